@@ -9,7 +9,7 @@ import (
 	"time"
 
 	"github.com/ericvolp12/bsky-experiments/pkg/search"
-	"github.com/meilisearch/meilisearch-go"
+	"github.com/ericvolp12/bsky-experiments/pkg/search/textsearch"
 )
 
 func main() {
@@ -25,9 +25,21 @@ func main() {
 		panic(err)
 	}
 
-	client := meilisearch.NewClient(meilisearch.ClientConfig{
-		Host: "http://localhost:7700",
-	})
+	// SEARCH_BACKEND picks where posts are indexed for search, "postgres" (default) or "meilisearch"
+	searcher, err := textsearch.New(
+		os.Getenv("SEARCH_BACKEND"),
+		postRegistry,
+		os.Getenv("MEILI_ADDRESS"),
+		os.Getenv("MEILI_API_KEY"),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	err = textsearch.SetupIndex(ctx, searcher)
+	if err != nil {
+		panic(err)
+	}
 
 	// Define the shared offset
 	offset := int32(0)
@@ -61,7 +73,7 @@ func main() {
 
 				log.Printf("indexing %d posts...", len(posts))
 
-				ti, err := client.Index("posts").UpdateDocuments(posts, "id")
+				err = searcher.IndexPosts(ctx, posts)
 				if err != nil {
 					log.Printf("error indexing posts: %v", err)
 					continue
@@ -79,7 +91,7 @@ func main() {
 					continue
 				}
 
-				log.Printf("...indexed %d posts: %d", len(posts), ti.TaskUID)
+				log.Printf("...indexed %d posts", len(posts))
 
 				if localOffset%100000 == 0 {
 					log.Printf("PROGRESS:\tindexed %d posts\n", localOffset)
@@ -8,11 +8,19 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/ericvolp12/bsky-experiments/pkg/auth"
 	feedgenerator "github.com/ericvolp12/bsky-experiments/pkg/feed-generator"
+	"github.com/ericvolp12/bsky-experiments/pkg/feed-generator/activeusers"
+	"github.com/ericvolp12/bsky-experiments/pkg/feed-generator/analytics"
 	"github.com/ericvolp12/bsky-experiments/pkg/feed-generator/endpoints"
+	"github.com/ericvolp12/bsky-experiments/pkg/feed-generator/experiments"
+	"github.com/ericvolp12/bsky-experiments/pkg/feed-generator/modlists"
+	"github.com/ericvolp12/bsky-experiments/pkg/feeds/alttext"
 	"github.com/ericvolp12/bsky-experiments/pkg/feeds/authorlabel"
 	"github.com/ericvolp12/bsky-experiments/pkg/feeds/bangers"
 	"github.com/ericvolp12/bsky-experiments/pkg/feeds/cluster"
@@ -24,7 +32,10 @@ import (
 	"github.com/gin-contrib/cors"
 	ginzap "github.com/gin-contrib/zap"
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/extra/redisotel/v9"
+	"github.com/redis/go-redis/v9"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel"
 	"go.uber.org/zap"
 )
 
@@ -87,6 +98,37 @@ func main() {
 	}
 	defer postRegistry.Close()
 
+	redisAddress := os.Getenv("REDIS_ADDRESS")
+	if redisAddress == "" {
+		redisAddress = "localhost:6379"
+	}
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     redisAddress,
+		Password: "",
+		DB:       0,
+	})
+
+	// Enable tracing instrumentation.
+	if err := redisotel.InstrumentTracing(redisClient); err != nil {
+		log.Fatalf("failed to instrument redis with tracing: %+v\n", err)
+	}
+
+	// Enable metrics instrumentation.
+	if err := redisotel.InstrumentMetrics(redisClient); err != nil {
+		log.Fatalf("failed to instrument redis with metrics: %+v\n", err)
+	}
+
+	activeUsers, err := activeusers.NewActiveUsers(ctx, redisClient, "feedgen:active-users")
+	if err != nil {
+		log.Fatalf("failed to initialize active user counter: %+v\n", err)
+	}
+
+	feedAnalytics, err := analytics.NewAnalytics(ctx, redisClient, "feedgen:analytics", activeUsers, postRegistry)
+	if err != nil {
+		log.Fatalf("failed to initialize feed analytics: %+v\n", err)
+	}
+
 	feedActorDID := os.Getenv("FEED_ACTOR_DID")
 	if feedActorDID == "" {
 		log.Fatal("FEED_ACTOR_DID environment variable must be set")
@@ -116,11 +158,102 @@ func main() {
 		log.Fatalf("Failed to create FeedGenerator: %v", err)
 	}
 
-	endpoints, err := endpoints.NewEndpoints(feedGenerator, graphJSONUrl, postRegistry)
+	// Experiments are optional, feeds are served without variants if none are configured
+	var feedExperiments *experiments.Experiments
+	experimentsJSONPath := os.Getenv("EXPERIMENTS_JSON_PATH")
+	if experimentsJSONPath != "" {
+		feedExperiments, err = experiments.LoadExperimentsFromFile(experimentsJSONPath)
+		if err != nil {
+			log.Fatalf("Failed to load experiments: %v", err)
+		}
+		log.Printf("loaded %d feed experiments", len(feedExperiments.ExperimentsByFeed))
+	}
+
+	// Mod list filtering is optional, feeds are served unfiltered if it isn't enabled
+	var modLists *modlists.ModLists
+	if os.Getenv("MOD_LIST_FILTERING_ENABLED") == "true" {
+		modLists, err = modlists.NewModLists(ctx, redisClient, "feedgen:mod-lists", postRegistry)
+		if err != nil {
+			log.Fatalf("failed to initialize mod lists: %+v\n", err)
+		}
+		log.Printf("mod list filtering enabled")
+	}
+
+	endpoints, err := endpoints.NewEndpoints(feedGenerator, graphJSONUrl, postRegistry, activeUsers, feedAnalytics, feedExperiments, modLists)
 	if err != nil {
 		log.Fatalf("Failed to create Endpoints: %v", err)
 	}
 
+	// Language filtering is optional, the AppView forwards the user's content languages as Accept-Language
+	if os.Getenv("LANGUAGE_FILTERING_ENABLED") == "true" {
+		endpoints.LanguageFilteringEnabled = true
+		log.Printf("language filtering enabled")
+	}
+
+	// Cancelled on SIGINT or SIGTERM to shut down the server and the background routines
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		<-signals
+		log.Println("received shutdown signal, shutting down...")
+		cancel()
+	}()
+
+	backgroundWg := sync.WaitGroup{}
+
+	// Create a routine to refresh active user gauges every minute
+	backgroundWg.Add(1)
+	go func() {
+		defer backgroundWg.Done()
+		ticker := time.NewTicker(1 * time.Minute)
+		defer ticker.Stop()
+		tracer := otel.Tracer("feed-generator")
+		for {
+			ctx, span := tracer.Start(runCtx, "refreshActiveUserGauges")
+			err := endpoints.RefreshActiveUserGauges(ctx)
+			if err != nil {
+				log.Printf("Error refreshing active user gauges: %v", err)
+			}
+			span.End()
+			select {
+			case <-ticker.C:
+				continue
+			case <-runCtx.Done():
+				return
+			}
+		}
+	}()
+
+	// Create a routine to roll up feed analytics into the registry every 15 minutes
+	// Yesterday is rolled up as well so the final numbers for a day land after midnight
+	backgroundWg.Add(1)
+	go func() {
+		defer backgroundWg.Done()
+		ticker := time.NewTicker(15 * time.Minute)
+		defer ticker.Stop()
+		tracer := otel.Tracer("feed-generator")
+		for {
+			ctx, span := tracer.Start(runCtx, "rollupFeedAnalytics")
+			now := time.Now()
+			for _, day := range []time.Time{now.AddDate(0, 0, -1), now} {
+				err := feedAnalytics.Rollup(ctx, day)
+				if err != nil {
+					log.Printf("Error rolling up feed analytics: %v", err)
+				}
+			}
+			span.End()
+			select {
+			case <-ticker.C:
+				continue
+			case <-runCtx.Done():
+				return
+			}
+		}
+	}()
+
 	// Create a cluster feed
 	clustersFeed, clusterFeedAliases, err := cluster.NewClusterFeed(ctx, feedActorDID, postRegistry)
 	if err != nil {
@@ -156,6 +289,13 @@ func main() {
 	}
 	feedGenerator.AddFeed(bangersFeedAliases, bangersFeed)
 
+	// Create a feed of images missing alt text
+	altTextFeed, altTextFeedAliases, err := alttext.NewAltTextFeed(ctx, feedActorDID, postRegistry)
+	if err != nil {
+		log.Fatalf("Failed to create AltTextFeed: %v", err)
+	}
+	feedGenerator.AddFeed(altTextFeedAliases, altTextFeed)
+
 	router := gin.New()
 
 	router.Use(gin.Recovery())
@@ -191,6 +331,8 @@ func main() {
 					zap.String("feedName", c.GetString("feedName")),
 					zap.Int64("limit", c.GetInt64("limit")),
 					zap.String("cursor", c.GetString("cursor")),
+					zap.String("experiment", c.GetString("experiment")),
+					zap.String("experimentVariant", c.GetString("experimentVariant")),
 					zap.Duration("latency", latency),
 				)
 			}
@@ -278,17 +420,46 @@ func main() {
 	router.GET("/xrpc/app.bsky.feed.getFeedSkeleton", endpoints.GetFeedSkeleton)
 	router.GET("/xrpc/app.bsky.feed.describeFeedGenerator", endpoints.DescribeFeedGenerator)
 
+	if modLists != nil {
+		router.PUT("/mod_lists/subscribe", endpoints.SubscribeToModList)
+		router.PUT("/mod_lists/unsubscribe", endpoints.UnsubscribeFromModList)
+		router.GET("/mod_lists", endpoints.GetModListSubscriptions)
+	}
+
 	// API Key Auth Middleware
 	router.Use(auther.AuthenticateGinRequestViaAPIKey)
 	router.PUT("/assign_user_to_feed", endpoints.AssignUserToFeed)
 	router.PUT("/unassign_user_from_feed", endpoints.UnassignUserFromFeed)
 	router.GET("/feed_members", endpoints.GetFeedMembers)
+	router.GET("/admin/active_users", endpoints.GetActiveUsers)
+	router.GET("/admin/feeds/:name/stats", endpoints.GetFeedStats)
+	router.GET("/admin/experiments", endpoints.GetExperiments)
 
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%s", port),
+		Handler: router,
+	}
+
+	go func() {
+		<-runCtx.Done()
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer shutdownCancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Error shutting down server: %v", err)
+		}
+	}()
+
 	log.Printf("Starting server on port %s", port)
-	router.Run(fmt.Sprintf(":%s", port))
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatalf("Failed to run server: %v", err)
+	}
+
+	// Wait for the background routines to stop
+	backgroundWg.Wait()
+	log.Println("Exiting...")
 }
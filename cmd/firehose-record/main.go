@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/firehose"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// firehose-record captures raw frames from a relay so they can be replayed
+// through the graph builder with FIREHOSE_SOURCE pointed at the recording
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Trap SIGINT to stop recording cleanly.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	rawlog, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("failed to create logger: %+v\n", err)
+	}
+	defer func() {
+		err := rawlog.Sync()
+		if err != nil {
+			log.Printf("failed to sync logger on teardown: %+v", err.Error())
+		}
+	}()
+
+	log := rawlog.Sugar().With("source", "firehose_record")
+
+	relayURL := os.Getenv("RELAY_URL")
+	if relayURL == "" {
+		relayURL = "wss://bsky.social/xrpc/com.atproto.sync.subscribeRepos"
+	}
+
+	cursor := os.Getenv("CURSOR")
+	if cursor != "" {
+		relayURL = fmt.Sprintf("%s?cursor=%s", relayURL, cursor)
+	}
+
+	outputPath := os.Getenv("OUTPUT_PATH")
+	if outputPath == "" {
+		log.Fatal("OUTPUT_PATH environment variable is required")
+	}
+
+	// "file" writes a single length-prefixed frame file, "dir" writes one file per frame
+	outputFormat := os.Getenv("OUTPUT_FORMAT")
+	if outputFormat == "" {
+		outputFormat = "file"
+	}
+
+	duration := time.Hour
+	if durationString := os.Getenv("RECORD_DURATION"); durationString != "" {
+		duration, err = time.ParseDuration(durationString)
+		if err != nil {
+			log.Fatalf("failed to parse RECORD_DURATION: %+v\n", err)
+		}
+	}
+
+	var writeFrame func(index int64, frame []byte) error
+
+	switch outputFormat {
+	case "file":
+		f, err := os.Create(outputPath)
+		if err != nil {
+			log.Fatalf("failed to create output file: %+v\n", err)
+		}
+		defer f.Close()
+
+		buffered := bufio.NewWriter(f)
+		defer func() {
+			if err := buffered.Flush(); err != nil {
+				log.Errorf("failed to flush output file: %+v\n", err)
+			}
+		}()
+
+		frameWriter := firehose.NewFrameWriter(buffered)
+		writeFrame = func(index int64, frame []byte) error {
+			return frameWriter.WriteFrame(frame)
+		}
+	case "dir":
+		err := os.MkdirAll(outputPath, 0755)
+		if err != nil {
+			log.Fatalf("failed to create output directory: %+v\n", err)
+		}
+
+		writeFrame = func(index int64, frame []byte) error {
+			return os.WriteFile(filepath.Join(outputPath, firehose.FrameFileName(index)), frame, 0644)
+		}
+	default:
+		log.Fatalf("unknown OUTPUT_FORMAT %q, expected file or dir", outputFormat)
+	}
+
+	log.Infof("connecting to %s...", relayURL)
+	c, _, err := websocket.DefaultDialer.Dial(relayURL, nil)
+	if err != nil {
+		log.Fatalf("failed to connect to websocket: %+v\n", err)
+	}
+	defer c.Close()
+
+	// Stop recording after the duration or on signal by closing the websocket
+	go func() {
+		select {
+		case <-signals:
+			log.Info("stopping recording on signal")
+		case <-time.After(duration):
+			log.Infof("stopping recording after %v", duration)
+		case <-ctx.Done():
+		}
+		c.Close()
+	}()
+
+	log.Infof("recording frames to %s (%s) for %v...", outputPath, outputFormat, duration)
+
+	start := time.Now()
+	index := int64(0)
+	for {
+		messageType, frame, err := c.ReadMessage()
+		if err != nil {
+			log.Infof("websocket closed: %v", err)
+			break
+		}
+
+		if messageType != websocket.BinaryMessage {
+			continue
+		}
+
+		err = writeFrame(index, frame)
+		if err != nil {
+			log.Errorf("failed to write frame %d: %+v\n", index, err)
+			break
+		}
+
+		index++
+		if index%10000 == 0 {
+			log.Infof("recorded %d frames in %v", index, time.Since(start))
+		}
+	}
+
+	log.Infof("recorded %d frames in %v", index, time.Since(start))
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/graph"
+	"github.com/ericvolp12/bsky-experiments/pkg/persistedgraph"
+	"github.com/redis/go-redis/v9"
+)
+
+// follow-graph-export dumps the follow graph built by the graph builder
+// so it can be laid out by the Atlas next to the interaction graph
+func main() {
+	ctx := context.Background()
+	if len(os.Args) != 3 {
+		fmt.Println("Usage: go run main.go (bin|sqlite|text) outputfile")
+		return
+	}
+
+	format := os.Args[1]
+	outputFile := os.Args[2]
+
+	redisAddress := os.Getenv("REDIS_ADDRESS")
+	if redisAddress == "" {
+		redisAddress = "localhost:6379"
+	}
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     redisAddress,
+		Password: "",
+		DB:       0,
+	})
+
+	followGraph, err := persistedgraph.NewPersistedGraph(ctx, redisClient, "follow-graph")
+	if err != nil {
+		log.Fatalf("Error initializing follow graph: %v", err)
+	}
+
+	g, err := followGraph.ReadGraph(ctx)
+	if err != nil {
+		log.Fatalf("Error reading follow graph from Redis: %v", err)
+	}
+
+	fmt.Printf("Read follow graph with %d nodes and %d edges\n", g.GetNodeCount(), g.GetEdgeCount())
+
+	switch format {
+	case "bin":
+		rw := graph.BinaryGraphReaderWriter{}
+		err = rw.WriteGraph(ctx, g, outputFile)
+	case "sqlite":
+		rw, err := graph.NewSQLiteReaderWriter(outputFile)
+		if err != nil {
+			log.Fatalf("Error initializing SQLiteReaderWriter: %v", err)
+		}
+		defer rw.DB.Close()
+		err = rw.WriteGraph(ctx, g)
+		if err != nil {
+			log.Fatalf("Error writing follow graph: %v", err)
+		}
+	case "text":
+		err = g.WriteGraph(outputFile)
+	default:
+		log.Fatalf("Unknown format %q, expected bin, sqlite, or text", format)
+	}
+	if err != nil {
+		log.Fatalf("Error writing follow graph: %v", err)
+	}
+
+	fmt.Printf("Follow graph successfully written to %s\n", outputFile)
+}
@@ -2,13 +2,14 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"math/rand"
 	"net/http"
-	"net/url"
 	"os"
 	"os/signal"
+	"strconv"
 	"sync"
 	"syscall"
 	"time"
@@ -17,9 +18,9 @@ import (
 
 	"github.com/bluesky-social/indigo/events"
 	intEvents "github.com/ericvolp12/bsky-experiments/pkg/events"
+	"github.com/ericvolp12/bsky-experiments/pkg/firehose"
 	"github.com/ericvolp12/bsky-experiments/pkg/persistedgraph"
 	"github.com/ericvolp12/bsky-experiments/pkg/tracing"
-	"github.com/gorilla/websocket"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
@@ -83,13 +84,25 @@ func main() {
 		}()
 	}
 
-	// Replace with the WebSocket URL you want to connect to.
-	u := url.URL{Scheme: "wss", Host: "bsky.social", Path: "/xrpc/com.atproto.sync.subscribeRepos"}
+	// The firehose source can be a relay websocket URL, a recorded frame file, or a directory of frames
+	firehoseSource := os.Getenv("FIREHOSE_SOURCE")
+	if firehoseSource == "" {
+		firehoseSource = "wss://bsky.social/xrpc/com.atproto.sync.subscribeRepos"
+	}
+
+	source, err := firehose.NewSource(firehoseSource)
+	if err != nil {
+		log.Fatalf("failed to initialize firehose source: %+v\n", err)
+	}
 
 	includeLinks := os.Getenv("INCLUDE_LINKS") == "true"
 
 	workerCount := 20
 
+	// Image URLs are built from this template if set (i.e. "https://cdn.example.com/img/{preset}/plain/{did}/{cid}@jpeg")
+	// otherwise images are linked straight from the author's PDS
+	imageCDNTemplate := os.Getenv("IMAGE_CDN_URL_TEMPLATE")
+
 	postRegistryEnabled := false
 	dbConnectionString := os.Getenv("REGISTRY_DB_CONNECTION_STRING")
 	if dbConnectionString != "" {
@@ -128,14 +141,55 @@ func main() {
 		log.Fatalf("failed to initialize persisted graph: %+v\n", err)
 	}
 
+	followGraph, err := persistedgraph.NewPersistedGraph(ctx, redisClient, "follow-graph")
+	if err != nil {
+		log.Fatalf("failed to initialize follow graph: %+v\n", err)
+	}
+
+	// Graphs persisted before the adjacency sets existed are migrated by running once with BUILD_ADJACENCY_INDEX=true
+	buildAdjacencyIndex := os.Getenv("BUILD_ADJACENCY_INDEX") == "true"
+	for _, g := range []*persistedgraph.PersistedGraph{redisGraph, followGraph} {
+		if buildAdjacencyIndex {
+			log.Infof("building adjacency index for %s...", g.Prefix)
+			err = g.BuildAdjacencyIndex(ctx)
+			if err != nil {
+				log.Fatalf("failed to build adjacency index for %s: %+v\n", g.Prefix, err)
+			}
+			continue
+		}
+
+		indexed, err := g.IsAdjacencyIndexed(ctx)
+		if err != nil {
+			log.Fatalf("failed to check adjacency index for %s: %+v\n", g.Prefix, err)
+		}
+		if !indexed {
+			log.Warnf("adjacency index for %s has not been built, run with BUILD_ADJACENCY_INDEX=true to build it", g.Prefix)
+		}
+	}
+
+	// Replay mode rewinds the persisted cursor so the stream is reprocessed from the given sequence number
+	replayFromCursor := os.Getenv("REPLAY_FROM_CURSOR")
+	if replayFromCursor != "" {
+		if _, err := strconv.ParseInt(replayFromCursor, 10, 64); err != nil {
+			log.Fatalf("REPLAY_FROM_CURSOR must be a sequence number: %+v\n", err)
+		}
+		log.Infof("replaying from cursor %s (was %s)", replayFromCursor, redisGraph.GetCursor(ctx))
+		err = redisGraph.SetCursor(ctx, replayFromCursor)
+		if err != nil {
+			log.Fatalf("failed to set replay cursor: %+v\n", err)
+		}
+	}
+
 	log.Info("initializing BSky Event Handler...")
 	bsky, err := intEvents.NewBSky(
 		ctx,
 		includeLinks, postRegistryEnabled,
 		dbConnectionString,
 		redisGraph,
+		followGraph,
 		redisClient,
 		workerCount,
+		imageCDNTemplate,
 	)
 	if err != nil {
 		log.Fatal(err)
@@ -172,6 +226,65 @@ func main() {
 			}
 		})
 
+		// Create a handler to write out the plaintext follow graph
+		http.HandleFunc("/follow-graph", func(w http.ResponseWriter, r *http.Request) {
+			log.Info("writing follow graph to HTTP Response...")
+
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("Content-Disposition", "attachment; filename=follow-graph.txt")
+			w.Header().Set("Content-Transfer-Encoding", "binary")
+			w.Header().Set("Expires", "0")
+			w.Header().Set("Cache-Control", "must-revalidate")
+			w.Header().Set("Pragma", "public")
+
+			err := bsky.FollowGraph.Write(ctx, w)
+			if err != nil {
+				log.Errorf("error writing follow graph: %s", err)
+			} else {
+				log.Info("follow graph written to HTTP Response successfully")
+			}
+		})
+
+		// Create a handler to write out indexed profiles as JSON lines so graph exports can be
+		// joined by DID to show display names and avatars
+		http.HandleFunc("/profiles", func(w http.ResponseWriter, r *http.Request) {
+			if !bsky.PostRegistryEnabled {
+				http.Error(w, "profiles require the post registry to be enabled", http.StatusNotFound)
+				return
+			}
+
+			log.Info("writing profiles to HTTP Response...")
+
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.Header().Set("Content-Disposition", "attachment; filename=profiles.jsonl")
+
+			encoder := json.NewEncoder(w)
+			cursor := ""
+			count := 0
+			for {
+				profiles, err := bsky.PostRegistry.GetAuthorProfilePage(ctx, cursor, 10000)
+				if err != nil {
+					log.Errorf("error getting profile page: %s", err)
+					return
+				}
+
+				for _, profile := range profiles {
+					if err := encoder.Encode(profile); err != nil {
+						log.Errorf("error writing profile: %s", err)
+						return
+					}
+				}
+
+				count += len(profiles)
+				if len(profiles) < 10000 {
+					break
+				}
+				cursor = profiles[len(profiles)-1].DID
+			}
+
+			log.Infof("%d profiles written to HTTP Response successfully", count)
+		})
+
 		http.Handle("/metrics", promhttp.Handler())
 		log.Info(http.ListenAndServe("0.0.0.0:6060", nil))
 	}()
@@ -180,16 +293,27 @@ func main() {
 
 	// Run a routine that handles the events from the WebSocket
 	log.Info("starting repo sync routine...")
-	err = handleRepoStreamWithRetry(ctx, bsky, log, u, &intEvents.RepoStreamCtxCallbacks{
-		RepoCommit: bsky.HandleRepoCommit,
-		RepoInfo:   intEvents.HandleRepoInfo,
-		Error:      intEvents.HandleError,
+	err = handleRepoStreamWithRetry(ctx, bsky, log, source, &intEvents.RepoStreamCtxCallbacks{
+		RepoCommit:    bsky.HandleRepoCommit,
+		RepoHandle:    bsky.HandleRepoHandle,
+		RepoInfo:      intEvents.HandleRepoInfo,
+		RepoMigrate:   bsky.HandleRepoMigrate,
+		RepoTombstone: bsky.HandleRepoTombstone,
+		Error:         intEvents.HandleError,
 	})
 
 	if err != nil {
 		log.Errorf("Error: %v", err)
 	}
 
+	if bsky.BatchWriter != nil {
+		log.Info("flushing batch writer...")
+		err = bsky.BatchWriter.Close(context.Background())
+		if err != nil {
+			log.Errorf("failed to flush batch writer: %+v", err)
+		}
+	}
+
 	log.Info("waiting for routines to finish...")
 	close(quit)
 	wg.Wait()
@@ -208,7 +332,7 @@ func handleRepoStreamWithRetry(
 	ctx context.Context,
 	bsky *intEvents.BSky,
 	log *zap.SugaredLogger,
-	u url.URL,
+	source firehose.Source,
 	callbacks *intEvents.RepoStreamCtxCallbacks,
 ) error {
 	var backoff time.Duration
@@ -243,42 +367,42 @@ func handleRepoStreamWithRetry(
 
 	for {
 		// Try to read the seq number from Redis
+		seq := int64(0)
 		cursor := bsky.PersistedGraph.GetCursor(ctx)
 		if cursor != "" {
 			log.Infof("found cursor in redis: %s", cursor)
-			u.RawQuery = fmt.Sprintf("cursor=%s", cursor)
-		}
 
-		log.Info("connecting to BSky WebSocket...")
-		c, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
-		if err != nil {
-			log.Infof("failed to connect to websocket: %v", err)
-			continue
-		}
-		defer c.Close()
-
-		go func() {
-			select {
-			case <-streamCtx.Done():
-				log.Info("closing websocket...")
-				c.Close()
+			// Events after the cursor will be replayed, so don't count them as gaps
+			var err error
+			seq, err = strconv.ParseInt(cursor, 10, 64)
+			if err != nil {
+				return fmt.Errorf("failed to parse cursor (%s): %w", cursor, err)
 			}
-		}()
+			bsky.CursorTracker.Reset(seq)
+		}
 
-		pool := events.NewConsumerPool(16, 32, func(ctx context.Context, xe *events.XRPCStreamEvent) error {
+		// Events are released from the cursor tracker once their callbacks return
+		pool := events.NewConsumerPool(16, 32, bsky.CursorTracker.Wrap(func(ctx context.Context, xe *events.XRPCStreamEvent) error {
 			switch {
 			case xe.RepoCommit != nil:
 				callbacks.RepoCommit(ctx, xe.RepoCommit)
+			case xe.RepoHandle != nil:
+				callbacks.RepoHandle(ctx, xe.RepoHandle)
 			case xe.RepoInfo != nil:
 				callbacks.RepoInfo(ctx, xe.RepoInfo)
+			case xe.RepoMigrate != nil:
+				callbacks.RepoMigrate(ctx, xe.RepoMigrate)
+			case xe.RepoTombstone != nil:
+				callbacks.RepoTombstone(ctx, xe.RepoTombstone)
 			case xe.Error != nil:
 				callbacks.Error(ctx, xe.Error)
 			}
 			return nil
-		})
+		}))
 
-		err = events.HandleRepoStream(streamCtx, c, pool)
-		log.Info("HandleRepoStream returned unexpectedly: %w...", err)
+		log.Infof("streaming events from %s...", source.Name())
+		err := source.Stream(streamCtx, seq, bsky.CursorTracker.Scheduler(pool))
+		log.Infof("firehose stream returned: %v", err)
 		if err != nil {
 			log.Infof("Error in event handler routine: %v", err)
 			backoff = getNextBackoff(backoff)
@@ -294,6 +418,20 @@ func handleRepoStreamWithRetry(
 				return ctx.Err()
 			}
 		}
+
+		// A finite source has been read to the end, let the workers finish before committing the final cursor
+		log.Info("firehose source exhausted, waiting for in-flight events...")
+		err = bsky.CursorTracker.Wait(ctx)
+		if err != nil {
+			return fmt.Errorf("failed waiting for in-flight events: %w", err)
+		}
+
+		err = bsky.CursorTracker.Commit(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to commit final cursor: %w", err)
+		}
+		log.Infof("committed final cursor %d", bsky.CursorTracker.LowWaterMark())
+
 		return nil
 	}
 }
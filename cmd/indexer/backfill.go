@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// insertedAtBackfillBatchSize is small enough that each batch only holds row locks on posts briefly
+const insertedAtBackfillBatchSize = 5000
+
+// BackfillPostsInsertedAt stamps the posts written before inserted_at existed, a batch at a time,
+// until every post has been visited or the context is cancelled
+func (indexer *Indexer) BackfillPostsInsertedAt(ctx context.Context) {
+	log := indexer.Logger.With("source", "inserted_at_backfill")
+	log.Info("backfilling posts inserted_at...")
+
+	cursor := ""
+	batches := 0
+	for {
+		nextCursor, err := indexer.PostRegistry.BackfillPostsInsertedAt(ctx, cursor, insertedAtBackfillBatchSize)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Errorf("error backfilling posts inserted_at after %q, retrying: %+v", cursor, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(5 * time.Second):
+			}
+			continue
+		}
+
+		if nextCursor == "" {
+			log.Infow("finished backfilling posts inserted_at", "batches", batches)
+			return
+		}
+
+		cursor = nextCursor
+		batches++
+		if batches%100 == 0 {
+			log.Infow("backfilling posts inserted_at", "batches", batches, "cursor", cursor)
+		}
+	}
+}
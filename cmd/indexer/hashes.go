@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/imagehash"
+	"github.com/ericvolp12/bsky-experiments/pkg/search"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var imagesHashedCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "indexer_hashed_images",
+	Help: "The total number of image thumbnails hashed by result",
+}, []string{"result"})
+
+// HashImages computes perceptual hashes for the thumbnails of new images
+// and labels the posts of images that turn out to be reposts of images seen before
+func (indexer *Indexer) HashImages(ctx context.Context) {
+	tracer := otel.Tracer("ImageHasher")
+	ctx, span := tracer.Start(ctx, "HashImages")
+	defer span.End()
+
+	log := indexer.Logger.With("source", "image_hasher")
+	start := time.Now()
+
+	unhashedImages, err := indexer.PostRegistry.GetUnhashedImages(ctx, 100)
+	if err != nil {
+		if errors.As(err, &search.NotFoundError{}) {
+			log.Debug("No unhashed images found, skipping hash cycle...")
+		} else {
+			log.Errorf("Failed to get unhashed images, skipping hash cycle: %v", err)
+		}
+		return
+	}
+
+	// The same image can be attached to many posts, it only needs to be hashed once
+	cids := []string{}
+	thumbnailsByCID := map[string]string{}
+	for _, image := range unhashedImages {
+		if _, ok := thumbnailsByCID[image.CID]; !ok {
+			cids = append(cids, image.CID)
+			thumbnailsByCID[image.CID] = image.ThumbnailURL
+		}
+	}
+
+	hashedCount := 0
+	labelCount := 0
+
+	for _, cid := range cids {
+		img, err := imagehash.Fetch(ctx, indexer.HTTPClient, thumbnailsByCID[cid])
+		if err != nil {
+			// Images that are gone or broken are recorded without hashes so they aren't fetched again
+			var statusErr *imagehash.StatusError
+			permanent := errors.Is(err, imagehash.ErrDecode) ||
+				(errors.As(err, &statusErr) && statusErr.StatusCode >= 400 && statusErr.StatusCode < 500 && statusErr.StatusCode != http.StatusTooManyRequests)
+			if !permanent {
+				log.Warnf("Failed to fetch thumbnail for image %s, will retry: %v", cid, err)
+				imagesHashedCounter.WithLabelValues("retry").Inc()
+				continue
+			}
+
+			log.Infof("Failed to hash thumbnail for image %s: %v", cid, err)
+			imagesHashedCounter.WithLabelValues("failed").Inc()
+			err = indexer.PostRegistry.AddImageHash(ctx, cid, nil, time.Now())
+			if err != nil {
+				log.Errorf("Failed to record unhashable image: %v", err)
+			}
+			continue
+		}
+
+		hashes := imagehash.Compute(img)
+		err = indexer.PostRegistry.AddImageHash(ctx, cid, &hashes, time.Now())
+		if err != nil {
+			log.Errorf("Failed to add image hash: %v", err)
+			continue
+		}
+		hashedCount++
+
+		// Flat images all hash alike, so they aren't matched against other images
+		if imagehash.LowEntropy(hashes.PHash) {
+			imagesHashedCounter.WithLabelValues("low_entropy").Inc()
+			continue
+		}
+		imagesHashedCounter.WithLabelValues("hashed").Inc()
+
+		labels, err := indexer.PostRegistry.LabelDuplicateImages(ctx, cid, time.Now())
+		if err != nil {
+			log.Errorf("Failed to label duplicate images: %v", err)
+			continue
+		}
+		labelCount += labels
+	}
+
+	span.SetAttributes(
+		attribute.Int("image_count", len(cids)),
+		attribute.Int("hashed_image_count", hashedCount),
+		attribute.Int("label_count", labelCount),
+	)
+
+	log.Infow("Finished hashing images...",
+		"image_count", len(cids),
+		"hashed_image_count", hashedCount,
+		"label_count", labelCount,
+		"processing_time", time.Since(start),
+	)
+}
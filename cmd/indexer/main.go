@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -13,14 +12,14 @@ import (
 	"syscall"
 	"time"
 
-	objectdetection "github.com/ericvolp12/bsky-experiments/pkg/object-detection"
+	"github.com/ericvolp12/bsky-experiments/pkg/classifier"
 	"github.com/ericvolp12/bsky-experiments/pkg/search"
-	"github.com/ericvolp12/bsky-experiments/pkg/sentiment"
+	"github.com/ericvolp12/bsky-experiments/pkg/search/textsearch"
 	"github.com/ericvolp12/bsky-experiments/pkg/tracing"
-	"github.com/meilisearch/meilisearch-go"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
@@ -28,13 +27,9 @@ import (
 
 type Indexer struct {
 	PostRegistry *search.PostRegistry
-	MeiliClient  *meilisearch.Client
-	Detection    *objectdetection.ObjectDetectionImpl
-	Sentiment    *sentiment.Sentiment
+	Searcher     textsearch.Searcher
+	HTTPClient   *http.Client
 	Logger       *zap.SugaredLogger
-
-	PositiveConfidenceThreshold float64
-	NegativeConfidenceThreshold float64
 }
 
 func main() {
@@ -67,11 +62,6 @@ func main() {
 		log.Fatal("REGISTRY_DB_CONNECTION_STRING environment variable is required")
 	}
 
-	// meiliAddress := os.Getenv("MEILI_ADDRESS")
-	// if meiliAddress == "" {
-	// 	log.Fatal("MEILI_ADDRESS environment variable is required")
-	// }
-
 	// Registers a tracer Provider globally if the exporter endpoint is set
 	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" {
 		log.Info("initializing tracer...")
@@ -92,23 +82,35 @@ func main() {
 	}
 	defer postRegistry.Close()
 
-	objectDetectionServiceHost := os.Getenv("OBJECT_DETECTION_SERVICE_HOST")
-	if objectDetectionServiceHost == "" {
-		log.Fatal("OBJECT_DETECTION_SERVICE_HOST environment variable is required")
+	// SEARCH_BACKEND picks where posts are indexed for search, "postgres" (default) or "meilisearch"
+	searcher, err := textsearch.New(
+		os.Getenv("SEARCH_BACKEND"),
+		postRegistry,
+		os.Getenv("MEILI_ADDRESS"),
+		os.Getenv("MEILI_API_KEY"),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create searcher: %v", err)
 	}
 
-	detection := objectdetection.NewObjectDetection(objectDetectionServiceHost)
-
-	// meiliClient := meilisearch.NewClient(meilisearch.ClientConfig{
-	// 	Host: meiliAddress,
-	// })
+	err = textsearch.SetupIndex(ctx, searcher)
+	if err != nil {
+		log.Fatalf("Failed to set up search index: %v", err)
+	}
 
-	sentimentServiceHost := os.Getenv("SENTIMENT_SERVICE_HOST")
-	if sentimentServiceHost == "" {
-		log.Fatal("SENTIMENT_SERVICE_HOST environment variable is required")
+	// CLASSIFIERS is a comma separated list of the registered classifiers to run over new posts
+	// The "objects" classifier needs OBJECT_DETECTION_SERVICE_HOST and
+	// the "topic" classifier needs TOPIC_RULES_PATH pointing at a JSON file of topic rules
+	classifiers := os.Getenv("CLASSIFIERS")
+	if classifiers == "" {
+		classifiers = "sentiment,language,objects"
 	}
 
-	sentiment := sentiment.NewSentiment(sentimentServiceHost)
+	pipeline := classifier.NewPipeline(postRegistry, log.With("source", "classifier_pipeline"))
+	err = pipeline.Enable(ctx, strings.Split(classifiers, ","))
+	if err != nil {
+		log.Fatalf("Failed to set up classifier pipeline: %v", err)
+	}
 
 	// Start up a Metrics and Profiling goroutine
 	go func() {
@@ -118,14 +120,17 @@ func main() {
 		log.Info(http.ListenAndServe("0.0.0.0:8094", nil))
 	}()
 
+	// Thumbnails are fetched from their stored URLs (the author's PDS or the image CDN) to be hashed
+	httpClient := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: otelhttp.NewTransport(http.DefaultTransport),
+	}
+
 	indexer := &Indexer{
 		PostRegistry: postRegistry,
-		// MeiliClient:                 meiliClient,
-		Detection:                   detection,
-		Sentiment:                   sentiment,
-		Logger:                      log,
-		PositiveConfidenceThreshold: 0.65,
-		NegativeConfidenceThreshold: 0.65,
+		Searcher:     searcher,
+		HTTPClient:   httpClient,
+		Logger:       log,
 	}
 
 	// Create a cancellable context
@@ -143,27 +148,44 @@ func main() {
 		cancel()
 	}()
 
-	// Start the Image Processing loop
 	wg := sync.WaitGroup{}
+
+	// Start the Image Hashing loop
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
 		for {
-			indexer.ProcessImages(ctx)
+			indexer.HashImages(ctx)
 			select {
 			case <-ctx.Done():
 				log.Info("Context cancelled, exiting...")
 				return
 			default:
-				time.Sleep(1 * time.Second)
+				time.Sleep(5 * time.Second)
 			}
 		}
 	}()
 
-	// Start the MeiliSearch index loop
+	// Posts written before inserted_at existed are skipped by classifiers until they're stamped
+	if os.Getenv("BACKFILL_POSTS_INSERTED_AT") == "true" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			indexer.BackfillPostsInsertedAt(ctx)
+		}()
+	}
+
+	// Start the classifier pipeline
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		pipeline.Run(ctx)
+	}()
+
+	// Start the search index loop
 	wg.Add(1)
 	go func() {
-		log = log.With("source", "meilisearch_indexer")
+		log = log.With("source", "search_indexer")
 		defer wg.Done()
 		for {
 			indexer.IndexPosts(ctx)
@@ -184,32 +206,17 @@ func main() {
 	log.Info("Exiting...")
 }
 
-var postsAnalyzedCounter = promauto.NewCounter(prometheus.CounterOpts{
-	Name: "indexer_analyzed_posts",
-	Help: "The total number of posts analyzed",
-})
-
 var postsIndexedCounter = promauto.NewCounter(prometheus.CounterOpts{
 	Name: "indexer_indexed_posts",
 	Help: "The total number of posts indexed",
 })
 
-var positiveSentimentCounter = promauto.NewCounter(prometheus.CounterOpts{
-	Name: "indexer_positive_sentiment",
-	Help: "The total number of posts with positive sentiment",
-})
-
-var negativeSentimentCounter = promauto.NewCounter(prometheus.CounterOpts{
-	Name: "indexer_negative_sentiment",
-	Help: "The total number of posts with negative sentiment",
-})
-
 func (indexer *Indexer) IndexPosts(ctx context.Context) {
-	tracer := otel.Tracer("MeilisearchIndexer")
+	tracer := otel.Tracer("SearchIndexer")
 	ctx, span := tracer.Start(ctx, "IndexPosts")
 	defer span.End()
 
-	log := indexer.Logger.With("source", "meilisearch_indexer")
+	log := indexer.Logger.With("source", "search_indexer")
 	log.Info("index loop waking up...")
 	start := time.Now()
 	log.Info("getting unindexed posts...")
@@ -226,55 +233,15 @@ func (indexer *Indexer) IndexPosts(ctx context.Context) {
 
 	fetchDone := time.Now()
 
-	log.Info("submtting posts for Sentiment Analysis...")
-	// If sentiment is enabled, get the sentiment for the post
-	span.AddEvent("GetPostsSentiment")
-	sentimentResults, err := indexer.Sentiment.GetPostsSentiment(ctx, posts)
-	if err != nil {
-		span.SetAttributes(attribute.String("sentiment.error", err.Error()))
-		log.Errorf("error getting sentiment for posts: %+v\n", err)
-	}
-	sentimentDone := time.Now()
-
-	log.Info("setting sentiment results...")
-	errs := indexer.PostRegistry.SetSentimentResults(ctx, sentimentResults)
-	if len(errs) > 0 {
-		log.Errorf("error(s) setting sentiment results: %+v\n", errs)
-	}
-
-	postIDsToLabel := make([]string, 0)
-	authorDIDsToLabel := make([]string, 0)
-	labels := make([]string, 0)
-	postsAnalyzed := 0
-
-	for i := range sentimentResults {
-		post := sentimentResults[i]
-		if post != nil {
-			if post.Sentiment != nil && post.SentimentConfidence != nil {
-				postsAnalyzed++
-				postsAnalyzedCounter.Inc()
-				if strings.Contains(*post.Sentiment, "p") && *post.SentimentConfidence >= indexer.PositiveConfidenceThreshold {
-					postIDsToLabel = append(postIDsToLabel, post.ID)
-					authorDIDsToLabel = append(authorDIDsToLabel, post.AuthorDID)
-					labels = append(labels, "sentiment:pos")
-					positiveSentimentCounter.Inc()
-				} else if strings.Contains(*post.Sentiment, "n") && *post.SentimentConfidence >= indexer.NegativeConfidenceThreshold {
-					postIDsToLabel = append(postIDsToLabel, post.ID)
-					authorDIDsToLabel = append(authorDIDsToLabel, post.AuthorDID)
-					labels = append(labels, "sentiment:neg")
-					negativeSentimentCounter.Inc()
-				}
-			}
-		}
-	}
-
-	log.Infof("setting %d sentiment labels...", len(labels))
-	err = indexer.PostRegistry.AddOneLabelPerPost(ctx, labels, postIDsToLabel, authorDIDsToLabel)
+	// Index the posts for search, if this fails they're left unindexed to be retried
+	log.Infof("indexing %d posts for search...", len(posts))
+	err = indexer.Searcher.IndexPosts(ctx, posts)
 	if err != nil {
-		log.Errorf("error setting sentiment labels: %+v\n", err)
+		log.Errorf("error indexing posts for search: %+v\n", err)
+		return
 	}
 
-	appliedSentimentDone := time.Now()
+	searchIndexDone := time.Now()
 
 	// Set indexed at timestamp on posts
 	postIds := make([]string, len(posts))
@@ -295,122 +262,17 @@ func (indexer *Indexer) IndexPosts(ctx context.Context) {
 
 	span.SetAttributes(
 		attribute.Int("posts_indexed", len(posts)),
-		attribute.Int("posts_analyzed", postsAnalyzed),
-		attribute.Int("posts_labeled_with_sentiment", len(postIDsToLabel)),
 		attribute.String("indexing_time", time.Since(start).String()),
 		attribute.String("fetch_time", fetchDone.Sub(start).String()),
-		attribute.String("index_time", appliedSentimentDone.Sub(fetchDone).String()),
-		attribute.String("update_time", updateDone.Sub(appliedSentimentDone).String()),
-		attribute.String("sentiment_time", sentimentDone.Sub(fetchDone).String()),
-		attribute.String("sentiment_db_time", appliedSentimentDone.Sub(sentimentDone).String()),
+		attribute.String("search_index_time", searchIndexDone.Sub(fetchDone).String()),
+		attribute.String("update_time", updateDone.Sub(searchIndexDone).String()),
 	)
 
 	log.Infow("finished indexing posts, sleeping...",
 		"posts_indexed", len(posts),
-		"posts_analyzed", postsAnalyzed,
-		"posts_labeled_with_sentiment", len(postIDsToLabel),
 		"indexing_time", time.Since(start),
 		"fetch_time", fetchDone.Sub(start),
-		"index_time", appliedSentimentDone.Sub(fetchDone),
-		"update_time", updateDone.Sub(appliedSentimentDone),
-		"sentiment_time", sentimentDone.Sub(fetchDone),
-		"sentiment_db_time", appliedSentimentDone.Sub(sentimentDone),
-	)
-}
-
-func (indexer *Indexer) ProcessImages(ctx context.Context) {
-	tracer := otel.Tracer("ImageProcessor")
-	ctx, span := tracer.Start(ctx, "ProcessImages")
-	defer span.End()
-
-	log := indexer.Logger.With("source", "image_processor")
-	log.Info("Processing images...")
-	start := time.Now()
-	unprocessedImages, err := indexer.PostRegistry.GetUnprocessedImages(ctx, 50)
-	if err != nil {
-		if errors.As(err, &search.NotFoundError{}) {
-			log.Info("No unprocessed images found, skipping process cycle...")
-
-		} else {
-			log.Errorf("Failed to get unprocessed images, skipping process cycle: %v", err)
-		}
-		return
-	}
-
-	if len(unprocessedImages) == 0 {
-		log.Info("No unprocessed images found, skipping process cycle...")
-		return
-	}
-
-	imageMetas := make([]*objectdetection.ImageMeta, len(unprocessedImages))
-	for i, image := range unprocessedImages {
-		imageMetas[i] = &objectdetection.ImageMeta{
-			CID:       image.CID,
-			URL:       image.FullsizeURL,
-			MimeType:  image.MimeType,
-			CreatedAt: image.CreatedAt,
-		}
-	}
-
-	results, err := indexer.Detection.ProcessImages(ctx, imageMetas)
-	if err != nil {
-		log.Errorf("Failed to process images: %v", err)
-		return
-	}
-
-	executionTime := time.Now()
-
-	successCount := 0
-
-	for idx, result := range results {
-		if len(result.Results) > 0 {
-			successCount++
-		}
-
-		cvClasses, err := json.Marshal(result.Results)
-		if err != nil {
-			log.Errorf("Failed to marshal classes: %v", err)
-			continue
-		}
-
-		err = indexer.PostRegistry.AddCVDataToImage(
-			ctx,
-			result.Meta.CID,
-			unprocessedImages[idx].PostID,
-			executionTime,
-			cvClasses,
-		)
-		if err != nil {
-			log.Errorf("Failed to update image: %v", err)
-			continue
-		}
-
-		imageLabels := []string{}
-		for _, class := range result.Results {
-			if class.Confidence >= 0.75 {
-				imageLabels = append(imageLabels, class.Label)
-			}
-		}
-
-		for _, label := range imageLabels {
-			postLabel := fmt.Sprintf("%s:%s", "cv", label)
-			err = indexer.PostRegistry.AddPostLabel(ctx, unprocessedImages[idx].PostID, unprocessedImages[idx].AuthorDID, postLabel)
-			if err != nil {
-				log.Errorf("Failed to add label to post: %v", err)
-				continue
-			}
-		}
-	}
-
-	span.SetAttributes(
-		attribute.Int("batch_size", len(unprocessedImages)),
-		attribute.Int("successful_image_count", successCount),
-		attribute.String("processing_time", time.Since(start).String()),
-	)
-
-	log.Infow("Finished processing images...",
-		"batch_size", len(unprocessedImages),
-		"successfully_processed_image_count", successCount,
-		"processing_time", time.Since(start),
+		"search_index_time", searchIndexDone.Sub(fetchDone),
+		"update_time", updateDone.Sub(searchIndexDone),
 	)
 }
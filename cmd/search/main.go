@@ -10,6 +10,8 @@ import (
 
 	"github.com/ericvolp12/bsky-experiments/pkg/search"
 	"github.com/ericvolp12/bsky-experiments/pkg/search/endpoints"
+	"github.com/ericvolp12/bsky-experiments/pkg/search/healthcheck"
+	"github.com/ericvolp12/bsky-experiments/pkg/search/textsearch"
 	"github.com/ericvolp12/bsky-experiments/pkg/tracing"
 	"github.com/ericvolp12/bsky-experiments/pkg/usercount"
 	intXRPC "github.com/ericvolp12/bsky-experiments/pkg/xrpc"
@@ -87,6 +89,17 @@ func main() {
 
 	userCount := usercount.NewUserCount(ctx, client)
 
+	// SEARCH_BACKEND picks where post search runs, "postgres" (default) or "meilisearch"
+	searcher, err := textsearch.New(
+		os.Getenv("SEARCH_BACKEND"),
+		postRegistry,
+		os.Getenv("MEILI_ADDRESS"),
+		os.Getenv("MEILI_API_KEY"),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create searcher: %v", err)
+	}
+
 	api, err := endpoints.NewAPI(
 		postRegistry,
 		userCount,
@@ -95,6 +108,7 @@ func main() {
 		1*time.Minute, // Thread View Cache TTL
 		1*time.Minute, // Layout Cache TTL
 		5*time.Minute, // Stats Cache TTL
+		searcher,
 	)
 
 	router := gin.New()
@@ -165,6 +179,7 @@ func main() {
 	router.GET("/thread", api.ProcessThreadRequest)
 	router.GET("/stats", api.GetAuthorStats)
 	router.GET("/post/:id", api.GetPost)
+	router.GET("/post/:id/reposts", api.GetRepostsForPost)
 
 	router.GET("/opted_out_authors", api.GetOptedOutAuthors)
 	router.POST("/opt_out", api.GraphOptOut)
@@ -173,6 +188,28 @@ func main() {
 	router.GET("/clusters", api.GetClusterList)
 	router.GET("/users/by_handle/:handle/cluster", api.GetClusterForHandle)
 	router.GET("/users/by_did/:did/cluster", api.GetClusterForDID)
+	router.GET("/users/by_handle/:handle/profile", api.GetProfileForHandle)
+	router.GET("/users/by_did/:did/profile", api.GetProfileForDID)
+	router.GET("/users/by_did/:did/follows", api.GetFollowCountsForDID)
+	router.GET("/users/by_did/:did/mutuals", api.GetMutualFollowsForDID)
+	router.GET("/users/by_did/:did/lists", api.GetListsForDID)
+	router.GET("/users/by_did/:did/alt_text", api.GetAltTextCoverageForDID)
+	router.GET("/users/search", api.SearchAuthors)
+
+	router.GET("/lists/members", api.GetListMembers)
+
+	router.GET("/feed_generators", api.SearchFeedGenerators)
+	router.GET("/feed_generators/by_uri", api.GetFeedGenerator)
+
+	router.GET("/links/top_domains", api.GetTopLinkDomains)
+	router.GET("/links/posts", api.GetPostsLinkingTo)
+
+	router.GET("/images/labels", api.GetTopDetectionLabels)
+	router.GET("/images/by_label", api.GetImagesByDetectionLabel)
+	router.GET("/images/:cid/detections", api.GetDetectionsForImage)
+	router.GET("/images/:cid/similar", api.GetSimilarImages)
+
+	router.GET("/search/posts", api.SearchPosts)
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -202,6 +239,12 @@ func main() {
 		}
 	}()
 
+	// Health check feed generators in the background if enabled
+	if os.Getenv("FEED_GENERATOR_HEALTH_CHECKS_ENABLED") == "true" {
+		healthChecker := healthcheck.NewHealthChecker(postRegistry, 1*time.Hour, sugar)
+		go healthChecker.Run(ctx)
+	}
+
 	log.Printf("Starting server on port %s", port)
 	router.Run(fmt.Sprintf(":%s", port))
 }
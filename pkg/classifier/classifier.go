@@ -0,0 +1,145 @@
+package classifier
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search"
+)
+
+// Classifier labels batches of posts
+type Classifier interface {
+	// Classify returns the labels that apply to the posts, a post can get any number of labels
+	// Classify may be called again with the same posts if a batch fails, so it must be safe to retry
+	Classify(ctx context.Context, posts []*search.Post) ([]Classification, error)
+}
+
+// Pausable is implemented by classifiers that depend on a service that can go down
+// The pipeline waits for the service to come back instead of skipping batches while it's down,
+// Classify should return an error wrapping sidecar.ErrUnavailable when it can't reach the service
+type Pausable interface {
+	WaitUntilAvailable(ctx context.Context) error
+}
+
+// Seeder is implemented by classifiers that can have work older than Options.Lookback when they're run for the first time
+// Seed returns how far back the classifier should start instead, ok is false if there's nothing older to start from
+type Seeder interface {
+	Seed(ctx context.Context) (since time.Time, ok bool, err error)
+}
+
+// Backfiller is implemented by classifiers whose config can change what they label
+// Enable adds a stage for each backfill that classifies older posts and stops once it catches up to the newest ones
+type Backfiller interface {
+	Backfills(ctx context.Context) ([]Backfill, error)
+}
+
+// Backfill is a one-off run of a classifier over older posts
+type Backfill struct {
+	// Name keys the backfill's progress in the PostRegistry, it should change whenever the backfill needs to run again
+	Name       string
+	Classifier Classifier
+	// Options.Lookback is how far back the backfill starts
+	Options Options
+	// Start is called before the backfill's first batch with the time it starts from, it's optional
+	// If it fails the backfill doesn't start and Start is called again on the next attempt
+	Start func(ctx context.Context, since time.Time) error
+	// Done is called once the backfill has caught up to the newest posts, it's optional
+	// If it fails the backfill keeps running until Done succeeds
+	Done func(ctx context.Context) error
+}
+
+// Classification is a label a classifier applied to a post
+type Classification struct {
+	PostID     string
+	AuthorDID  string
+	Label      string
+	Confidence float64
+}
+
+// Options control how the pipeline runs a classifier
+type Options struct {
+	// BatchSize is the number of posts passed to each Classify call
+	BatchSize int32
+	// MinConfidence is the confidence below which classifications aren't written as labels
+	MinConfidence float64
+	// MaxAttempts is the number of times a failing batch is retried before it's skipped
+	MaxAttempts int
+	// Lookback is how far back a classifier starts when it's run for the first time
+	Lookback time.Duration
+}
+
+var defaultOptions = Options{
+	BatchSize:     500,
+	MinConfidence: 0.5,
+	MaxAttempts:   5,
+	Lookback:      time.Hour,
+}
+
+// withDefaults fills in zero options with the defaults
+func (o Options) withDefaults() Options {
+	if o.BatchSize <= 0 {
+		o.BatchSize = defaultOptions.BatchSize
+	}
+	if o.MinConfidence <= 0 {
+		o.MinConfidence = defaultOptions.MinConfidence
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = defaultOptions.MaxAttempts
+	}
+	if o.Lookback <= 0 {
+		o.Lookback = defaultOptions.Lookback
+	}
+	return o
+}
+
+// Factory builds a registered classifier, reading any config it needs from the environment
+type Factory func(postRegistry *search.PostRegistry) (Classifier, error)
+
+type registration struct {
+	factory Factory
+	options Options
+}
+
+var (
+	registryLk sync.Mutex
+	registry   = map[string]registration{}
+)
+
+// Register makes a classifier available to Pipeline.Enable under a name
+// It's meant to be called from the init func of the file implementing the classifier
+// The name keys the classifier's progress in the PostRegistry so it shouldn't change
+func Register(name string, factory Factory, options Options) {
+	registryLk.Lock()
+	defer registryLk.Unlock()
+
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("classifier %q is already registered", name))
+	}
+
+	registry[name] = registration{factory: factory, options: options.withDefaults()}
+}
+
+// Registered returns the names of the registered classifiers in order
+func Registered() []string {
+	registryLk.Lock()
+	defer registryLk.Unlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func lookup(name string) (registration, bool) {
+	registryLk.Lock()
+	defer registryLk.Unlock()
+
+	reg, ok := registry[name]
+	return reg, ok
+}
@@ -0,0 +1,176 @@
+package classifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search"
+	"github.com/ericvolp12/bsky-experiments/pkg/sidecar"
+	"github.com/ericvolp12/bsky-experiments/pkg/topics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfidentClassifications(t *testing.T) {
+	classifications := []Classification{
+		{PostID: "a", Label: "sentiment:pos", Confidence: 0.9},
+		{PostID: "a", Label: "sentiment:pos", Confidence: 0.8},
+		{PostID: "b", Label: "sentiment:neg", Confidence: 0.4},
+		{PostID: "b", Label: "lang:en", Confidence: 0.6},
+	}
+
+	confident := confidentClassifications(classifications, 0.5)
+
+	assert.Equal(t, []Classification{
+		{PostID: "a", Label: "sentiment:pos", Confidence: 0.9},
+		{PostID: "b", Label: "lang:en", Confidence: 0.6},
+	}, confident)
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, time.Second, retryDelay(1))
+	assert.Equal(t, 8*time.Second, retryDelay(4))
+	assert.Equal(t, time.Minute, retryDelay(7))
+	assert.Equal(t, time.Minute, retryDelay(100))
+}
+
+func TestOptionsWithDefaults(t *testing.T) {
+	options := Options{BatchSize: 10}.withDefaults()
+
+	assert.Equal(t, int32(10), options.BatchSize)
+	assert.Equal(t, defaultOptions.MinConfidence, options.MinConfidence)
+	assert.Equal(t, defaultOptions.MaxAttempts, options.MaxAttempts)
+	assert.Equal(t, defaultOptions.Lookback, options.Lookback)
+}
+
+func TestRegisteredIncludesSentiment(t *testing.T) {
+	assert.Contains(t, Registered(), "sentiment")
+	assert.Panics(t, func() { Register("sentiment", newSentimentClassifier, Options{}) })
+}
+
+type unavailableClassifier struct {
+	calls int
+}
+
+func (uc *unavailableClassifier) Classify(ctx context.Context, posts []*search.Post) ([]Classification, error) {
+	uc.calls++
+	return nil, fmt.Errorf("sentiment-en: %w", sidecar.ErrUnavailable)
+}
+
+func TestClassifyWithRetriesStopsWhenUnavailable(t *testing.T) {
+	uc := &unavailableClassifier{}
+	p := &Pipeline{}
+	st := &stage{name: "unavailable", classifier: uc, options: Options{}.withDefaults()}
+
+	_, err := p.classifyWithRetries(context.Background(), st, []*search.Post{{ID: "a"}})
+	assert.True(t, errors.Is(err, sidecar.ErrUnavailable))
+	assert.Equal(t, 1, uc.calls)
+}
+
+type seededClassifier struct {
+	unavailableClassifier
+	since time.Time
+	ok    bool
+}
+
+func (sc *seededClassifier) Seed(ctx context.Context) (time.Time, bool, error) {
+	return sc.since, sc.ok, nil
+}
+
+func TestSeedCursor(t *testing.T) {
+	lookback := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name       string
+		classifier Classifier
+		want       time.Time
+	}{
+		{
+			name:       "Not a seeder",
+			classifier: &unavailableClassifier{},
+			want:       lookback,
+		},
+		{
+			name:       "Nothing to seed from",
+			classifier: &seededClassifier{},
+			want:       lookback,
+		},
+		{
+			name:       "Seed before lookback",
+			classifier: &seededClassifier{since: lookback.Add(-24 * time.Hour), ok: true},
+			want:       lookback.Add(-24 * time.Hour),
+		},
+		{
+			name:       "Seed after lookback",
+			classifier: &seededClassifier{since: lookback.Add(time.Minute), ok: true},
+			want:       lookback,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			st := &stage{name: "seeded", classifier: tc.classifier}
+			got, err := seedCursor(context.Background(), st, lookback)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestTopicClassifier(t *testing.T) {
+	rules, err := topics.LoadRules(strings.NewReader(`[
+		{"name": "space", "keywords": ["nasa"], "hashtags": ["space"]},
+		{"name": "cats", "hashtags": ["caturday"]}
+	]`))
+	assert.NoError(t, err)
+
+	tags := fakePostTags{"c": {"Caturday"}}
+	tc := &TopicClassifier{Rules: rules, BackfillLookback: time.Hour, Tags: tags}
+
+	classifications, err := tc.Classify(context.Background(), []*search.Post{
+		{ID: "a", AuthorDID: "did:plc:a", Text: "NASA cat pics #caturday"},
+		{ID: "b", AuthorDID: "did:plc:b", Text: "nothing to see"},
+		{ID: "c", AuthorDID: "did:plc:c", Text: "tagged without a hashtag in the text"},
+		nil,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []Classification{
+		{PostID: "a", AuthorDID: "did:plc:a", Label: "topic:space", Confidence: 1},
+		{PostID: "a", AuthorDID: "did:plc:a", Label: "topic:cats", Confidence: 1},
+		{PostID: "c", AuthorDID: "did:plc:c", Label: "topic:cats", Confidence: 1},
+	}, classifications)
+
+	backfill := tc.backfill(rules.Rules)
+	assert.Equal(t, time.Hour, backfill.Options.Lookback)
+
+	// Every pending rule runs in the one pass
+	classifications, err = backfill.Classifier.Classify(context.Background(), []*search.Post{
+		{ID: "a", AuthorDID: "did:plc:a", Text: "NASA cat pics #caturday"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []Classification{
+		{PostID: "a", AuthorDID: "did:plc:a", Label: "topic:space", Confidence: 1},
+		{PostID: "a", AuthorDID: "did:plc:a", Label: "topic:cats", Confidence: 1},
+	}, classifications)
+
+	// The pass is keyed by its rules regardless of their order and changes when a different set of rules is pending
+	reversed := []*topics.Rule{rules.Rules[1], rules.Rules[0]}
+	assert.Equal(t, backfill.Name, tc.backfill(reversed).Name)
+	assert.NotEqual(t, backfill.Name, tc.backfill(rules.Rules[:1]).Name)
+}
+
+// fakePostTags is a PostTagSource backed by a map of post IDs to tags
+type fakePostTags map[string][]string
+
+func (f fakePostTags) GetTagsForPosts(_ context.Context, postIDs []string) (map[string][]string, error) {
+	tags := map[string][]string{}
+	for _, postID := range postIDs {
+		if postTags, ok := f[postID]; ok {
+			tags[postID] = postTags
+		}
+	}
+	return tags, nil
+}
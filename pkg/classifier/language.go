@@ -0,0 +1,82 @@
+package classifier
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/language"
+	"github.com/ericvolp12/bsky-experiments/pkg/search"
+	"go.opentelemetry.io/otel"
+)
+
+func init() {
+	Register("language", newLanguageClassifier, Options{
+		BatchSize:     2000,
+		MinConfidence: 0.5,
+	})
+}
+
+// LanguageClassifier labels posts with the language detected from their text, i.e. "lang:en"
+// It also stores the detected language and its confidence for the post, detections under
+// search.MinDetectedLangConfidence are stored as unknown
+type LanguageClassifier struct {
+	Detector     *language.Detector
+	PostRegistry *search.PostRegistry
+}
+
+func newLanguageClassifier(postRegistry *search.PostRegistry) (Classifier, error) {
+	return &LanguageClassifier{
+		Detector:     language.NewDetector(),
+		PostRegistry: postRegistry,
+	}, nil
+}
+
+func (lc *LanguageClassifier) Classify(ctx context.Context, posts []*search.Post) ([]Classification, error) {
+	tracer := otel.Tracer("classifier")
+	ctx, span := tracer.Start(ctx, "LanguageClassifier:Classify")
+	defer span.End()
+
+	detections := make([]*search.PostLanguage, 0, len(posts))
+	classifications := []Classification{}
+
+	for _, post := range posts {
+		if post == nil {
+			continue
+		}
+
+		postLang := &search.PostLanguage{
+			PostID:    post.ID,
+			AuthorDID: post.AuthorDID,
+			CreatedAt: post.CreatedAt,
+		}
+		detections = append(detections, postLang)
+
+		// Text in a language the detector doesn't know is spread over the closest ones it does,
+		// so unconfident detections are stored as unknown rather than as the closest match
+		detection, ok := lc.Detector.Detect(post.Text)
+		if !ok || detection.Confidence < search.MinDetectedLangConfidence {
+			continue
+		}
+
+		lang := detection.Lang
+		confidence := detection.Confidence
+		postLang.DetectedLang = &lang
+		postLang.DetectedConfidence = &confidence
+
+		classifications = append(classifications, Classification{
+			PostID:     post.ID,
+			AuthorDID:  post.AuthorDID,
+			Label:      search.LanguageLabel(lang),
+			Confidence: confidence,
+		})
+	}
+
+	if len(detections) > 0 {
+		err := lc.PostRegistry.SetDetectedLanguages(ctx, detections)
+		if err != nil {
+			return nil, fmt.Errorf("error storing detected languages: %w", err)
+		}
+	}
+
+	return classifications, nil
+}
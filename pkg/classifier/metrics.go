@@ -0,0 +1,37 @@
+package classifier
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var classifierPostsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "classifier_posts_total",
+	Help: "The total number of posts run through a classifier by result",
+}, []string{"classifier", "result"})
+
+var classifierLabelsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "classifier_labels_total",
+	Help: "The total number of labels applied by a classifier",
+}, []string{"classifier", "label"})
+
+var classifierRetriesCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "classifier_retries_total",
+	Help: "The total number of failed Classify calls",
+}, []string{"classifier"})
+
+var classifierPausesCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "classifier_pauses_total",
+	Help: "The total number of batches held back because a classifier's service was unavailable",
+}, []string{"classifier"})
+
+var classifierBatchDurationHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "classifier_batch_duration_seconds",
+	Help:    "The time it takes a classifier to classify a batch including retries",
+	Buckets: prometheus.ExponentialBuckets(0.01, 2, 15),
+}, []string{"classifier"})
+
+var classifierLagGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "classifier_lag_seconds",
+	Help: "How far behind the newest post a classifier's cursor is",
+}, []string{"classifier"})
@@ -0,0 +1,208 @@
+package classifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	objectdetection "github.com/ericvolp12/bsky-experiments/pkg/object-detection"
+	"github.com/ericvolp12/bsky-experiments/pkg/search"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func init() {
+	Register("objects", newObjectsClassifier, Options{
+		BatchSize: 200,
+	})
+}
+
+// ObjectsClassifier labels posts with the objects detected in their images, i.e. "cv:dog"
+// It also stores the detections for each image
+type ObjectsClassifier struct {
+	Detection    *objectdetection.ObjectDetectionImpl
+	Thresholds   *objectdetection.Thresholds
+	PostRegistry *search.PostRegistry
+}
+
+// newObjectsClassifier uses the object detection service at OBJECT_DETECTION_SERVICE_HOST
+// CV_LABEL_THRESHOLDS is a comma separated list of label=confidence overrides for labeling posts
+// i.e. "person=0.9,dog=0.6", labels not in the list use CV_DEFAULT_THRESHOLD (default 0.75)
+func newObjectsClassifier(postRegistry *search.PostRegistry) (Classifier, error) {
+	objectDetectionServiceHost := os.Getenv("OBJECT_DETECTION_SERVICE_HOST")
+	if objectDetectionServiceHost == "" {
+		return nil, fmt.Errorf("OBJECT_DETECTION_SERVICE_HOST environment variable is required")
+	}
+
+	defaultThreshold := objectdetection.DefaultThreshold
+	if rawDefault := os.Getenv("CV_DEFAULT_THRESHOLD"); rawDefault != "" {
+		var err error
+		defaultThreshold, err = strconv.ParseFloat(rawDefault, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing CV_DEFAULT_THRESHOLD: %w", err)
+		}
+	}
+
+	thresholds, err := objectdetection.ParseThresholds(os.Getenv("CV_LABEL_THRESHOLDS"), defaultThreshold)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing CV_LABEL_THRESHOLDS: %w", err)
+	}
+
+	return &ObjectsClassifier{
+		Detection:    objectdetection.NewObjectDetection(objectDetectionServiceHost),
+		Thresholds:   thresholds,
+		PostRegistry: postRegistry,
+	}, nil
+}
+
+// Classify runs object detection over the images of posts with embedded media that haven't been through CV yet
+// Labels are passed on with full confidence since they've already been held to their per-label thresholds
+func (oc *ObjectsClassifier) Classify(ctx context.Context, posts []*search.Post) ([]Classification, error) {
+	tracer := otel.Tracer("classifier")
+	ctx, span := tracer.Start(ctx, "ObjectsClassifier:Classify")
+	defer span.End()
+
+	postIDs := []string{}
+	for _, post := range posts {
+		if post != nil && post.HasEmbeddedMedia {
+			postIDs = append(postIDs, post.ID)
+		}
+	}
+
+	if len(postIDs) == 0 {
+		return []Classification{}, nil
+	}
+
+	images, err := oc.PostRegistry.GetImagesForPosts(ctx, postIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// The same image can be posted more than once so detection is run once per CID
+	imagesByCID := map[string][]*search.Image{}
+	imageMetas := []*objectdetection.ImageMeta{}
+	for _, image := range images {
+		if image.CVCompleted {
+			continue
+		}
+		if _, ok := imagesByCID[image.CID]; !ok {
+			imageMetas = append(imageMetas, &objectdetection.ImageMeta{
+				CID:       image.CID,
+				URL:       image.FullsizeURL,
+				MimeType:  image.MimeType,
+				CreatedAt: image.CreatedAt,
+			})
+		}
+		imagesByCID[image.CID] = append(imagesByCID[image.CID], image)
+	}
+
+	span.SetAttributes(attribute.Int("images", len(imageMetas)))
+
+	if len(imageMetas) == 0 {
+		return []Classification{}, nil
+	}
+
+	// Nothing is stored until every image is through so a failed batch can be retried whole
+	results, err := oc.Detection.ProcessImages(ctx, imageMetas)
+	if err != nil {
+		return nil, fmt.Errorf("error detecting objects: %w", err)
+	}
+
+	classifications := []Classification{}
+	for _, result := range results {
+		for _, label := range oc.Thresholds.PassingLabels(result.Results) {
+			for _, image := range imagesByCID[result.Meta.CID] {
+				classifications = append(classifications, Classification{
+					PostID:     image.PostID,
+					AuthorDID:  image.AuthorDID,
+					Label:      fmt.Sprintf("cv:%s", label),
+					Confidence: 1,
+				})
+			}
+		}
+	}
+
+	// Retries skip images that are marked CVCompleted, so their labels are written before any image is marked
+	// The pipeline writes them again with the rest of the batch, which is a no-op
+	if len(classifications) > 0 {
+		labels := make([]string, len(classifications))
+		postIDs := make([]string, len(classifications))
+		authorDIDs := make([]string, len(classifications))
+		for i, classification := range classifications {
+			labels[i] = classification.Label
+			postIDs[i] = classification.PostID
+			authorDIDs[i] = classification.AuthorDID
+		}
+
+		err = oc.PostRegistry.AddOneLabelPerPost(ctx, labels, postIDs, authorDIDs)
+		if err != nil {
+			return nil, fmt.Errorf("error storing CV labels: %w", err)
+		}
+	}
+
+	executionTime := time.Now()
+	for _, result := range results {
+		cvClasses, err := json.Marshal(result.Results)
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling detections: %w", err)
+		}
+
+		for _, image := range imagesByCID[result.Meta.CID] {
+			err = oc.PostRegistry.AddImageDetections(ctx, imageDetections(image, result.Results))
+			if err != nil {
+				return nil, fmt.Errorf("error storing image detections: %w", err)
+			}
+
+			// Marking CV data last keeps the image up for detection again if anything before it fails
+			err = oc.PostRegistry.AddCVDataToImage(ctx, image.CID, image.PostID, executionTime, cvClasses)
+			if err != nil {
+				return nil, fmt.Errorf("error storing CV results: %w", err)
+			}
+		}
+	}
+
+	return classifications, nil
+}
+
+// Seed starts the classifier from the oldest image that hasn't been through CV the first time it runs
+// so images from before its Lookback are still processed
+func (oc *ObjectsClassifier) Seed(ctx context.Context) (time.Time, bool, error) {
+	since, err := oc.PostRegistry.GetOldestUnprocessedImageInsertedAt(ctx)
+	if err != nil {
+		if errors.As(err, &search.NotFoundError{}) {
+			return time.Time{}, false, nil
+		}
+		return time.Time{}, false, err
+	}
+	return since, true, nil
+}
+
+// WaitUntilAvailable blocks while the object detection service is down
+func (oc *ObjectsClassifier) WaitUntilAvailable(ctx context.Context) error {
+	return oc.Detection.WaitUntilAvailable(ctx)
+}
+
+// imageDetections turns the detection results of an image into rows, detections without a bounding box are skipped
+func imageDetections(image *search.Image, results []objectdetection.DetectionResult) []*search.ImageDetection {
+	detections := []*search.ImageDetection{}
+	for i, result := range results {
+		if len(result.Box) != 4 {
+			continue
+		}
+		detections = append(detections, &search.ImageDetection{
+			ImageCID:       image.CID,
+			PostID:         image.PostID,
+			AuthorDID:      image.AuthorDID,
+			DetectionIndex: int32(i),
+			Label:          result.Label,
+			Confidence:     result.Confidence,
+			Box:            [4]float64{result.Box[0], result.Box[1], result.Box[2], result.Box[3]},
+			CreatedAt:      image.CreatedAt,
+		})
+	}
+	return detections
+}
@@ -0,0 +1,338 @@
+package classifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search"
+	"github.com/ericvolp12/bsky-experiments/pkg/sidecar"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+type stage struct {
+	name       string
+	classifier Classifier
+	options    Options
+	// backfill stages stop once they've caught up to the newest posts
+	backfill bool
+	start    func(ctx context.Context, since time.Time) error
+	done     func(ctx context.Context) error
+}
+
+// Pipeline runs each of its classifiers over the posts in the PostRegistry in the order they were inserted
+// Every classifier keeps its own cursor in the PostRegistry so they progress independently
+// and a new classifier can be added without reprocessing posts for the others
+type Pipeline struct {
+	PostRegistry *search.PostRegistry
+	Logger       *zap.SugaredLogger
+	// PollInterval is how long a classifier waits for new posts once it has caught up
+	PollInterval time.Duration
+	// SettleDelay keeps classifiers this far behind the newest inserts so writes that commit late aren't skipped
+	SettleDelay time.Duration
+
+	stages []*stage
+}
+
+func NewPipeline(postRegistry *search.PostRegistry, logger *zap.SugaredLogger) *Pipeline {
+	return &Pipeline{
+		PostRegistry: postRegistry,
+		Logger:       logger,
+		PollInterval: 5 * time.Second,
+		SettleDelay:  time.Minute,
+	}
+}
+
+// Add adds a classifier to the pipeline under a name
+func (p *Pipeline) Add(name string, classifier Classifier, options Options) {
+	p.stages = append(p.stages, &stage{
+		name:       name,
+		classifier: classifier,
+		options:    options.withDefaults(),
+	})
+}
+
+// Enable builds the named registered classifiers and adds them to the pipeline
+func (p *Pipeline) Enable(ctx context.Context, names []string) error {
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		reg, ok := lookup(name)
+		if !ok {
+			return fmt.Errorf("unknown classifier %q, registered classifiers are %v", name, Registered())
+		}
+
+		classifier, err := reg.factory(p.PostRegistry)
+		if err != nil {
+			return fmt.Errorf("error creating classifier %q: %w", name, err)
+		}
+
+		p.Add(name, classifier, reg.options)
+
+		if backfiller, ok := classifier.(Backfiller); ok {
+			backfills, err := backfiller.Backfills(ctx)
+			if err != nil {
+				return fmt.Errorf("error getting backfills for classifier %q: %w", name, err)
+			}
+
+			for _, backfill := range backfills {
+				p.stages = append(p.stages, &stage{
+					name:       backfill.Name,
+					classifier: backfill.Classifier,
+					options:    backfill.Options.withDefaults(),
+					backfill:   true,
+					start:      backfill.Start,
+					done:       backfill.Done,
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+// Run runs every classifier in the pipeline until the context is cancelled
+func (p *Pipeline) Run(ctx context.Context) {
+	wg := sync.WaitGroup{}
+	for _, st := range p.stages {
+		wg.Add(1)
+		go func(st *stage) {
+			defer wg.Done()
+			p.runStage(ctx, st)
+		}(st)
+	}
+	wg.Wait()
+}
+
+func (p *Pipeline) runStage(ctx context.Context, st *stage) {
+	log := p.Logger.With("classifier", st.name)
+	log.Info("starting classifier...")
+
+	for {
+		if pausable, ok := st.classifier.(Pausable); ok {
+			err := pausable.WaitUntilAvailable(ctx)
+			if err != nil {
+				log.Info("context cancelled, stopping classifier...")
+				return
+			}
+		}
+
+		processed, err := p.processBatch(ctx, st)
+		if err != nil {
+			log.Errorf("error processing batch: %+v", err)
+		}
+
+		if st.backfill && err == nil && processed < int(st.options.BatchSize) {
+			if st.done == nil {
+				log.Info("backfill caught up, stopping...")
+				return
+			}
+
+			err = st.done(ctx)
+			if err == nil {
+				log.Info("backfill caught up, stopping...")
+				return
+			}
+			log.Errorf("error finishing backfill: %+v", err)
+		}
+
+		// Go straight to the next batch while there's a backlog
+		if err == nil && processed == int(st.options.BatchSize) {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info("context cancelled, stopping classifier...")
+			return
+		case <-time.After(p.PollInterval):
+		}
+	}
+}
+
+// processBatch classifies the next batch of posts and moves the classifier's cursor past it
+// It returns the number of posts in the batch
+func (p *Pipeline) processBatch(ctx context.Context, st *stage) (int, error) {
+	tracer := otel.Tracer("classifier")
+	ctx, span := tracer.Start(ctx, "Pipeline:processBatch")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("classifier", st.name))
+
+	cursorInsertedAt := time.Now().Add(-st.options.Lookback)
+	cursorPostID := ""
+
+	progress, err := p.PostRegistry.GetClassifierProgress(ctx, st.name)
+	if err == nil {
+		cursorInsertedAt = progress.CursorInsertedAt
+		cursorPostID = progress.CursorPostID
+	} else if !errors.As(err, &search.NotFoundError{}) {
+		return 0, fmt.Errorf("error getting progress: %w", err)
+	} else {
+		// The classifier hasn't written any progress yet so this is its first batch
+		cursorInsertedAt, err = seedCursor(ctx, st, cursorInsertedAt)
+		if err != nil {
+			return 0, err
+		}
+
+		if st.start != nil {
+			err = st.start(ctx, cursorInsertedAt)
+			if err != nil {
+				return 0, fmt.Errorf("error starting backfill: %w", err)
+			}
+		}
+	}
+
+	posts, err := p.PostRegistry.GetPostsAfterCursor(ctx, cursorInsertedAt, cursorPostID, time.Now().Add(-p.SettleDelay), st.options.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	span.SetAttributes(attribute.Int("batch.size", len(posts)))
+
+	if len(posts) == 0 {
+		return 0, nil
+	}
+
+	last := posts[len(posts)-1]
+	start := time.Now()
+
+	classifications, err := p.classifyWithRetries(ctx, st, posts)
+	classifierBatchDurationHistogram.WithLabelValues(st.name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		// Leave the batch to be classified again once the service is back
+		if ctx.Err() != nil || errors.Is(err, sidecar.ErrUnavailable) {
+			return 0, err
+		}
+
+		// Skip the batch so one bad batch doesn't stall the classifier
+		classifierPostsCounter.WithLabelValues(st.name, "failed").Add(float64(len(posts)))
+		updateErr := p.PostRegistry.UpdateClassifierProgress(ctx, st.name, *last.InsertedAt, last.ID, 0, 0, int64(len(posts)), err)
+		if updateErr != nil {
+			return 0, fmt.Errorf("error skipping failed batch (%s): %w", err.Error(), updateErr)
+		}
+		return len(posts), fmt.Errorf("skipped batch of %d posts after %d attempts: %w", len(posts), st.options.MaxAttempts, err)
+	}
+
+	labels := confidentClassifications(classifications, st.options.MinConfidence)
+
+	if len(labels) > 0 {
+		labelNames := make([]string, len(labels))
+		postIDs := make([]string, len(labels))
+		authorDIDs := make([]string, len(labels))
+		for i, label := range labels {
+			labelNames[i] = label.Label
+			postIDs[i] = label.PostID
+			authorDIDs[i] = label.AuthorDID
+			classifierLabelsCounter.WithLabelValues(st.name, label.Label).Inc()
+		}
+
+		// If the labels can't be written the cursor stays put and the batch is classified again
+		err = p.PostRegistry.AddOneLabelPerPost(ctx, labelNames, postIDs, authorDIDs)
+		if err != nil {
+			return 0, fmt.Errorf("error writing labels: %w", err)
+		}
+	}
+
+	err = p.PostRegistry.UpdateClassifierProgress(ctx, st.name, *last.InsertedAt, last.ID, int64(len(posts)), int64(len(labels)), 0, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error updating progress: %w", err)
+	}
+
+	classifierPostsCounter.WithLabelValues(st.name, "classified").Add(float64(len(posts)))
+	classifierLagGauge.WithLabelValues(st.name).Set(time.Since(*last.InsertedAt).Seconds())
+	span.SetAttributes(attribute.Int("batch.labels", len(labels)))
+
+	return len(posts), nil
+}
+
+// seedCursor moves a classifier's first cursor back to where its seeder says its work starts
+func seedCursor(ctx context.Context, st *stage, cursorInsertedAt time.Time) (time.Time, error) {
+	seeder, ok := st.classifier.(Seeder)
+	if !ok {
+		return cursorInsertedAt, nil
+	}
+
+	since, ok, err := seeder.Seed(ctx)
+	if err != nil {
+		return cursorInsertedAt, fmt.Errorf("error seeding cursor: %w", err)
+	}
+	if ok && since.Before(cursorInsertedAt) {
+		return since, nil
+	}
+	return cursorInsertedAt, nil
+}
+
+func (p *Pipeline) classifyWithRetries(ctx context.Context, st *stage, posts []*search.Post) ([]Classification, error) {
+	var err error
+	for attempt := 1; attempt <= st.options.MaxAttempts; attempt++ {
+		var classifications []Classification
+		classifications, err = st.classifier.Classify(ctx, posts)
+		if err == nil {
+			return classifications, nil
+		}
+
+		// Retrying won't help while the service is down, the stage waits for it instead
+		if errors.Is(err, sidecar.ErrUnavailable) {
+			classifierPausesCounter.WithLabelValues(st.name).Inc()
+			return nil, err
+		}
+
+		classifierRetriesCounter.WithLabelValues(st.name).Inc()
+		p.Logger.Warnw("classifier failed, retrying", "classifier", st.name, "attempt", attempt, "error", err)
+
+		if attempt == st.options.MaxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(retryDelay(attempt)):
+		}
+	}
+
+	return nil, err
+}
+
+// retryDelay backs off exponentially from one second, capped at a minute
+func retryDelay(attempt int) time.Duration {
+	delay := time.Second << (attempt - 1)
+	if delay > time.Minute || delay <= 0 {
+		return time.Minute
+	}
+	return delay
+}
+
+// confidentClassifications drops classifications below the minimum confidence and duplicate labels for a post
+func confidentClassifications(classifications []Classification, minConfidence float64) []Classification {
+	seen := map[[2]string]struct{}{}
+	confident := make([]Classification, 0, len(classifications))
+
+	for _, classification := range classifications {
+		if classification.Confidence < minConfidence {
+			continue
+		}
+
+		key := [2]string{classification.PostID, classification.Label}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		confident = append(confident, classification)
+	}
+
+	return confident
+}
@@ -0,0 +1,102 @@
+package classifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search"
+	"github.com/ericvolp12/bsky-experiments/pkg/sentiment"
+	"go.opentelemetry.io/otel"
+)
+
+func init() {
+	Register("sentiment", newSentimentClassifier, Options{
+		BatchSize:     2000,
+		MinConfidence: 0.65,
+	})
+}
+
+// SentimentClassifier labels posts with positive or negative sentiment using the sentiment services
+// It also stores the sentiment on the posts themselves
+type SentimentClassifier struct {
+	Sentiment    *sentiment.Sentiment
+	PostRegistry *search.PostRegistry
+}
+
+// newSentimentClassifier routes posts to sentiment services by language with SENTIMENT_SERVICE_ROUTES
+// i.e. "en=http://sentiment-en:8088,*=http://sentiment-multilingual:8088"
+// SENTIMENT_SERVICE_HOST on its own is used as the English sentiment service
+func newSentimentClassifier(postRegistry *search.PostRegistry) (Classifier, error) {
+	routes := map[string]string{}
+	if rawRoutes := os.Getenv("SENTIMENT_SERVICE_ROUTES"); rawRoutes != "" {
+		var err error
+		routes, err = sentiment.ParseRoutes(rawRoutes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing SENTIMENT_SERVICE_ROUTES: %w", err)
+		}
+	}
+
+	if sentimentServiceHost := os.Getenv("SENTIMENT_SERVICE_HOST"); sentimentServiceHost != "" {
+		if _, ok := routes["en"]; !ok {
+			routes["en"] = sentimentServiceHost
+		}
+	}
+
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("SENTIMENT_SERVICE_ROUTES or SENTIMENT_SERVICE_HOST environment variable is required")
+	}
+
+	return &SentimentClassifier{
+		Sentiment:    sentiment.NewSentiment(routes),
+		PostRegistry: postRegistry,
+	}, nil
+}
+
+func (sc *SentimentClassifier) Classify(ctx context.Context, posts []*search.Post) ([]Classification, error) {
+	tracer := otel.Tracer("classifier")
+	ctx, span := tracer.Start(ctx, "SentimentClassifier:Classify")
+	defer span.End()
+
+	results, err := sc.Sentiment.GetPostsSentiment(ctx, posts)
+	if err != nil {
+		return nil, fmt.Errorf("error getting sentiment for posts: %w", err)
+	}
+
+	errs := sc.PostRegistry.SetSentimentResults(ctx, results)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("error setting sentiment results: %w", errors.Join(errs...))
+	}
+
+	classifications := []Classification{}
+	for _, post := range results {
+		if post == nil || post.Sentiment == nil || post.SentimentConfidence == nil {
+			continue
+		}
+
+		var label string
+		switch *post.Sentiment {
+		case search.PositiveSentiment:
+			label = "sentiment:pos"
+		case search.NegativeSentiment:
+			label = "sentiment:neg"
+		default:
+			continue
+		}
+
+		classifications = append(classifications, Classification{
+			PostID:     post.ID,
+			AuthorDID:  post.AuthorDID,
+			Label:      label,
+			Confidence: *post.SentimentConfidence,
+		})
+	}
+
+	return classifications, nil
+}
+
+// WaitUntilAvailable blocks while the sentiment services the last failed batch needed are down
+func (sc *SentimentClassifier) WaitUntilAvailable(ctx context.Context) error {
+	return sc.Sentiment.WaitUntilAvailable(ctx)
+}
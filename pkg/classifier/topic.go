@@ -0,0 +1,179 @@
+package classifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search"
+	"github.com/ericvolp12/bsky-experiments/pkg/topics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func init() {
+	Register("topic", newTopicClassifier, Options{
+		BatchSize: 2000,
+	})
+}
+
+// defaultTopicBackfillLookback is how far back new topic rules are applied
+const defaultTopicBackfillLookback = 30 * 24 * time.Hour
+
+// TopicClassifier labels posts with the topics whose rules match their text or tag facets, i.e. "topic:space"
+// Rules are loaded from the JSON file at TOPIC_RULES_PATH, new or edited rules are backfilled
+// over the posts from the last TOPIC_BACKFILL_LOOKBACK (default 30 days)
+type TopicClassifier struct {
+	Rules            *topics.Rules
+	BackfillLookback time.Duration
+	PostRegistry     *search.PostRegistry
+	Tags             PostTagSource
+}
+
+// PostTagSource looks up the tags from the tag facets of a batch of posts, keyed by post ID
+type PostTagSource interface {
+	GetTagsForPosts(ctx context.Context, postIDs []string) (map[string][]string, error)
+}
+
+func newTopicClassifier(postRegistry *search.PostRegistry) (Classifier, error) {
+	rulesPath := os.Getenv("TOPIC_RULES_PATH")
+	if rulesPath == "" {
+		return nil, fmt.Errorf("TOPIC_RULES_PATH environment variable is required")
+	}
+
+	rules, err := topics.LoadRulesFromFile(rulesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	backfillLookback := defaultTopicBackfillLookback
+	if rawLookback := os.Getenv("TOPIC_BACKFILL_LOOKBACK"); rawLookback != "" {
+		backfillLookback, err = time.ParseDuration(rawLookback)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse TOPIC_BACKFILL_LOOKBACK: %w", err)
+		}
+	}
+
+	return &TopicClassifier{
+		Rules:            rules,
+		BackfillLookback: backfillLookback,
+		PostRegistry:     postRegistry,
+		Tags:             postRegistry,
+	}, nil
+}
+
+func (tc *TopicClassifier) Classify(ctx context.Context, posts []*search.Post) ([]Classification, error) {
+	tracer := otel.Tracer("classifier")
+	ctx, span := tracer.Start(ctx, "TopicClassifier:Classify")
+	defer span.End()
+
+	postIDs := make([]string, 0, len(posts))
+	for _, post := range posts {
+		if post != nil {
+			postIDs = append(postIDs, post.ID)
+		}
+	}
+
+	// Tag facets don't have to appear in the text, so they're matched alongside it
+	tags, err := tc.Tags.GetTagsForPosts(ctx, postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error getting tags for posts: %w", err)
+	}
+
+	classifications := []Classification{}
+
+	for _, post := range posts {
+		if post == nil {
+			continue
+		}
+
+		for _, topic := range tc.Rules.Match(post.Text, tags[post.ID]) {
+			classifications = append(classifications, Classification{
+				PostID:     post.ID,
+				AuthorDID:  post.AuthorDID,
+				Label:      topics.Label(topic),
+				Confidence: 1,
+			})
+		}
+	}
+
+	span.SetAttributes(attribute.Int("classifications", len(classifications)))
+
+	return classifications, nil
+}
+
+// Backfills runs the rules that were added or edited since the last backfill over older posts in a single pass
+// Each rule is marked as backfilled under its fingerprint once the pass catches up, so it only runs again when it's edited
+func (tc *TopicClassifier) Backfills(ctx context.Context) ([]Backfill, error) {
+	pending := []*topics.Rule{}
+	for _, rule := range tc.Rules.Rules {
+		_, err := tc.PostRegistry.GetClassifierProgress(ctx, topicRuleBackfilledKey(rule))
+		if err == nil {
+			continue
+		}
+		if !errors.As(err, &search.NotFoundError{}) {
+			return nil, fmt.Errorf("error checking whether topic rule %q was backfilled: %w", rule.Name, err)
+		}
+		pending = append(pending, rule)
+	}
+
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	return []Backfill{tc.backfill(pending)}, nil
+}
+
+// backfill builds the pass over older posts for the given rules
+// It's keyed by the rules' fingerprints so an interrupted pass resumes as long as the pending rules don't change
+func (tc *TopicClassifier) backfill(rules []*topics.Rule) Backfill {
+	keys := make([]string, len(rules))
+	for i, rule := range rules {
+		keys[i] = topicRuleBackfilledKey(rule)
+	}
+	sort.Strings(keys)
+
+	h := fnv.New64a()
+	h.Write([]byte(strings.Join(keys, "\n")))
+
+	return Backfill{
+		Name: fmt.Sprintf("topic-backfill:%016x", h.Sum64()),
+		Classifier: &TopicClassifier{
+			Rules: &topics.Rules{Rules: rules},
+			Tags:  tc.Tags,
+		},
+		Options: Options{
+			BatchSize: 5000,
+			Lookback:  tc.BackfillLookback,
+		},
+		// Clear the labels from older versions of the rules so posts they no longer match lose them
+		Start: func(ctx context.Context, since time.Time) error {
+			for _, rule := range rules {
+				err := tc.PostRegistry.DeletePostLabelsInsertedSince(ctx, topics.Label(rule.Name), since)
+				if err != nil {
+					return fmt.Errorf("error clearing labels for topic rule %q: %w", rule.Name, err)
+				}
+			}
+			return nil
+		},
+		Done: func(ctx context.Context) error {
+			for _, rule := range rules {
+				err := tc.PostRegistry.UpdateClassifierProgress(ctx, topicRuleBackfilledKey(rule), time.Now(), "", 0, 0, 0, nil)
+				if err != nil {
+					return fmt.Errorf("error marking topic rule %q as backfilled: %w", rule.Name, err)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// topicRuleBackfilledKey is the classifier progress key recorded once a version of a rule has been backfilled
+func topicRuleBackfilledKey(rule *topics.Rule) string {
+	return fmt.Sprintf("topic-rule:%s:%s", rule.Name, rule.Fingerprint())
+}
@@ -0,0 +1,241 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bluesky-social/indigo/events"
+	"go.uber.org/zap"
+)
+
+// CursorTracker tracks which firehose sequence numbers are still being processed
+// so the persisted cursor only ever advances past events that have been fully handled.
+// Events are acquired as they are read off the stream (in sequence order) and released
+// once every piece of work they spawned has finished, the cursor is the low-water mark
+// of all sequence numbers still in flight.
+type CursorTracker struct {
+	lk sync.Mutex
+
+	// inFlight holds a reference count for each sequence number still being processed
+	inFlight map[int64]int
+	// lastDispatched is the highest sequence number read off the stream
+	lastDispatched int64
+	// lastCommitted is the last cursor persisted
+	lastCommitted int64
+
+	commit func(ctx context.Context, seq int64) error
+	Logger *zap.SugaredLogger
+}
+
+// NewCursorTracker creates a CursorTracker starting from the given cursor
+// commit is called with the new low-water mark whenever it advances
+func NewCursorTracker(startCursor int64, commit func(ctx context.Context, seq int64) error, logger *zap.SugaredLogger) *CursorTracker {
+	committedCursor.Set(float64(startCursor))
+
+	return &CursorTracker{
+		inFlight:       map[int64]int{},
+		lastDispatched: startCursor,
+		lastCommitted:  startCursor,
+		commit:         commit,
+		Logger:         logger,
+	}
+}
+
+// Reset is called when (re)connecting to the stream from the given cursor
+// so replayed events aren't counted as gaps
+func (ct *CursorTracker) Reset(cursor int64) {
+	ct.lk.Lock()
+	defer ct.lk.Unlock()
+	ct.lastDispatched = cursor
+}
+
+// Dispatch marks a sequence number as read off the stream and in flight
+// It must be called in stream order, gaps in the sequence are recorded here
+func (ct *CursorTracker) Dispatch(seq int64) {
+	ct.lk.Lock()
+	defer ct.lk.Unlock()
+
+	if ct.lastDispatched > 0 && seq > ct.lastDispatched+1 {
+		missed := seq - ct.lastDispatched - 1
+		seqGapsCounter.Inc()
+		seqGapSizeCounter.Add(float64(missed))
+		ct.Logger.Warnw("gap detected in firehose sequence",
+			"last_seq", ct.lastDispatched,
+			"seq", seq,
+			"missed", missed,
+		)
+	} else if seq <= ct.lastDispatched {
+		seqReplayedCounter.Inc()
+	}
+
+	if seq > ct.lastDispatched {
+		ct.lastDispatched = seq
+	}
+
+	ct.inFlight[seq]++
+	inFlightEventsGauge.Set(float64(len(ct.inFlight)))
+}
+
+// Acquire adds a reference to a sequence number that is already in flight
+// Use it when handing work for an event off to another routine
+func (ct *CursorTracker) Acquire(seq int64) {
+	ct.lk.Lock()
+	defer ct.lk.Unlock()
+	ct.inFlight[seq]++
+}
+
+// Release drops a reference to a sequence number, once all references
+// are released the event is considered fully processed
+func (ct *CursorTracker) Release(seq int64) {
+	ct.lk.Lock()
+	defer ct.lk.Unlock()
+
+	ct.inFlight[seq]--
+	if ct.inFlight[seq] <= 0 {
+		delete(ct.inFlight, seq)
+	}
+	inFlightEventsGauge.Set(float64(len(ct.inFlight)))
+}
+
+// LowWaterMark returns the highest sequence number below which every event has been processed
+func (ct *CursorTracker) LowWaterMark() int64 {
+	ct.lk.Lock()
+	defer ct.lk.Unlock()
+
+	lowWaterMark := ct.lastDispatched
+	for seq := range ct.inFlight {
+		if seq-1 < lowWaterMark {
+			lowWaterMark = seq - 1
+		}
+	}
+
+	return lowWaterMark
+}
+
+// Wait blocks until every event in flight has been released or the context is cancelled
+// Use it to drain the workers once a finite stream has been read to the end
+func (ct *CursorTracker) Wait(ctx context.Context) error {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		ct.lk.Lock()
+		inFlight := len(ct.inFlight)
+		ct.lk.Unlock()
+
+		if inFlight == 0 {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Commit persists the low-water mark if it has advanced since the last commit
+func (ct *CursorTracker) Commit(ctx context.Context) error {
+	lowWaterMark := ct.LowWaterMark()
+
+	ct.lk.Lock()
+	if lowWaterMark <= ct.lastCommitted {
+		ct.lk.Unlock()
+		return nil
+	}
+	ct.lk.Unlock()
+
+	err := ct.commit(ctx, lowWaterMark)
+	if err != nil {
+		return err
+	}
+
+	ct.lk.Lock()
+	if lowWaterMark > ct.lastCommitted {
+		ct.lastCommitted = lowWaterMark
+	}
+	ct.lk.Unlock()
+
+	committedCursor.Set(float64(lowWaterMark))
+
+	return nil
+}
+
+// Run commits the low-water mark on an interval until the context is cancelled
+// A final commit is made on the way out so a clean shutdown doesn't replay processed events
+func (ct *CursorTracker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			err := ct.Commit(ctx)
+			if err != nil {
+				ct.Logger.Errorf("failed to commit cursor: %+v\n", err)
+			}
+		case <-ctx.Done():
+			err := ct.Commit(context.Background())
+			if err != nil {
+				ct.Logger.Errorf("failed to commit cursor on shutdown: %+v\n", err)
+			}
+			return
+		}
+	}
+}
+
+// Scheduler wraps an events.Scheduler so every event is dispatched in stream order
+// before it is handed to the (possibly parallel) inner scheduler
+func (ct *CursorTracker) Scheduler(inner events.Scheduler) events.Scheduler {
+	return &trackingScheduler{
+		tracker: ct,
+		inner:   inner,
+	}
+}
+
+// Wrap wraps an event handler so the event is released once the handler returns
+func (ct *CursorTracker) Wrap(do func(context.Context, *events.XRPCStreamEvent) error) func(context.Context, *events.XRPCStreamEvent) error {
+	return func(ctx context.Context, xe *events.XRPCStreamEvent) error {
+		seq, ok := eventSeq(xe)
+		if ok {
+			defer ct.Release(seq)
+		}
+		return do(ctx, xe)
+	}
+}
+
+type trackingScheduler struct {
+	tracker *CursorTracker
+	inner   events.Scheduler
+}
+
+func (ts *trackingScheduler) AddWork(ctx context.Context, repo string, xe *events.XRPCStreamEvent) error {
+	seq, ok := eventSeq(xe)
+	if ok {
+		ts.tracker.Dispatch(seq)
+	}
+
+	err := ts.inner.AddWork(ctx, repo, xe)
+	if err != nil && ok {
+		ts.tracker.Release(seq)
+	}
+
+	return err
+}
+
+// eventSeq returns the sequence number of a stream event, if it has one
+func eventSeq(xe *events.XRPCStreamEvent) (int64, bool) {
+	switch {
+	case xe.RepoCommit != nil:
+		return xe.RepoCommit.Seq, true
+	case xe.RepoHandle != nil:
+		return xe.RepoHandle.Seq, true
+	case xe.RepoMigrate != nil:
+		return xe.RepoMigrate.Seq, true
+	case xe.RepoTombstone != nil:
+		return xe.RepoTombstone.Seq, true
+	}
+	return 0, false
+}
@@ -0,0 +1,71 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestCursorTrackerLowWaterMark(t *testing.T) {
+	committed := int64(0)
+	ct := NewCursorTracker(100, func(ctx context.Context, seq int64) error {
+		committed = seq
+		return nil
+	}, zap.NewNop().Sugar())
+
+	ctx := context.Background()
+
+	ct.Dispatch(101)
+	ct.Dispatch(102)
+	ct.Dispatch(103)
+
+	// A post from 102 is handed off to a worker
+	ct.Acquire(102)
+
+	// Events finish out of order
+	ct.Release(103)
+	ct.Release(102)
+	assert.Equal(t, int64(100), ct.LowWaterMark())
+
+	ct.Release(101)
+	assert.Equal(t, int64(101), ct.LowWaterMark())
+
+	assert.NoError(t, ct.Commit(ctx))
+	assert.Equal(t, int64(101), committed)
+
+	// Once the worker finishes with 102 everything has been processed
+	ct.Release(102)
+	assert.Equal(t, int64(103), ct.LowWaterMark())
+
+	assert.NoError(t, ct.Commit(ctx))
+	assert.Equal(t, int64(103), committed)
+
+	// Reconnecting from an older cursor replays events without moving the committed cursor backwards
+	ct.Reset(101)
+	ct.Dispatch(102)
+	assert.Equal(t, int64(101), ct.LowWaterMark())
+	assert.NoError(t, ct.Commit(ctx))
+	assert.Equal(t, int64(103), committed)
+}
+
+func TestCursorTrackerWait(t *testing.T) {
+	ct := NewCursorTracker(100, func(ctx context.Context, seq int64) error {
+		return nil
+	}, zap.NewNop().Sugar())
+
+	ct.Dispatch(101)
+	ct.Acquire(101)
+	ct.Release(101)
+
+	// The event is still held by a worker
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, ct.Wait(ctx), context.DeadlineExceeded)
+
+	go ct.Release(101)
+	assert.NoError(t, ct.Wait(context.Background()))
+	assert.Equal(t, int64(101), ct.LowWaterMark())
+}
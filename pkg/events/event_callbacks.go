@@ -4,7 +4,8 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"path"
+	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
@@ -17,8 +18,8 @@ import (
 	"github.com/bluesky-social/indigo/repomgr"
 	"github.com/ericvolp12/bsky-experiments/pkg/persistedgraph"
 	"github.com/ericvolp12/bsky-experiments/pkg/search"
-	intXRPC "github.com/ericvolp12/bsky-experiments/pkg/xrpc"
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
@@ -41,18 +42,32 @@ type RepoRecord struct {
 	ctx       context.Context
 	seq       int64
 	pst       appbsky.FeedPost
+	langs     []string
+	tags      []string
 	opPath    string
 	repoName  string
 	eventTime string
 	workerID  int
 }
 
+// repoRecordQueueSize bounds how many posts can be waiting on a worker
+// Queued posts hold back the committed cursor, so nothing in the queue is lost on a crash
+const repoRecordQueueSize = 1000
+
+// cursorCommitInterval is how often the low-water mark cursor is persisted
+const cursorCommitInterval = 5 * time.Second
+
+// batchWriterBatchSize and batchWriterFlushInterval bound how many PostRegistry writes are buffered and for how long
+const batchWriterBatchSize = 500
+const batchWriterFlushInterval = 1 * time.Second
+
 // BSky is a struct that holds the state of the social graph and the
 // authenticated XRPC client
 type BSky struct {
 	IncludeLinks bool
 
 	PersistedGraph *persistedgraph.PersistedGraph
+	FollowGraph    *persistedgraph.PersistedGraph
 
 	Logger *zap.SugaredLogger
 
@@ -63,19 +78,33 @@ type BSky struct {
 	redisClient     *redis.Client
 	cachesPrefix    string
 	profileCacheTTL time.Duration
-	postCacheTTL    time.Duration
+
+	// imageCDNTemplate builds image URLs from blob CIDs, image URLs point at the author's PDS if it's empty
+	imageCDNTemplate string
 
 	RepoRecordQueue chan RepoRecord
 
-	// Rate Limiter for requests against the BSky API
-	bskyLimiter      *rate.Limiter
+	// CursorTracker only lets the persisted cursor advance past fully processed events
+	CursorTracker *CursorTracker
+
+	// RecordHandlers registered by collection NSID
+	handlersMux sync.RWMutex
+	handlers    map[string]*recordHandlerPool
+
+	// Rate Limiter for requests against the PLC directory
 	directoryLimiter *rate.Limiter
+	// directoryClient fetches DID documents, which for did:web can be served by any host, so it gives up quickly
+	directoryClient *http.Client
+	// pdsFailureTTL is how long a DID whose PDS couldn't be resolved is skipped for
+	pdsFailureTTL time.Duration
 
 	WorkerCount int
 	Workers     []*Worker
 
 	PostRegistryEnabled bool
 	PostRegistry        *search.PostRegistry
+	// BatchWriter buffers post, author, image, and like writes to the PostRegistry
+	BatchWriter *search.BatchWriter
 }
 
 // NewBSky creates a new BSky struct with an authenticated XRPC client
@@ -85,31 +114,56 @@ func NewBSky(
 	includeLinks, postRegistryEnabled bool,
 	dbConnectionString string,
 	persistedGraph *persistedgraph.PersistedGraph,
+	followGraph *persistedgraph.PersistedGraph,
 	redisClient *redis.Client,
 	workerCount int,
+	imageCDNTemplate string,
 ) (*BSky, error) {
 
 	var postRegistry *search.PostRegistry
 	var err error
 
+	rawlog, err := zap.NewProduction()
+	if err != nil {
+		fmt.Printf("failed to create logger: %+v\n", err)
+		return nil, err
+	}
+	log := rawlog.Sugar().With("source", "event_handler")
+
+	var batchWriter *search.BatchWriter
 	if postRegistryEnabled {
 		postRegistry, err = search.NewPostRegistry(dbConnectionString)
 		if err != nil {
 			return nil, err
 		}
+		batchWriter = search.NewBatchWriter(postRegistry, batchWriterBatchSize, batchWriterFlushInterval, log.With("source", "batch_writer"))
 	}
 
-	rawlog, err := zap.NewProduction()
-	if err != nil {
-		fmt.Printf("failed to create logger: %+v\n", err)
-		return nil, err
+	// Resume cursor tracking from the last committed cursor
+	startCursor := int64(0)
+	if cursor := persistedGraph.GetCursor(ctx); cursor != "" {
+		startCursor, err = strconv.ParseInt(cursor, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse persisted cursor (%s): %w", cursor, err)
+		}
 	}
-	log := rawlog.Sugar().With("source", "event_handler")
+
+	cursorTracker := NewCursorTracker(startCursor, func(ctx context.Context, seq int64) error {
+		// Buffered writes have to land before the cursor moves past the events they came from
+		if batchWriter != nil {
+			err := batchWriter.Flush(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to flush batch writer before committing cursor: %w", err)
+			}
+		}
+		return persistedGraph.SetCursor(ctx, fmt.Sprintf("%d", seq))
+	}, log.With("source", "cursor_tracker"))
 
 	bsky := &BSky{
 		IncludeLinks: includeLinks,
 
 		PersistedGraph: persistedGraph,
+		FollowGraph:    followGraph,
 
 		Logger: log,
 
@@ -120,36 +174,54 @@ func NewBSky(
 		cachesPrefix:    "graph_builder",
 		redisClient:     redisClient,
 		profileCacheTTL: time.Hour * 12,
-		postCacheTTL:    time.Minute * 60,
 
-		RepoRecordQueue:  make(chan RepoRecord, 1),
-		bskyLimiter:      rate.NewLimiter(rate.Every(time.Millisecond*125), 1),
+		imageCDNTemplate: imageCDNTemplate,
+
+		RepoRecordQueue: make(chan RepoRecord, repoRecordQueueSize),
+		CursorTracker:   cursorTracker,
+
+		handlers:         map[string]*recordHandlerPool{},
 		directoryLimiter: rate.NewLimiter(rate.Every(time.Millisecond*125), 1),
+		directoryClient: &http.Client{
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+			Timeout:   5 * time.Second,
+		},
+		pdsFailureTTL: 10 * time.Minute,
 
 		WorkerCount: workerCount,
 		Workers:     make([]*Worker, workerCount),
 
 		PostRegistryEnabled: postRegistryEnabled,
 		PostRegistry:        postRegistry,
+		BatchWriter:         batchWriter,
 	}
 
-	// Initialize the workers, each with their own BSky Client and Mutex
-	// Workers share a single WorkQueue and SocialGraph/Mutex
+	// Initialize the workers, workers share a single WorkQueue and SocialGraph/Mutex
 	for i := 0; i < workerCount; i++ {
-		client, err := intXRPC.GetXRPCClient(ctx)
-		if err != nil {
-			return nil, err
-		}
-
 		bsky.Workers[i] = &Worker{
-			WorkerID:  i,
-			Client:    client,
-			ClientMux: sync.RWMutex{},
+			WorkerID: i,
 		}
 
 		go bsky.worker(ctx, i)
 	}
 
+	go cursorTracker.Run(ctx, cursorCommitInterval)
+
+	if batchWriter != nil {
+		go batchWriter.Run(ctx)
+	}
+
+	if postRegistryEnabled {
+		bsky.RegisterHandler(ctx, "app.bsky.feed.like", RecordHandlerFunc(bsky.HandleLike), 4)
+		bsky.RegisterHandler(ctx, "app.bsky.feed.repost", RecordHandlerFunc(bsky.HandleRepost), 2)
+		bsky.RegisterHandler(ctx, "app.bsky.graph.block", RecordHandlerFunc(bsky.HandleBlock), 1)
+		bsky.RegisterHandler(ctx, "app.bsky.graph.follow", RecordHandlerFunc(bsky.HandleFollow), 4)
+		bsky.RegisterHandler(ctx, "app.bsky.actor.profile", RecordHandlerFunc(bsky.HandleProfile), 1)
+		bsky.RegisterHandler(ctx, "app.bsky.graph.list", RecordHandlerFunc(bsky.HandleList), 1)
+		bsky.RegisterHandler(ctx, "app.bsky.graph.listitem", RecordHandlerFunc(bsky.HandleListItem), 2)
+		bsky.RegisterHandler(ctx, "app.bsky.feed.generator", RecordHandlerFunc(bsky.HandleFeedGenerator), 1)
+	}
+
 	return bsky, nil
 }
 
@@ -185,11 +257,6 @@ func (bsky *BSky) HandleRepoCommit(ctx context.Context, evt *comatproto.SyncSubs
 		switch ek {
 		case repomgr.EvtKindCreateRecord, repomgr.EvtKindUpdateRecord:
 			span.SetAttributes(attribute.String("op.path", op.Path))
-			// Check if this record is modifying the user's profile
-			if op.Path == "app.bsky.actor.profile/self" {
-				log.Infof("found profile update for %s", evt.Repo)
-				return nil
-			}
 
 			// Parse time from the event time string
 			t, err := time.Parse(time.RFC3339, evt.Time)
@@ -201,11 +268,6 @@ func (bsky *BSky) HandleRepoCommit(ctx context.Context, evt *comatproto.SyncSubs
 			lastSeqCreatedAt.Set(float64(t.UnixNano()))
 			lastSeqProcessedAt.Set(float64(time.Now().UnixNano()))
 
-			err = bsky.PersistedGraph.SetCursor(ctx, fmt.Sprintf("%d", evt.Seq))
-			if err != nil {
-				log.Errorf("failed to set cursor: %+v\n", err)
-			}
-
 			// Grab the record from the merkel tree
 			rc, rec, err := rr.GetRecord(ctx, op.Path)
 			if err != nil {
@@ -221,62 +283,61 @@ func (bsky *BSky) HandleRepoCommit(ctx context.Context, evt *comatproto.SyncSubs
 				return nil
 			}
 
+			// Hand the record off to a registered RecordHandler if there is one for its collection
+			if bsky.dispatchToHandler(ctx, evt.Seq, evt.Repo, ek, op.Path, rec, t) {
+				continue
+			}
+
 			// Unpack the record and process it
 			switch rec := rec.(type) {
 			case *appbsky.FeedPost:
+				// Read the declared languages and tag facets from the raw record
+				var langs, tags []string
+				blk, err := rr.Blockstore().Get(ctx, rc)
+				if err != nil {
+					log.Errorf("failed to get raw post record: %+v\n", err)
+				} else {
+					langs, err = DecodePostLangs(blk.RawData())
+					if err != nil {
+						log.Errorf("failed to decode post langs: %+v\n", err)
+					}
+					tags, err = DecodePostTags(blk.RawData())
+					if err != nil {
+						log.Errorf("failed to decode post tags: %+v\n", err)
+					}
+				}
+
 				span.AddEvent("Adding to Queue")
+				// Hold the cursor until a worker has processed the post
+				bsky.CursorTracker.Acquire(evt.Seq)
 				// Add the RepoRecord to the Queue
 				bsky.RepoRecordQueue <- RepoRecord{
 					ctx:       ctx,
 					seq:       evt.Seq,
 					pst:       *rec,
+					langs:     langs,
+					tags:      tags,
 					repoName:  evt.Repo,
 					opPath:    op.Path,
 					eventTime: evt.Time,
 				}
 				span.AddEvent("Added to Queue")
 			case *appbsky.FeedLike:
-				span.SetAttributes(attribute.String("repo.name", evt.Repo))
-				span.SetAttributes(attribute.String("event.type", "app.bsky.feed.like"))
-				if rec.Subject != nil {
-					span.SetAttributes(attribute.String("like.subject.uri", rec.Subject.Uri))
-
-					_, postID := path.Split(rec.Subject.Uri)
-					span.SetAttributes(attribute.String("like.subject.post_id", postID))
-
-					// Add the Like to the DB
-					err := bsky.PostRegistry.AddLikeToPost(ctx, postID, evt.Repo)
-					if err != nil {
-						log.Errorf("failed to add like to post: %+v\n", err)
-						span.SetAttributes(attribute.String("error", err.Error()))
-					}
-					likesProcessedCounter.Inc()
-				}
-				return nil
+				// Likes are indexed by a RecordHandler when the PostRegistry is enabled
 			case *appbsky.FeedRepost:
-				// Ignore reposts for now
+				// Reposts are indexed by a RecordHandler when the PostRegistry is enabled
 			case *appbsky.GraphBlock:
-				span.SetAttributes(attribute.String("repo.name", evt.Repo))
-				span.SetAttributes(attribute.String("event.type", "app.bsky.graph.block"))
-				span.SetAttributes(attribute.String("block.subject", rec.Subject))
-				err = bsky.PostRegistry.AddAuthorBlock(ctx, evt.Repo, rec.Subject, t)
-				if err != nil {
-					log.Errorf("failed to add author block to registry: %+v\n", err)
-					return nil
-				}
-				log.Infow("processed graph block", "target", rec.Subject, "source", evt.Repo)
-				return nil
-
+				// Blocks are indexed by a RecordHandler when the PostRegistry is enabled
 			case *appbsky.GraphFollow:
-				// Ignore follows for now
+				// Follows are indexed by a RecordHandler when the PostRegistry is enabled
 			case *appbsky.ActorProfile:
-				// Ignore profile updates for now
+				// Profiles are indexed by a RecordHandler when the PostRegistry is enabled
 			case *appbsky.FeedGenerator:
-				// Ignore feed generator updates for now
+				// Feed generators are indexed by a RecordHandler when the PostRegistry is enabled
 			case *appbsky.GraphList:
-				// Ignore mute list creation for now
+				// Lists are indexed by a RecordHandler when the PostRegistry is enabled
 			case *appbsky.GraphListitem:
-				// Ignore mute list updates for now
+				// List items are indexed by a RecordHandler when the PostRegistry is enabled
 			default:
 				log.Warnf("unknown record type: %+v", rec)
 			}
@@ -285,6 +346,15 @@ func (bsky *BSky) HandleRepoCommit(ctx context.Context, evt *comatproto.SyncSubs
 			deleteRecordsProcessed.Inc()
 			span.SetAttributes(attribute.String("evt.kind", "delete"))
 			span.SetAttributes(attribute.String("op.path", op.Path))
+
+			// Parse time from the event time string
+			t, err := time.Parse(time.RFC3339, evt.Time)
+			if err != nil {
+				log.Errorf("error parsing time: %+v", err)
+				return nil
+			}
+
+			bsky.dispatchToHandler(ctx, evt.Seq, evt.Repo, ek, op.Path, nil, t)
 		}
 	}
 	return nil
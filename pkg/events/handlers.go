@@ -0,0 +1,134 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bluesky-social/indigo/repomgr"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// RecordEvent is a single record operation from a repo commit
+type RecordEvent struct {
+	Seq        int64
+	Repo       string
+	Action     repomgr.EventKind
+	Collection string
+	RKey       string
+	// Record is the decoded record (i.e. *appbsky.GraphFollow), nil for deletes
+	Record    interface{}
+	EventTime time.Time
+}
+
+// URI returns the AT URI of the record
+func (evt *RecordEvent) URI() string {
+	return fmt.Sprintf("at://%s/%s/%s", evt.Repo, evt.Collection, evt.RKey)
+}
+
+// RecordHandler indexes the records of a single collection
+// Handlers are registered with BSky by collection NSID and are called from their own worker pool
+type RecordHandler interface {
+	HandleRecord(ctx context.Context, evt *RecordEvent) error
+}
+
+// RecordHandlerFunc adapts a function to the RecordHandler interface
+type RecordHandlerFunc func(ctx context.Context, evt *RecordEvent) error
+
+func (f RecordHandlerFunc) HandleRecord(ctx context.Context, evt *RecordEvent) error {
+	return f(ctx, evt)
+}
+
+type recordHandlerPool struct {
+	collection string
+	handler    RecordHandler
+	queue      chan *RecordEvent
+}
+
+// RegisterHandler registers a RecordHandler for a collection NSID and starts its worker pool
+// Records in the collection skip the built-in handling in HandleRepoCommit
+func (bsky *BSky) RegisterHandler(ctx context.Context, collection string, handler RecordHandler, workerCount int) {
+	pool := &recordHandlerPool{
+		collection: collection,
+		handler:    handler,
+		queue:      make(chan *RecordEvent, workerCount*10),
+	}
+
+	bsky.handlersMux.Lock()
+	bsky.handlers[collection] = pool
+	bsky.handlersMux.Unlock()
+
+	log := bsky.Logger.With("source", "record_handler", "collection", collection)
+	log.Infof("starting %d workers for %s records", workerCount, collection)
+
+	for i := 0; i < workerCount; i++ {
+		go bsky.recordHandlerWorker(ctx, pool, log)
+	}
+}
+
+// dispatchToHandler hands a record operation off to its registered handler, if one exists
+// It returns false if no handler is registered for the record's collection
+func (bsky *BSky) dispatchToHandler(ctx context.Context, seq int64, repo string, action repomgr.EventKind, opPath string, rec interface{}, eventTime time.Time) bool {
+	collection, rkey, found := strings.Cut(opPath, "/")
+	if !found {
+		return false
+	}
+
+	bsky.handlersMux.RLock()
+	pool, ok := bsky.handlers[collection]
+	bsky.handlersMux.RUnlock()
+	if !ok {
+		return false
+	}
+
+	// Hold the cursor until the handler has processed the record
+	bsky.CursorTracker.Acquire(seq)
+
+	pool.queue <- &RecordEvent{
+		Seq:        seq,
+		Repo:       repo,
+		Action:     action,
+		Collection: collection,
+		RKey:       rkey,
+		Record:     rec,
+		EventTime:  eventTime,
+	}
+	recordHandlerQueueDepth.WithLabelValues(collection).Set(float64(len(pool.queue)))
+
+	return true
+}
+
+func (bsky *BSky) recordHandlerWorker(ctx context.Context, pool *recordHandlerPool, log *zap.SugaredLogger) {
+	tracer := otel.Tracer("graph-builder")
+
+	for {
+		select {
+		case evt := <-pool.queue:
+			recordHandlerQueueDepth.WithLabelValues(pool.collection).Set(float64(len(pool.queue)))
+
+			ctx, span := tracer.Start(ctx, "HandleRecord")
+			span.SetAttributes(attribute.String("record.collection", evt.Collection))
+			span.SetAttributes(attribute.String("record.action", string(evt.Action)))
+			span.SetAttributes(attribute.String("repo.name", evt.Repo))
+
+			start := time.Now()
+			err := pool.handler.HandleRecord(ctx, evt)
+			recordHandlerDuration.WithLabelValues(pool.collection).Observe(time.Since(start).Seconds())
+			recordHandlerEventsCounter.WithLabelValues(pool.collection, string(evt.Action)).Inc()
+			if err != nil {
+				span.RecordError(err)
+				recordHandlerErrorsCounter.WithLabelValues(pool.collection).Inc()
+				log.Errorw("failed to handle record", "uri", evt.URI(), "action", evt.Action, "error", err)
+			}
+
+			span.End()
+			bsky.CursorTracker.Release(evt.Seq)
+		case <-ctx.Done():
+			log.Infof("record handler worker for %s terminating: context was cancelled", pool.collection)
+			return
+		}
+	}
+}
@@ -4,50 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"time"
 
 	appbsky "github.com/bluesky-social/indigo/api/bsky"
-	"github.com/bluesky-social/indigo/xrpc"
 	"github.com/ericvolp12/bsky-experiments/pkg/graph"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 )
 
-var TimeoutError = fmt.Errorf("timeout")
-
-func FeedGetPostsWithTimeout(
-	ctx context.Context,
-	client *xrpc.Client,
-	uris []string,
-	timeout time.Duration,
-) (*appbsky.FeedGetPosts_Output, error) {
-	type result struct {
-		posts *appbsky.FeedGetPosts_Output
-		err   error
-	}
-
-	resultChan := make(chan result, 1)
-
-	ctx, cancel := context.WithCancel(ctx)
-
-	go func() {
-		start := time.Now()
-		posts, err := appbsky.FeedGetPosts(ctx, client, uris)
-		elapsed := time.Since(start)
-		apiCallDurationHistogram.WithLabelValues("FeedGetPosts").Observe(elapsed.Seconds())
-		resultChan <- result{posts: posts, err: err}
-	}()
-
-	select {
-	case res := <-resultChan:
-		cancel()
-		return res.posts, res.err
-	case <-time.After(timeout):
-		cancel()
-		return nil, TimeoutError
-	}
-}
-
 // DecodeFacets decodes the facets of a richtext record into mentions and links
 func (bsky *BSky) DecodeFacets(
 	ctx context.Context,
@@ -0,0 +1,132 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	comatproto "github.com/bluesky-social/indigo/api/atproto"
+	"github.com/ericvolp12/bsky-experiments/pkg/graph"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// bustDIDCache removes a DID's cached handle and PDS so the next lookup goes to the directory
+func (bsky *BSky) bustDIDCache(ctx context.Context, did string) {
+	err := bsky.redisClient.Del(ctx,
+		bsky.cachesPrefix+":did:"+did,
+		bsky.cachesPrefix+":pds:"+did,
+		bsky.cachesPrefix+":pds-failed:"+did,
+	).Err()
+	if err != nil {
+		bsky.Logger.Errorf("failed to bust DID cache for %s: %+v", did, err)
+	}
+}
+
+// HandleRepoHandle is called when a repo changes its handle
+func (bsky *BSky) HandleRepoHandle(ctx context.Context, evt *comatproto.SyncSubscribeRepos_Handle) error {
+	tracer := otel.Tracer("graph-builder")
+	ctx, span := tracer.Start(ctx, "HandleRepoHandle")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("repo.name", evt.Did))
+	span.SetAttributes(attribute.String("repo.handle", evt.Handle))
+
+	log := bsky.Logger.With("repo", evt.Did, "seq", evt.Seq)
+	log.Infof("handle changed to %s", evt.Handle)
+
+	bsky.bustDIDCache(ctx, evt.Did)
+
+	if bsky.PostRegistryEnabled {
+		err := bsky.PostRegistry.UpdateAuthorHandle(ctx, evt.Did, evt.Handle)
+		if err != nil {
+			log.Errorf("failed to update author handle: %+v", err)
+		}
+	}
+
+	node := graph.Node{DID: graph.NodeID(evt.Did), Handle: evt.Handle}
+
+	err := bsky.PersistedGraph.UpdateNodeHandle(ctx, node)
+	if err != nil {
+		log.Errorf("failed to update handle in persisted graph: %+v", err)
+	}
+
+	err = bsky.FollowGraph.UpdateNodeHandle(ctx, node)
+	if err != nil {
+		log.Errorf("failed to update handle in follow graph: %+v", err)
+	}
+
+	identityEventsCounter.WithLabelValues("handle").Inc()
+
+	return nil
+}
+
+// HandleRepoMigrate is called when a repo moves to a different PDS
+func (bsky *BSky) HandleRepoMigrate(ctx context.Context, evt *comatproto.SyncSubscribeRepos_Migrate) error {
+	tracer := otel.Tracer("graph-builder")
+	ctx, span := tracer.Start(ctx, "HandleRepoMigrate")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("repo.name", evt.Did))
+
+	migrateTo := ""
+	if evt.MigrateTo != nil {
+		migrateTo = *evt.MigrateTo
+	}
+	span.SetAttributes(attribute.String("repo.migrate_to", migrateTo))
+
+	bsky.Logger.With("repo", evt.Did, "seq", evt.Seq).Infof("repo migrated to %s", migrateTo)
+
+	// The new PDS may serve a different handle for the DID and serves its blobs from now on
+	bsky.bustDIDCache(ctx, evt.Did)
+
+	identityEventsCounter.WithLabelValues("migrate").Inc()
+
+	return nil
+}
+
+// HandleRepoTombstone is called when a repo is deleted
+// Tombstoned authors are marked deleted in the PostRegistry and removed from the graphs
+func (bsky *BSky) HandleRepoTombstone(ctx context.Context, evt *comatproto.SyncSubscribeRepos_Tombstone) error {
+	tracer := otel.Tracer("graph-builder")
+	ctx, span := tracer.Start(ctx, "HandleRepoTombstone")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("repo.name", evt.Did))
+
+	log := bsky.Logger.With("repo", evt.Did, "seq", evt.Seq)
+	log.Info("repo tombstoned")
+
+	t, err := time.Parse(time.RFC3339, evt.Time)
+	if err != nil {
+		log.Errorf("error parsing time: %+v", err)
+		t = time.Now()
+	}
+
+	bsky.bustDIDCache(ctx, evt.Did)
+
+	if bsky.PostRegistryEnabled {
+		err = bsky.PostRegistry.AddAuthorTombstone(ctx, evt.Did, t)
+		if err != nil {
+			log.Errorf("failed to add author tombstone: %+v", err)
+		}
+
+		err = bsky.PostRegistry.DeleteAuthorProfile(ctx, evt.Did)
+		if err != nil {
+			log.Errorf("failed to delete author profile: %+v", err)
+		}
+	}
+
+	err = bsky.PersistedGraph.RemoveNode(ctx, graph.NodeID(evt.Did))
+	if err != nil {
+		log.Errorf("failed to remove node from persisted graph: %+v", err)
+	}
+
+	err = bsky.FollowGraph.RemoveNode(ctx, graph.NodeID(evt.Did))
+	if err != nil {
+		log.Errorf("failed to remove node from follow graph: %+v", err)
+	}
+
+	identityEventsCounter.WithLabelValues("tombstone").Inc()
+
+	return nil
+}
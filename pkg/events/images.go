@@ -0,0 +1,117 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/plc"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Image presets substituted for {preset} in the image CDN template
+const (
+	fullsizeImagePreset  = "feed_fullsize"
+	thumbnailImagePreset = "feed_thumbnail"
+)
+
+// ImageURLs returns the fullsize and thumbnail URLs of an image blob
+// If an image CDN template is configured its {did}, {cid}, and {preset} placeholders are filled in,
+// otherwise both URLs fetch the original blob from the author's PDS
+func (bsky *BSky) ImageURLs(ctx context.Context, did string, cid string) (fullsize string, thumbnail string, err error) {
+	if bsky.imageCDNTemplate != "" {
+		return imageCDNURL(bsky.imageCDNTemplate, did, cid, fullsizeImagePreset),
+			imageCDNURL(bsky.imageCDNTemplate, did, cid, thumbnailImagePreset),
+			nil
+	}
+
+	pds, err := bsky.ResolvePDS(ctx, did)
+	if err != nil {
+		return "", "", fmt.Errorf("error resolving PDS for %s: %w", did, err)
+	}
+
+	blobURL := pdsBlobURL(pds, did, cid)
+
+	return blobURL, blobURL, nil
+}
+
+func imageCDNURL(template string, did string, cid string, preset string) string {
+	return strings.NewReplacer("{did}", did, "{cid}", cid, "{preset}", preset).Replace(template)
+}
+
+func pdsBlobURL(pds string, did string, cid string) string {
+	query := url.Values{}
+	query.Set("did", did)
+	query.Set("cid", cid)
+	return strings.TrimSuffix(pds, "/") + "/xrpc/com.atproto.sync.getBlob?" + query.Encode()
+}
+
+// ResolvePDS resolves the PDS endpoint of a DID from its DID document using the cache or the directory
+// Failures are cached too so a DID whose document host is down isn't fetched again for every event
+func (bsky *BSky) ResolvePDS(ctx context.Context, did string) (string, error) {
+	tracer := otel.Tracer("graph-builder")
+	ctx, span := tracer.Start(ctx, "ResolvePDS")
+	defer span.End()
+
+	cacheKey := bsky.cachesPrefix + ":pds:" + did
+
+	// Check the cache first
+	pdsFromCache, err := bsky.redisClient.Get(ctx, cacheKey).Result()
+	if err == nil {
+		span.SetAttributes(attribute.Bool("caches.pds.hit", true))
+		cacheHits.WithLabelValues("pds").Inc()
+		return pdsFromCache, nil
+	} else if err != redis.Nil {
+		span.SetAttributes(attribute.String("caches.pds.get.error", err.Error()))
+	}
+
+	span.SetAttributes(attribute.Bool("caches.pds.hit", false))
+	cacheMisses.WithLabelValues("pds").Inc()
+
+	failureKey := bsky.cachesPrefix + ":pds-failed:" + did
+	failure, err := bsky.redisClient.Get(ctx, failureKey).Result()
+	if err == nil {
+		span.SetAttributes(attribute.Bool("caches.pds.failed", true))
+		return "", fmt.Errorf("PDS resolution failed recently: %s", failure)
+	} else if err != redis.Nil {
+		span.SetAttributes(attribute.String("caches.pds-failed.get.error", err.Error()))
+	}
+
+	err = bsky.directoryLimiter.Wait(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error waiting for rate limiter: %w", err)
+	}
+
+	pds, err := bsky.resolvePDS(ctx, did)
+	if err != nil {
+		// Don't cache failures caused by the caller going away
+		if ctx.Err() == nil {
+			setErr := bsky.redisClient.Set(ctx, failureKey, err.Error(), bsky.pdsFailureTTL).Err()
+			if setErr != nil {
+				span.SetAttributes(attribute.String("caches.pds-failed.set.error", setErr.Error()))
+			}
+		}
+		return "", err
+	}
+
+	span.SetAttributes(attribute.String("pds", pds))
+
+	err = bsky.redisClient.Set(ctx, cacheKey, pds, bsky.profileCacheTTL).Err()
+	if err != nil {
+		span.SetAttributes(attribute.String("caches.pds.set.error", err.Error()))
+	}
+
+	return pds, nil
+}
+
+func (bsky *BSky) resolvePDS(ctx context.Context, did string) (string, error) {
+	doc, err := plc.ResolveDIDDocument(ctx, bsky.directoryClient, "https://plc.directory", did)
+	if err != nil {
+		return "", err
+	}
+
+	return doc.ServiceEndpoint("#atproto_pds")
+}
@@ -0,0 +1,22 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageURLs(t *testing.T) {
+	did := "did:plc:q6gjnaw2blty4crticxkmujt"
+	cid := "bafkreigh2akiscaildcqabsyg3dfr6chu3fgpregiymsck7e7aqa4s52zy"
+
+	assert.Equal(t,
+		"https://cdn.example.com/img/feed_thumbnail/plain/did:plc:q6gjnaw2blty4crticxkmujt/bafkreigh2akiscaildcqabsyg3dfr6chu3fgpregiymsck7e7aqa4s52zy@jpeg",
+		imageCDNURL("https://cdn.example.com/img/{preset}/plain/{did}/{cid}@jpeg", did, cid, thumbnailImagePreset),
+	)
+
+	assert.Equal(t,
+		"https://bsky.social/xrpc/com.atproto.sync.getBlob?cid=bafkreigh2akiscaildcqabsyg3dfr6chu3fgpregiymsck7e7aqa4s52zy&did=did%3Aplc%3Aq6gjnaw2blty4crticxkmujt",
+		pdsBlobURL("https://bsky.social/", did, cid),
+	)
+}
@@ -0,0 +1,66 @@
+package events
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+// maxPostLangs bounds the langs array we'll read from a post record
+const maxPostLangs = 64
+
+// DecodePostLangs reads the langs field from a raw app.bsky.feed.post record
+// Our version of appbsky.FeedPost predates the field, so it's dropped when the record is decoded
+func DecodePostLangs(raw []byte) ([]string, error) {
+	cr := cbg.NewCborReader(bytes.NewReader(raw))
+
+	maj, fields, err := cr.ReadHeader()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read record header: %w", err)
+	}
+	if maj != cbg.MajMap {
+		return nil, fmt.Errorf("record is not a map (major type %d)", maj)
+	}
+
+	for i := uint64(0); i < fields; i++ {
+		key, err := cbg.ReadString(cr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read record field name: %w", err)
+		}
+
+		if key != "langs" {
+			// Skip over the value of fields we don't care about
+			err = cbg.ScanForLinks(cr, func(cid.Cid) {})
+			if err != nil {
+				return nil, fmt.Errorf("failed to skip record field %q: %w", key, err)
+			}
+			continue
+		}
+
+		maj, n, err := cr.ReadHeader()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read langs header: %w", err)
+		}
+		if maj != cbg.MajArray {
+			return nil, fmt.Errorf("langs is not an array (major type %d)", maj)
+		}
+		if n > maxPostLangs {
+			return nil, fmt.Errorf("langs has too many entries (%d)", n)
+		}
+
+		langs := make([]string, 0, n)
+		for j := uint64(0); j < n; j++ {
+			lang, err := cbg.ReadString(cr)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read lang: %w", err)
+			}
+			langs = append(langs, lang)
+		}
+
+		return langs, nil
+	}
+
+	return nil, nil
+}
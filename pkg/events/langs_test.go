@@ -0,0 +1,52 @@
+package events
+
+import (
+	"bytes"
+	"testing"
+
+	appbsky "github.com/bluesky-social/indigo/api/bsky"
+	"github.com/stretchr/testify/assert"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+func TestDecodePostLangs(t *testing.T) {
+	// A post record without langs
+	buf := new(bytes.Buffer)
+	err := (&appbsky.FeedPost{
+		LexiconTypeID: "app.bsky.feed.post",
+		CreatedAt:     "2023-06-20T12:00:00Z",
+		Text:          "hello world",
+		Facets:        []*appbsky.RichtextFacet{},
+	}).MarshalCBOR(buf)
+	assert.NoError(t, err)
+
+	langs, err := DecodePostLangs(buf.Bytes())
+	assert.NoError(t, err)
+	assert.Nil(t, langs)
+
+	// A post record with langs after other fields
+	buf = new(bytes.Buffer)
+	cw := cbg.NewCborWriter(buf)
+	assert.NoError(t, cw.WriteMajorTypeHeader(cbg.MajMap, 3))
+	for _, s := range []string{"text", "olá mundo", "createdAt", "2023-06-20T12:00:00Z", "langs"} {
+		writeCborString(t, cw, s)
+	}
+	assert.NoError(t, cw.WriteMajorTypeHeader(cbg.MajArray, 2))
+	for _, s := range []string{"pt-BR", "en"} {
+		writeCborString(t, cw, s)
+	}
+
+	langs, err = DecodePostLangs(buf.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pt-BR", "en"}, langs)
+
+	// Not a map
+	_, err = DecodePostLangs([]byte{0x80})
+	assert.Error(t, err)
+}
+
+func writeCborString(t *testing.T, cw *cbg.CborWriter, s string) {
+	assert.NoError(t, cw.WriteMajorTypeHeader(cbg.MajTextString, uint64(len(s))))
+	_, err := cw.WriteString(s)
+	assert.NoError(t, err)
+}
@@ -91,3 +91,86 @@ var lastSeqCreatedAt = promauto.NewGauge(prometheus.GaugeOpts{
 	Name: "bsky_last_seq_created_at",
 	Help: "The timestamp of the last sequence number created",
 })
+
+// Initialize Prometheus metrics for cursor durability and gap detection
+var committedCursor = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "bsky_committed_cursor",
+	Help: "The last cursor committed, every event at or below it has been fully processed",
+})
+
+var inFlightEventsGauge = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "bsky_in_flight_events",
+	Help: "The number of firehose events read off the stream but not yet fully processed",
+})
+
+var seqGapsCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "bsky_seq_gaps_total",
+	Help: "The total number of gaps detected in the firehose sequence",
+})
+
+var seqGapSizeCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "bsky_seq_gap_missed_events_total",
+	Help: "The total number of sequence numbers skipped over by detected gaps",
+})
+
+var seqReplayedCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "bsky_seq_replayed_total",
+	Help: "The total number of events received at or below an already dispatched sequence number",
+})
+
+// Initialize Prometheus metrics for registered record handlers
+var recordHandlerEventsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "bsky_record_handler_events_total",
+	Help: "The total number of record operations processed by record handlers",
+}, []string{"collection", "action"})
+
+var recordHandlerErrorsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "bsky_record_handler_errors_total",
+	Help: "The total number of record operations record handlers failed to process",
+}, []string{"collection"})
+
+var recordHandlerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "bsky_record_handler_duration_seconds",
+	Help:    "The duration of processing a record operation in a record handler",
+	Buckets: prometheus.ExponentialBuckets(0.001, 2, 15),
+}, []string{"collection"})
+
+var recordHandlerQueueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "bsky_record_handler_queue_depth",
+	Help: "The number of record operations waiting on a record handler",
+}, []string{"collection"})
+
+var followsProcessedCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "bsky_follows_processed_total",
+	Help: "The total number of follows processed",
+})
+
+var unfollowsProcessedCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "bsky_unfollows_processed_total",
+	Help: "The total number of unfollows processed",
+})
+
+var repostsProcessedCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "bsky_reposts_processed_total",
+	Help: "The total number of reposts processed",
+})
+
+var unrepostsProcessedCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "bsky_unreposts_processed_total",
+	Help: "The total number of repost deletes processed",
+})
+
+var profilesProcessedCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "bsky_profiles_processed_total",
+	Help: "The total number of profile updates processed",
+})
+
+var feedGeneratorsProcessedCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "bsky_feed_generators_processed_total",
+	Help: "The total number of feed generator updates processed",
+})
+
+var identityEventsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "bsky_identity_events_total",
+	Help: "The total number of handle, migrate, and tombstone events processed",
+}, []string{"event_type"})
@@ -0,0 +1,387 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	appbsky "github.com/bluesky-social/indigo/api/bsky"
+	"github.com/bluesky-social/indigo/repomgr"
+	"github.com/ericvolp12/bsky-experiments/pkg/graph"
+	"github.com/ericvolp12/bsky-experiments/pkg/search"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HandleLike adds likes to the like counts of their subject posts in the PostRegistry
+func (bsky *BSky) HandleLike(ctx context.Context, evt *RecordEvent) error {
+	// Likes can't be removed without their subject, which deletes don't include
+	if evt.Action == repomgr.EvtKindDeleteRecord {
+		return nil
+	}
+
+	rec, ok := evt.Record.(*appbsky.FeedLike)
+	if !ok {
+		return fmt.Errorf("unexpected record type for like: %T", evt.Record)
+	}
+
+	if rec.Subject == nil {
+		return nil
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("like.subject.uri", rec.Subject.Uri))
+
+	// Feed generators can be liked too, they're counted separately from posts
+	if strings.Contains(rec.Subject.Uri, "/app.bsky.feed.generator/") {
+		err := bsky.PostRegistry.AddLikeToFeedGenerator(ctx, rec.Subject.Uri)
+		if err != nil {
+			return fmt.Errorf("failed to add like to feed generator: %w", err)
+		}
+		return nil
+	}
+
+	_, postID := path.Split(rec.Subject.Uri)
+	span.SetAttributes(attribute.String("like.subject.post_id", postID))
+
+	// Add the Like to the DB
+	err := bsky.BatchWriter.AddLikeToPost(ctx, postID, evt.Repo)
+	if err != nil {
+		return fmt.Errorf("failed to add like to post: %w", err)
+	}
+
+	likesProcessedCounter.Inc()
+
+	return nil
+}
+
+// HandleBlock records blocks between authors in the PostRegistry
+func (bsky *BSky) HandleBlock(ctx context.Context, evt *RecordEvent) error {
+	// Block deletes don't include their subject so they can't be removed yet
+	if evt.Action == repomgr.EvtKindDeleteRecord {
+		return nil
+	}
+
+	rec, ok := evt.Record.(*appbsky.GraphBlock)
+	if !ok {
+		return fmt.Errorf("unexpected record type for block: %T", evt.Record)
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("block.subject", rec.Subject))
+
+	err := bsky.PostRegistry.AddAuthorBlock(ctx, evt.Repo, rec.Subject, evt.EventTime)
+	if err != nil {
+		return fmt.Errorf("failed to add author block to registry: %w", err)
+	}
+
+	bsky.Logger.Infow("processed graph block", "target", rec.Subject, "source", evt.Repo)
+
+	return nil
+}
+
+// HandleFollow records follows and unfollows in the PostRegistry and the follow graph
+func (bsky *BSky) HandleFollow(ctx context.Context, evt *RecordEvent) error {
+	span := trace.SpanFromContext(ctx)
+
+	// Unfollows only include the rkey, so the registry tells us who was unfollowed
+	if evt.Action == repomgr.EvtKindDeleteRecord {
+		targetDID, err := bsky.PostRegistry.RemoveFollow(ctx, evt.Repo, evt.RKey)
+		if err != nil {
+			// The follow was created before we started indexing follows
+			if errors.As(err, &search.NotFoundError{}) {
+				return nil
+			}
+			return fmt.Errorf("failed to remove follow from registry: %w", err)
+		}
+
+		span.SetAttributes(attribute.String("follow.subject", targetDID))
+
+		from := graph.Node{DID: graph.NodeID(evt.Repo)}
+		to := graph.Node{DID: graph.NodeID(targetDID)}
+		err = bsky.FollowGraph.DecrementEdge(ctx, from, to, 1)
+		if err != nil {
+			return fmt.Errorf("failed to remove follow from follow graph: %w", err)
+		}
+
+		unfollowsProcessedCounter.Inc()
+		return nil
+	}
+
+	rec, ok := evt.Record.(*appbsky.GraphFollow)
+	if !ok {
+		return fmt.Errorf("unexpected record type for follow: %T", evt.Record)
+	}
+
+	span.SetAttributes(attribute.String("follow.subject", rec.Subject))
+
+	inserted, err := bsky.PostRegistry.AddFollow(ctx, evt.Repo, evt.RKey, rec.Subject, evt.EventTime)
+	if err != nil {
+		return fmt.Errorf("failed to add follow to registry: %w", err)
+	}
+
+	// Replayed follows are already in the follow graph
+	if !inserted {
+		return nil
+	}
+
+	from := graph.Node{DID: graph.NodeID(evt.Repo), Handle: bsky.knownHandle(ctx, evt.Repo)}
+	to := graph.Node{DID: graph.NodeID(rec.Subject), Handle: bsky.knownHandle(ctx, rec.Subject)}
+	err = bsky.FollowGraph.IncrementEdge(ctx, from, to, 1)
+	if err != nil {
+		return fmt.Errorf("failed to add follow to follow graph: %w", err)
+	}
+
+	followsProcessedCounter.Inc()
+
+	return nil
+}
+
+// HandleRepost records reposts in the PostRegistry and as interactions in the social graph
+func (bsky *BSky) HandleRepost(ctx context.Context, evt *RecordEvent) error {
+	span := trace.SpanFromContext(ctx)
+
+	// Repost deletes only include the rkey, so the registry tells us what was reposted
+	if evt.Action == repomgr.EvtKindDeleteRecord {
+		removed, err := bsky.PostRegistry.RemoveRepost(ctx, evt.Repo, evt.RKey)
+		if err != nil {
+			// The repost was created before we started indexing reposts
+			if errors.As(err, &search.NotFoundError{}) {
+				return nil
+			}
+			return fmt.Errorf("failed to remove repost from registry: %w", err)
+		}
+
+		span.SetAttributes(attribute.String("repost.subject.post_id", removed.PostID))
+
+		if removed.AuthorDID != nil {
+			from := graph.Node{DID: graph.NodeID(evt.Repo)}
+			to := graph.Node{DID: graph.NodeID(*removed.AuthorDID)}
+			err = bsky.PersistedGraph.DecrementEdge(ctx, from, to, 1)
+			if err != nil {
+				return fmt.Errorf("failed to remove repost from persisted graph: %w", err)
+			}
+		}
+
+		unrepostsProcessedCounter.Inc()
+		return nil
+	}
+
+	rec, ok := evt.Record.(*appbsky.FeedRepost)
+	if !ok {
+		return fmt.Errorf("unexpected record type for repost: %T", evt.Record)
+	}
+
+	if rec.Subject == nil {
+		return nil
+	}
+
+	span.SetAttributes(attribute.String("repost.subject.uri", rec.Subject.Uri))
+
+	slicedURI := strings.TrimPrefix(rec.Subject.Uri, "at://")
+	authorDID, _, _ := strings.Cut(slicedURI, "/")
+	_, postID := path.Split(rec.Subject.Uri)
+	span.SetAttributes(attribute.String("repost.subject.post_id", postID))
+	span.SetAttributes(attribute.String("repost.subject.author_did", authorDID))
+
+	inserted, err := bsky.PostRegistry.AddRepost(ctx, evt.Repo, evt.RKey, postID, authorDID, evt.EventTime)
+	if err != nil {
+		return fmt.Errorf("failed to add repost to registry: %w", err)
+	}
+
+	// Replayed reposts are already in the graph
+	if !inserted {
+		return nil
+	}
+
+	from := graph.Node{DID: graph.NodeID(evt.Repo), Handle: bsky.knownHandle(ctx, evt.Repo)}
+	to := graph.Node{DID: graph.NodeID(authorDID), Handle: bsky.knownHandle(ctx, authorDID)}
+	err = bsky.PersistedGraph.IncrementEdge(ctx, from, to, 1)
+	if err != nil {
+		return fmt.Errorf("failed to add repost to persisted graph: %w", err)
+	}
+
+	repostsProcessedCounter.Inc()
+
+	return nil
+}
+
+// HandleProfile records display names, descriptions, and avatars from profile records in the PostRegistry
+func (bsky *BSky) HandleProfile(ctx context.Context, evt *RecordEvent) error {
+	// Only the self profile is shown by clients
+	if evt.RKey != "self" {
+		return nil
+	}
+
+	if evt.Action == repomgr.EvtKindDeleteRecord {
+		err := bsky.PostRegistry.DeleteAuthorProfile(ctx, evt.Repo)
+		if err != nil {
+			return fmt.Errorf("failed to delete author profile from registry: %w", err)
+		}
+		return nil
+	}
+
+	rec, ok := evt.Record.(*appbsky.ActorProfile)
+	if !ok {
+		return fmt.Errorf("unexpected record type for profile: %T", evt.Record)
+	}
+
+	profile := &search.AuthorProfile{
+		DID:         evt.Repo,
+		DisplayName: rec.DisplayName,
+		Description: rec.Description,
+		UpdatedAt:   evt.EventTime,
+	}
+
+	if rec.Avatar != nil {
+		avatarCID := rec.Avatar.Ref.String()
+		profile.AvatarCID = &avatarCID
+	}
+
+	if rec.Banner != nil {
+		bannerCID := rec.Banner.Ref.String()
+		profile.BannerCID = &bannerCID
+	}
+
+	err := bsky.PostRegistry.UpsertAuthorProfile(ctx, profile)
+	if err != nil {
+		return fmt.Errorf("failed to upsert author profile in registry: %w", err)
+	}
+
+	profilesProcessedCounter.Inc()
+
+	return nil
+}
+
+// HandleList records list metadata in the PostRegistry
+func (bsky *BSky) HandleList(ctx context.Context, evt *RecordEvent) error {
+	if evt.Action == repomgr.EvtKindDeleteRecord {
+		err := bsky.PostRegistry.DeleteList(ctx, evt.URI())
+		if err != nil {
+			return fmt.Errorf("failed to delete list from registry: %w", err)
+		}
+		return nil
+	}
+
+	rec, ok := evt.Record.(*appbsky.GraphList)
+	if !ok {
+		return fmt.Errorf("unexpected record type for list: %T", evt.Record)
+	}
+
+	purpose := ""
+	if rec.Purpose != nil {
+		purpose = *rec.Purpose
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("list.purpose", purpose))
+
+	err := bsky.PostRegistry.UpsertList(ctx, &search.List{
+		URI:         evt.URI(),
+		CreatorDID:  evt.Repo,
+		Name:        rec.Name,
+		Purpose:     purpose,
+		Description: rec.Description,
+		CreatedAt:   evt.EventTime,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert list in registry: %w", err)
+	}
+
+	return nil
+}
+
+// HandleListItem records list membership in the PostRegistry
+func (bsky *BSky) HandleListItem(ctx context.Context, evt *RecordEvent) error {
+	if evt.Action == repomgr.EvtKindDeleteRecord {
+		err := bsky.PostRegistry.DeleteListItem(ctx, evt.URI())
+		if err != nil {
+			return fmt.Errorf("failed to delete list item from registry: %w", err)
+		}
+		return nil
+	}
+
+	rec, ok := evt.Record.(*appbsky.GraphListitem)
+	if !ok {
+		return fmt.Errorf("unexpected record type for list item: %T", evt.Record)
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("list_item.list", rec.List))
+	span.SetAttributes(attribute.String("list_item.subject", rec.Subject))
+
+	// Only a list's owner can add to it, otherwise anyone could inject subjects into someone else's mod list
+	listOwnerDID, _, _ := strings.Cut(strings.TrimPrefix(rec.List, "at://"), "/")
+	if listOwnerDID != evt.Repo {
+		span.SetAttributes(attribute.Bool("list_item.rejected", true))
+		return nil
+	}
+
+	err := bsky.PostRegistry.AddListItem(ctx, &search.ListItem{
+		URI:        evt.URI(),
+		CreatorDID: evt.Repo,
+		ListURI:    rec.List,
+		SubjectDID: rec.Subject,
+		CreatedAt:  evt.EventTime,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add list item to registry: %w", err)
+	}
+
+	return nil
+}
+
+// HandleFeedGenerator records feed generator metadata in the PostRegistry
+func (bsky *BSky) HandleFeedGenerator(ctx context.Context, evt *RecordEvent) error {
+	if evt.Action == repomgr.EvtKindDeleteRecord {
+		err := bsky.PostRegistry.DeleteFeedGenerator(ctx, evt.URI())
+		if err != nil {
+			return fmt.Errorf("failed to delete feed generator from registry: %w", err)
+		}
+		return nil
+	}
+
+	rec, ok := evt.Record.(*appbsky.FeedGenerator)
+	if !ok {
+		return fmt.Errorf("unexpected record type for feed generator: %T", evt.Record)
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("feed_generator.service_did", rec.Did))
+
+	createdAt, err := time.Parse(time.RFC3339, rec.CreatedAt)
+	if err != nil {
+		createdAt = evt.EventTime
+	}
+
+	err = bsky.PostRegistry.UpsertFeedGenerator(ctx, &search.FeedGenerator{
+		URI:         evt.URI(),
+		CreatorDID:  evt.Repo,
+		RKey:        evt.RKey,
+		DisplayName: rec.DisplayName,
+		Description: rec.Description,
+		ServiceDID:  rec.Did,
+		CreatedAt:   createdAt,
+		UpdatedAt:   evt.EventTime,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert feed generator in registry: %w", err)
+	}
+
+	feedGeneratorsProcessedCounter.Inc()
+
+	return nil
+}
+
+// knownHandle looks up the handle of an author we've already indexed
+// Follows are too frequent to resolve every DID against the directory, so unknown authors get an empty handle
+func (bsky *BSky) knownHandle(ctx context.Context, did string) string {
+	author, err := bsky.PostRegistry.GetAuthor(ctx, did)
+	if err != nil {
+		return ""
+	}
+	return author.Handle
+}
@@ -0,0 +1,160 @@
+package events
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+// maxPostFacets bounds the facets and features arrays we'll read from a post record
+const maxPostFacets = 256
+
+// tagFeatureType is the $type of a richtext facet feature that tags a post
+const tagFeatureType = "app.bsky.richtext.facet#tag"
+
+// DecodePostTags reads the tags from the tag facets of a raw app.bsky.feed.post record
+// Our version of appbsky.RichtextFacet has no tag feature, so they're dropped when the record is decoded
+// A tag facet doesn't have to cover a "#tag" in the text, so this is the only way to see every tag on a post
+func DecodePostTags(raw []byte) ([]string, error) {
+	cr := cbg.NewCborReader(bytes.NewReader(raw))
+
+	fields, err := readMapHeader(cr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read record header: %w", err)
+	}
+
+	for i := uint64(0); i < fields; i++ {
+		key, err := cbg.ReadString(cr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read record field name: %w", err)
+		}
+
+		if key != "facets" {
+			err = cbg.ScanForLinks(cr, func(cid.Cid) {})
+			if err != nil {
+				return nil, fmt.Errorf("failed to skip record field %q: %w", key, err)
+			}
+			continue
+		}
+
+		facets, err := readArrayHeader(cr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read facets header: %w", err)
+		}
+
+		tags := []string{}
+		for j := uint64(0); j < facets; j++ {
+			facetTags, err := decodeFacetTags(cr)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read facet %d: %w", j, err)
+			}
+			tags = append(tags, facetTags...)
+		}
+
+		return tags, nil
+	}
+
+	return nil, nil
+}
+
+// decodeFacetTags reads a single facet and returns the tags of its tag features
+func decodeFacetTags(cr *cbg.CborReader) ([]string, error) {
+	fields, err := readMapHeader(cr)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []string{}
+	for i := uint64(0); i < fields; i++ {
+		key, err := cbg.ReadString(cr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read facet field name: %w", err)
+		}
+
+		if key != "features" {
+			err = cbg.ScanForLinks(cr, func(cid.Cid) {})
+			if err != nil {
+				return nil, fmt.Errorf("failed to skip facet field %q: %w", key, err)
+			}
+			continue
+		}
+
+		features, err := readArrayHeader(cr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read features header: %w", err)
+		}
+
+		for j := uint64(0); j < features; j++ {
+			tag, ok, err := decodeTagFeature(cr)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read feature %d: %w", j, err)
+			}
+			if ok {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	return tags, nil
+}
+
+// decodeTagFeature reads a single facet feature and returns its tag if it's a tag feature
+func decodeTagFeature(cr *cbg.CborReader) (string, bool, error) {
+	fields, err := readMapHeader(cr)
+	if err != nil {
+		return "", false, err
+	}
+
+	var featureType, tag string
+	for i := uint64(0); i < fields; i++ {
+		key, err := cbg.ReadString(cr)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read feature field name: %w", err)
+		}
+
+		switch key {
+		case "$type":
+			featureType, err = cbg.ReadString(cr)
+		case "tag":
+			tag, err = cbg.ReadString(cr)
+		default:
+			err = cbg.ScanForLinks(cr, func(cid.Cid) {})
+		}
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read feature field %q: %w", key, err)
+		}
+	}
+
+	if featureType != tagFeatureType || tag == "" {
+		return "", false, nil
+	}
+
+	return tag, true, nil
+}
+
+func readMapHeader(cr *cbg.CborReader) (uint64, error) {
+	maj, n, err := cr.ReadHeader()
+	if err != nil {
+		return 0, err
+	}
+	if maj != cbg.MajMap {
+		return 0, fmt.Errorf("not a map (major type %d)", maj)
+	}
+	return n, nil
+}
+
+func readArrayHeader(cr *cbg.CborReader) (uint64, error) {
+	maj, n, err := cr.ReadHeader()
+	if err != nil {
+		return 0, err
+	}
+	if maj != cbg.MajArray {
+		return 0, fmt.Errorf("not an array (major type %d)", maj)
+	}
+	if n > maxPostFacets {
+		return 0, fmt.Errorf("array has too many entries (%d)", n)
+	}
+	return n, nil
+}
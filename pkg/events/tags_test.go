@@ -0,0 +1,78 @@
+package events
+
+import (
+	"bytes"
+	"testing"
+
+	appbsky "github.com/bluesky-social/indigo/api/bsky"
+	"github.com/stretchr/testify/assert"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+func TestDecodePostTags(t *testing.T) {
+	// A post record with a link facet and no tags
+	buf := new(bytes.Buffer)
+	err := (&appbsky.FeedPost{
+		LexiconTypeID: "app.bsky.feed.post",
+		CreatedAt:     "2023-06-20T12:00:00Z",
+		Text:          "example.com",
+		Facets: []*appbsky.RichtextFacet{{
+			Index: &appbsky.RichtextFacet_ByteSlice{ByteStart: 0, ByteEnd: 11},
+			Features: []*appbsky.RichtextFacet_Features_Elem{{
+				RichtextFacet_Link: &appbsky.RichtextFacet_Link{
+					LexiconTypeID: "app.bsky.richtext.facet#link",
+					Uri:           "https://example.com",
+				},
+			}},
+		}},
+	}).MarshalCBOR(buf)
+	assert.NoError(t, err)
+
+	tags, err := DecodePostTags(buf.Bytes())
+	assert.NoError(t, err)
+	assert.Empty(t, tags)
+
+	// A post record with tag facets that don't appear in its text
+	buf = new(bytes.Buffer)
+	cw := cbg.NewCborWriter(buf)
+	assert.NoError(t, cw.WriteMajorTypeHeader(cbg.MajMap, 2))
+	for _, s := range []string{"text", "look at this", "facets"} {
+		writeCborString(t, cw, s)
+	}
+	assert.NoError(t, cw.WriteMajorTypeHeader(cbg.MajArray, 1))
+	assert.NoError(t, cw.WriteMajorTypeHeader(cbg.MajMap, 2))
+	writeCborString(t, cw, "index")
+	assert.NoError(t, cw.WriteMajorTypeHeader(cbg.MajMap, 0))
+	writeCborString(t, cw, "features")
+	assert.NoError(t, cw.WriteMajorTypeHeader(cbg.MajArray, 3))
+	for _, feature := range [][]string{
+		{"$type", "app.bsky.richtext.facet#tag", "tag", "Space"},
+		{"$type", "app.bsky.richtext.facet#link", "uri", "https://example.com"},
+		{"tag", "JWST", "$type", "app.bsky.richtext.facet#tag"},
+	} {
+		assert.NoError(t, cw.WriteMajorTypeHeader(cbg.MajMap, 2))
+		for _, s := range feature {
+			writeCborString(t, cw, s)
+		}
+	}
+
+	tags, err = DecodePostTags(buf.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Space", "JWST"}, tags)
+
+	// A post record without facets
+	buf = new(bytes.Buffer)
+	cw = cbg.NewCborWriter(buf)
+	assert.NoError(t, cw.WriteMajorTypeHeader(cbg.MajMap, 1))
+	for _, s := range []string{"text", "hello world"} {
+		writeCborString(t, cw, s)
+	}
+
+	tags, err = DecodePostTags(buf.Bytes())
+	assert.NoError(t, err)
+	assert.Nil(t, tags)
+
+	// Not a map
+	_, err = DecodePostTags([]byte{0x80})
+	assert.Error(t, err)
+}
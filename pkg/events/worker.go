@@ -4,12 +4,11 @@ import (
 	"context"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	appbsky "github.com/bluesky-social/indigo/api/bsky"
-	"github.com/bluesky-social/indigo/xrpc"
 	"github.com/ericvolp12/bsky-experiments/pkg/graph"
+	"github.com/ericvolp12/bsky-experiments/pkg/language"
 	"github.com/ericvolp12/bsky-experiments/pkg/search"
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel"
@@ -18,10 +17,8 @@ import (
 )
 
 type Worker struct {
-	WorkerID  int
-	Client    *xrpc.Client
-	ClientMux sync.RWMutex
-	Logger    *zap.SugaredLogger
+	WorkerID int
+	Logger   *zap.SugaredLogger
 }
 
 type ImageMeta struct {
@@ -53,28 +50,6 @@ func (bsky *BSky) worker(ctx context.Context, workerID int) {
 
 	log.Infof("starting worker %d\n", workerID)
 
-	// Run a routine that refreshes the auth token every 10 minutes
-	authTicker := time.NewTicker(10 * time.Minute)
-	quit := make(chan struct{})
-	go func() {
-		log.Info("starting auth refresh routine...")
-		for {
-			select {
-			case <-authTicker.C:
-				log.Info("refreshing auth token...")
-				err := bsky.RefreshAuthToken(ctx, workerID)
-				if err != nil {
-					log.Error("error refreshing auth token: %s\n", err)
-				} else {
-					log.Info("successfully refreshed auth token")
-				}
-			case <-quit:
-				authTicker.Stop()
-				return
-			}
-		}
-	}()
-
 	// Pull from the work queue and process records as they come in
 	for {
 		select {
@@ -88,6 +63,8 @@ func (bsky *BSky) worker(ctx context.Context, workerID int) {
 				record.ctx,
 				record.seq,
 				record.pst,
+				record.langs,
+				record.tags,
 				record.opPath,
 				record.repoName,
 				record.eventTime,
@@ -96,6 +73,7 @@ func (bsky *BSky) worker(ctx context.Context, workerID int) {
 			if err != nil {
 				log.Errorf("failed to process record: %v\n", err)
 			}
+			bsky.CursorTracker.Release(record.seq)
 		case <-ctx.Done():
 			log.Infof("worker %d terminating: context was cancelled\n", workerID)
 			return
@@ -108,6 +86,8 @@ func (bsky *BSky) ProcessRepoRecord(
 	ctx context.Context,
 	seq int64,
 	pst appbsky.FeedPost,
+	langs []string,
+	tags []string,
 	opPath string,
 	authorDID string,
 	eventTime string,
@@ -211,31 +191,25 @@ func (bsky *BSky) ProcessRepoRecord(
 	images := []ImageMeta{}
 
 	if pst.Embed != nil && pst.Embed.EmbedImages != nil && pst.Embed.EmbedImages.Images != nil {
-		// Fetch the post with metadata from the BSky API (this includes signed URLs for the images)
-		postPath := fmt.Sprintf("at://%s/%s", authorDID, opPath)
-		postMeta, err := bsky.ResolvePost(ctx, postPath, workerID)
-		if err != nil {
-			log.Errorf("error fetching post with metadata: %+v\n", err)
-		} else if postMeta == nil {
-			log.Error("post with metadata not found")
-		} else if postMeta.Embed != nil &&
-			postMeta.Embed.EmbedImages_View != nil &&
-			postMeta.Embed.EmbedImages_View.Images != nil &&
-			len(postMeta.Embed.EmbedImages_View.Images) == len(pst.Embed.EmbedImages.Images) {
-			// Iterate through the images and add them to the list with enriched metadata
-			for idx, image := range pst.Embed.EmbedImages.Images {
-				imageMeta := postMeta.Embed.EmbedImages_View.Images[idx]
-				if image.Image != nil && imageMeta != nil {
-					imageCID := image.Image.Ref.String()
-					images = append(images, ImageMeta{
-						CID:          imageCID,
-						MimeType:     image.Image.MimeType,
-						AltText:      image.Alt,
-						FullsizeURL:  imageMeta.Fullsize,
-						ThumbnailURL: imageMeta.Thumb,
-					})
-				}
+		for _, image := range pst.Embed.EmbedImages.Images {
+			if image == nil || image.Image == nil {
+				continue
+			}
+
+			imageCID := image.Image.Ref.String()
+			fullsizeURL, thumbnailURL, err := bsky.ImageURLs(ctx, authorDID, imageCID)
+			if err != nil {
+				log.Errorf("error building image URLs: %+v\n", err)
+				continue
 			}
+
+			images = append(images, ImageMeta{
+				CID:          imageCID,
+				MimeType:     image.Image.MimeType,
+				AltText:      image.Alt,
+				FullsizeURL:  fullsizeURL,
+				ThumbnailURL: thumbnailURL,
+			})
 		}
 	}
 
@@ -269,16 +243,85 @@ func (bsky *BSky) ProcessRepoRecord(
 			post.ParentRelationship = &parentRelationsip
 		}
 
-		err = bsky.PostRegistry.AddAuthor(ctx, &author)
+		err = bsky.BatchWriter.AddAuthor(ctx, &author)
 		if err != nil {
 			log.Errorf("error writing author to registry: %+v\n", err)
 		}
 
-		err = bsky.PostRegistry.AddPost(ctx, &post)
+		err = bsky.BatchWriter.AddPost(ctx, &post)
 		if err != nil {
 			log.Errorf("error writing post to registry: %+v\n", err)
 		}
 
+		// Write mentions and links from the facets to the registry
+		span.AddEvent("AddFacetsToRegistry")
+		for _, facet := range pst.Facets {
+			if facet == nil {
+				continue
+			}
+			for _, feature := range facet.Features {
+				if feature == nil {
+					continue
+				}
+				if feature.RichtextFacet_Mention != nil {
+					err = bsky.BatchWriter.AddPostMention(ctx, &search.PostMention{
+						PostID:       postID,
+						AuthorDID:    authorDID,
+						MentionedDID: feature.RichtextFacet_Mention.Did,
+						CreatedAt:    t,
+					})
+					if err != nil {
+						log.Errorf("error writing mention to registry: %+v\n", err)
+					}
+				} else if feature.RichtextFacet_Link != nil {
+					domain, ok := search.DomainFromURL(feature.RichtextFacet_Link.Uri)
+					if !ok {
+						continue
+					}
+					err = bsky.BatchWriter.AddPostLink(ctx, &search.PostLink{
+						PostID:    postID,
+						AuthorDID: authorDID,
+						URL:       feature.RichtextFacet_Link.Uri,
+						Domain:    domain,
+						CreatedAt: t,
+					})
+					if err != nil {
+						log.Errorf("error writing link to registry: %+v\n", err)
+					}
+				}
+			}
+		}
+
+		// Write the tags from the record's tag facets to the registry
+		if len(tags) > 0 {
+			span.AddEvent("AddTagsToRegistry")
+			for _, tag := range tags {
+				err = bsky.BatchWriter.AddPostTag(ctx, &search.PostTag{
+					PostID:    postID,
+					AuthorDID: authorDID,
+					Tag:       tag,
+					CreatedAt: t,
+				})
+				if err != nil {
+					log.Errorf("error writing tag to registry: %+v\n", err)
+				}
+			}
+		}
+
+		// Write the languages declared on the record to the registry
+		if declaredLangs := language.NormalizeAll(langs); len(declaredLangs) > 0 {
+			span.AddEvent("AddLanguagesToRegistry")
+			err = bsky.BatchWriter.AddPostLanguage(ctx, &search.PostLanguage{
+				PostID:        postID,
+				AuthorDID:     authorDID,
+				DeclaredLangs: declaredLangs,
+				CreatedAt:     t,
+			})
+			if err != nil {
+				log.Errorf("error writing post languages to registry: %+v\n", err)
+			}
+		}
+
 		// If there are images, write them to the registry
 		if len(images) > 0 {
 			for _, image := range images {
@@ -294,7 +337,7 @@ func (bsky *BSky) ProcessRepoRecord(
 					CreatedAt:    t,
 				}
 				span.AddEvent("AddImageToRegistry")
-				err = bsky.PostRegistry.AddImage(ctx, &registryImage)
+				err = bsky.BatchWriter.AddImage(ctx, &registryImage)
 				if err != nil {
 					log.Errorf("error writing image to registry: %+v\n", err)
 				}
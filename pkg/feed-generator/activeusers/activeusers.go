@@ -0,0 +1,204 @@
+package activeusers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GlobalFeed is the pseudo-feed name used to count users across all feeds
+const GlobalFeed = "_all"
+
+// dailyKeyTTL keeps enough daily HyperLogLogs around to compute a rolling 30 day window
+const dailyKeyTTL = 32 * 24 * time.Hour
+
+const dayFormat = "2006-01-02"
+
+// ActiveUsers tracks unique feed users in Redis HyperLogLogs
+// One HLL is kept per feed per day, plus an all-time HLL per feed
+// Weekly and monthly counts are computed by merging the daily HLLs at read time
+type ActiveUsers struct {
+	Client *redis.Client
+	Prefix string
+
+	FeedsKey string
+}
+
+// Counts holds the number of unique users seen over various windows
+type Counts struct {
+	Daily   int64 `json:"daily"`
+	Weekly  int64 `json:"weekly"`
+	Monthly int64 `json:"monthly"`
+	AllTime int64 `json:"all_time"`
+}
+
+func NewActiveUsers(ctx context.Context, client *redis.Client, prefix string) (*ActiveUsers, error) {
+	// Check if the client is connected
+	_, err := client.Ping(ctx).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to Redis: %w", err)
+	}
+
+	return &ActiveUsers{
+		Client:   client,
+		Prefix:   prefix,
+		FeedsKey: prefix + ":feeds",
+	}, nil
+}
+
+func (au *ActiveUsers) dailyKey(feedName string, day time.Time) string {
+	return fmt.Sprintf("%s:hll:%s:%s", au.Prefix, feedName, day.UTC().Format(dayFormat))
+}
+
+func (au *ActiveUsers) allTimeKey(feedName string) string {
+	return fmt.Sprintf("%s:hll:%s:all_time", au.Prefix, feedName)
+}
+
+// AddUser records a user as active on the given feed and globally
+// It returns whether the user appears to be new to the feed and new globally
+// (based on whether the all-time HyperLogLogs were modified)
+func (au *ActiveUsers) AddUser(ctx context.Context, feedName string, userDID string) (bool, bool, error) {
+	tracer := otel.Tracer("active-users")
+	ctx, span := tracer.Start(ctx, "ActiveUsers:AddUser")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("feed.name", feedName))
+
+	now := time.Now()
+	feedDailyKey := au.dailyKey(feedName, now)
+	globalDailyKey := au.dailyKey(GlobalFeed, now)
+
+	pipeline := au.Client.Pipeline()
+	pipeline.PFAdd(ctx, feedDailyKey, userDID)
+	pipeline.PFAdd(ctx, globalDailyKey, userDID)
+	pipeline.Expire(ctx, feedDailyKey, dailyKeyTTL)
+	pipeline.Expire(ctx, globalDailyKey, dailyKeyTTL)
+	newToFeed := pipeline.PFAdd(ctx, au.allTimeKey(feedName), userDID)
+	newGlobally := pipeline.PFAdd(ctx, au.allTimeKey(GlobalFeed), userDID)
+	pipeline.SAdd(ctx, au.FeedsKey, feedName)
+
+	_, err := pipeline.Exec(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return false, false, fmt.Errorf("error adding user to active user HLLs: %w", err)
+	}
+
+	return newToFeed.Val() == 1, newGlobally.Val() == 1, nil
+}
+
+// GetCounts returns the daily, rolling weekly, rolling monthly, and all-time unique users for a feed
+// Use GlobalFeed as the feed name to get counts across all feeds
+func (au *ActiveUsers) GetCounts(ctx context.Context, feedName string) (*Counts, error) {
+	tracer := otel.Tracer("active-users")
+	ctx, span := tracer.Start(ctx, "ActiveUsers:GetCounts")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("feed.name", feedName))
+
+	now := time.Now()
+	dailyKeys := make([]string, 30)
+	for i := range dailyKeys {
+		dailyKeys[i] = au.dailyKey(feedName, now.AddDate(0, 0, -i))
+	}
+
+	pipeline := au.Client.Pipeline()
+	daily := pipeline.PFCount(ctx, dailyKeys[0])
+	weekly := pipeline.PFCount(ctx, dailyKeys[:7]...)
+	monthly := pipeline.PFCount(ctx, dailyKeys...)
+	allTime := pipeline.PFCount(ctx, au.allTimeKey(feedName))
+
+	_, err := pipeline.Exec(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("error counting active users: %w", err)
+	}
+
+	return &Counts{
+		Daily:   daily.Val(),
+		Weekly:  weekly.Val(),
+		Monthly: monthly.Val(),
+		AllTime: allTime.Val(),
+	}, nil
+}
+
+// GetReturningUsers returns the number of unique users seen on a feed on the given day
+// and how many of those had also been seen in the 30 days before it
+// The overlap is estimated from the HyperLogLogs as |day| + |prior| - |day ∪ prior|
+func (au *ActiveUsers) GetReturningUsers(ctx context.Context, feedName string, day time.Time) (int64, int64, error) {
+	tracer := otel.Tracer("active-users")
+	ctx, span := tracer.Start(ctx, "ActiveUsers:GetReturningUsers")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("feed.name", feedName))
+
+	dayKey := au.dailyKey(feedName, day)
+	priorKeys := make([]string, 30)
+	for i := range priorKeys {
+		priorKeys[i] = au.dailyKey(feedName, day.AddDate(0, 0, -(i+1)))
+	}
+
+	pipeline := au.Client.Pipeline()
+	daily := pipeline.PFCount(ctx, dayKey)
+	prior := pipeline.PFCount(ctx, priorKeys...)
+	union := pipeline.PFCount(ctx, append([]string{dayKey}, priorKeys...)...)
+
+	_, err := pipeline.Exec(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return 0, 0, fmt.Errorf("error counting returning users: %w", err)
+	}
+
+	returning := daily.Val() + prior.Val() - union.Val()
+	if returning < 0 {
+		returning = 0
+	}
+	if returning > daily.Val() {
+		returning = daily.Val()
+	}
+
+	return daily.Val(), returning, nil
+}
+
+// GetFeeds returns the names of all feeds that have had active users recorded
+func (au *ActiveUsers) GetFeeds(ctx context.Context) ([]string, error) {
+	tracer := otel.Tracer("active-users")
+	ctx, span := tracer.Start(ctx, "ActiveUsers:GetFeeds")
+	defer span.End()
+
+	feeds, err := au.Client.SMembers(ctx, au.FeedsKey).Result()
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("error getting feeds from Redis: %w", err)
+	}
+
+	return feeds, nil
+}
+
+// GetAllCounts returns counts for every known feed as well as the global counts
+func (au *ActiveUsers) GetAllCounts(ctx context.Context) (map[string]*Counts, error) {
+	tracer := otel.Tracer("active-users")
+	ctx, span := tracer.Start(ctx, "ActiveUsers:GetAllCounts")
+	defer span.End()
+
+	feeds, err := au.GetFeeds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	feeds = append(feeds, GlobalFeed)
+
+	allCounts := make(map[string]*Counts, len(feeds))
+	for _, feedName := range feeds {
+		counts, err := au.GetCounts(ctx, feedName)
+		if err != nil {
+			return nil, err
+		}
+		allCounts[feedName] = counts
+	}
+
+	return allCounts, nil
+}
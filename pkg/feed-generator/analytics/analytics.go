@@ -0,0 +1,214 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	feedgenerator "github.com/ericvolp12/bsky-experiments/pkg/feed-generator"
+	"github.com/ericvolp12/bsky-experiments/pkg/feed-generator/activeusers"
+	"github.com/ericvolp12/bsky-experiments/pkg/search"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// dailyKeyTTL keeps live counters around long enough to roll up yesterday after midnight
+const dailyKeyTTL = 3 * 24 * time.Hour
+
+// sessionTTL is how long a user can go between pages before we consider it a new session
+const sessionTTL = 30 * time.Minute
+
+const dayFormat = "2006-01-02"
+
+// Analytics records per-feed usage in Redis and rolls it up daily into the PostRegistry
+// Live counters are kept in a Redis hash per feed per day, served post IDs in a set per feed per day,
+// and the current page depth of each user's session in a short-lived counter
+type Analytics struct {
+	Client       *redis.Client
+	Prefix       string
+	ActiveUsers  *activeusers.ActiveUsers
+	PostRegistry *search.PostRegistry
+}
+
+func NewAnalytics(
+	ctx context.Context,
+	client *redis.Client,
+	prefix string,
+	activeUsers *activeusers.ActiveUsers,
+	postRegistry *search.PostRegistry,
+) (*Analytics, error) {
+	// Check if the client is connected
+	_, err := client.Ping(ctx).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to Redis: %w", err)
+	}
+
+	return &Analytics{
+		Client:       client,
+		Prefix:       prefix,
+		ActiveUsers:  activeUsers,
+		PostRegistry: postRegistry,
+	}, nil
+}
+
+func (a *Analytics) statsKey(feedName string, day time.Time) string {
+	return fmt.Sprintf("%s:stats:%s:%s", a.Prefix, feedName, day.UTC().Format(dayFormat))
+}
+
+func (a *Analytics) servedKey(feedName string, day time.Time) string {
+	return fmt.Sprintf("%s:served:%s:%s", a.Prefix, feedName, day.UTC().Format(dayFormat))
+}
+
+func (a *Analytics) depthKey(feedName string, userDID string) string {
+	return fmt.Sprintf("%s:depth:%s:%s", a.Prefix, feedName, userDID)
+}
+
+// RecordPage records a page of a feed being served to a user
+// A page requested without a cursor starts a new session for the user
+// It returns the page depth the user has reached in their current session
+func (a *Analytics) RecordPage(ctx context.Context, feedName string, userDID string, cursor string, postURIs []string) (int64, error) {
+	tracer := otel.Tracer("feed-analytics")
+	ctx, span := tracer.Start(ctx, "Analytics:RecordPage")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("feed.name", feedName))
+	span.SetAttributes(attribute.Int("posts.length", len(postURIs)))
+
+	now := time.Now()
+	statsKey := a.statsKey(feedName, now)
+	servedKey := a.servedKey(feedName, now)
+	depthKey := a.depthKey(feedName, userDID)
+
+	pipeline := a.Client.Pipeline()
+
+	var depth *redis.IntCmd
+	if cursor == "" {
+		pipeline.Set(ctx, depthKey, 1, sessionTTL)
+		pipeline.HIncrBy(ctx, statsKey, "sessions", 1)
+	} else {
+		depth = pipeline.Incr(ctx, depthKey)
+		pipeline.Expire(ctx, depthKey, sessionTTL)
+	}
+
+	pipeline.HIncrBy(ctx, statsKey, "requests", 1)
+	pipeline.HIncrBy(ctx, statsKey, "posts_served", int64(len(postURIs)))
+	pipeline.Expire(ctx, statsKey, dailyKeyTTL)
+
+	if len(postURIs) > 0 {
+		postIDs := make([]interface{}, len(postURIs))
+		for i, uri := range postURIs {
+			postIDs[i] = feedgenerator.PostIDFromURI(uri)
+		}
+		pipeline.SAdd(ctx, servedKey, postIDs...)
+		pipeline.Expire(ctx, servedKey, dailyKeyTTL)
+	}
+
+	_, err := pipeline.Exec(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return 0, fmt.Errorf("error recording feed page: %w", err)
+	}
+
+	pageDepth := int64(1)
+	if depth != nil {
+		pageDepth = depth.Val()
+	}
+
+	feedPageDepth.WithLabelValues(feedName).Observe(float64(pageDepth))
+	feedPostsServedCounter.WithLabelValues(feedName).Add(float64(len(postURIs)))
+
+	return pageDepth, nil
+}
+
+// GetDayStats assembles the stats for a feed on a given day from the live counters in Redis
+// and the like counts of the posts served that day
+func (a *Analytics) GetDayStats(ctx context.Context, feedName string, day time.Time) (*search.FeedStats, error) {
+	tracer := otel.Tracer("feed-analytics")
+	ctx, span := tracer.Start(ctx, "Analytics:GetDayStats")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("feed.name", feedName))
+
+	date, err := time.Parse(dayFormat, day.UTC().Format(dayFormat))
+	if err != nil {
+		return nil, fmt.Errorf("error truncating day: %w", err)
+	}
+
+	counters, err := a.Client.HGetAll(ctx, a.statsKey(feedName, day)).Result()
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("error getting feed counters: %w", err)
+	}
+
+	stats := &search.FeedStats{
+		FeedName:  feedName,
+		Date:      date,
+		UpdatedAt: time.Now(),
+	}
+
+	stats.Requests, _ = strconv.ParseInt(counters["requests"], 10, 64)
+	stats.Sessions, _ = strconv.ParseInt(counters["sessions"], 10, 64)
+	stats.PostsServed, _ = strconv.ParseInt(counters["posts_served"], 10, 64)
+
+	servedPostIDs, err := a.Client.SMembers(ctx, a.servedKey(feedName, day)).Result()
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("error getting served posts: %w", err)
+	}
+
+	stats.UniquePostsServed = int64(len(servedPostIDs))
+
+	stats.UniqueUsers, stats.ReturningUsers, err = a.ActiveUsers.GetReturningUsers(ctx, feedName, day)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(servedPostIDs) > 0 {
+		likeTotals, err := a.PostRegistry.GetLikeTotalsForPosts(ctx, servedPostIDs)
+		if err != nil {
+			span.RecordError(err)
+			return nil, fmt.Errorf("error getting likes for served posts: %w", err)
+		}
+		stats.LikedPosts = likeTotals.LikedPosts
+		stats.TotalLikes = likeTotals.TotalLikes
+	}
+
+	stats.ComputeRates()
+
+	return stats, nil
+}
+
+// Rollup writes the stats for every known feed on the given day to the PostRegistry
+// Rollups are idempotent, so the current day can be rolled up repeatedly as it fills in
+func (a *Analytics) Rollup(ctx context.Context, day time.Time) error {
+	tracer := otel.Tracer("feed-analytics")
+	ctx, span := tracer.Start(ctx, "Analytics:Rollup")
+	defer span.End()
+
+	feeds, err := a.ActiveUsers.GetFeeds(ctx)
+	if err != nil {
+		return err
+	}
+
+	span.SetAttributes(attribute.Int("feeds.length", len(feeds)))
+
+	for _, feedName := range feeds {
+		stats, err := a.GetDayStats(ctx, feedName, day)
+		if err != nil {
+			return fmt.Errorf("error getting stats for feed %s: %w", feedName, err)
+		}
+
+		err = a.PostRegistry.UpsertFeedStats(ctx, stats)
+		if err != nil {
+			span.RecordError(err)
+			return fmt.Errorf("error saving stats for feed %s: %w", feedName, err)
+		}
+
+		feedEngagementRate.WithLabelValues(feedName).Set(stats.EngagementRate)
+		feedReturningUserRate.WithLabelValues(feedName).Set(stats.ReturningUserRate)
+	}
+
+	return nil
+}
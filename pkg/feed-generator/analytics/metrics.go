@@ -0,0 +1,27 @@
+package analytics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var feedPageDepth = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "feed_page_depth",
+	Help:    "The page depth reached by users within a feed session",
+	Buckets: []float64{1, 2, 3, 4, 5, 7, 10, 15, 20, 30, 50},
+}, []string{"feed_name"})
+
+var feedPostsServedCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "feed_posts_served_count",
+	Help: "The total number of posts served in feed pages",
+}, []string{"feed_name"})
+
+var feedEngagementRate = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "feed_engagement_rate",
+	Help: "The fraction of posts served by a feed today that have been liked",
+}, []string{"feed_name"})
+
+var feedReturningUserRate = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "feed_returning_user_rate",
+	Help: "The fraction of a feed's users today that were also seen in the prior 30 days",
+}, []string{"feed_name"})
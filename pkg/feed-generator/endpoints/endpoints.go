@@ -1,6 +1,7 @@
 package endpoints
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -10,10 +11,14 @@ import (
 	"sync"
 	"time"
 
-	"github.com/bits-and-blooms/bloom/v3"
 	appbsky "github.com/bluesky-social/indigo/api/bsky"
 	"github.com/ericvolp12/bsky-experiments/pkg/auth"
 	feedgenerator "github.com/ericvolp12/bsky-experiments/pkg/feed-generator"
+	"github.com/ericvolp12/bsky-experiments/pkg/feed-generator/activeusers"
+	"github.com/ericvolp12/bsky-experiments/pkg/feed-generator/analytics"
+	"github.com/ericvolp12/bsky-experiments/pkg/feed-generator/experiments"
+	"github.com/ericvolp12/bsky-experiments/pkg/feed-generator/modlists"
+	"github.com/ericvolp12/bsky-experiments/pkg/language"
 	"github.com/ericvolp12/bsky-experiments/pkg/search"
 	"github.com/ericvolp12/bsky-experiments/pkg/search/clusters"
 
@@ -29,10 +34,15 @@ type DescriptionCacheItem struct {
 }
 
 type Endpoints struct {
-	FeedGenerator   *feedgenerator.FeedGenerator
-	GraphJSONUrl    string
-	FeedUsers       map[string][]string
-	UniqueSeenUsers *bloom.BloomFilter
+	FeedGenerator *feedgenerator.FeedGenerator
+	GraphJSONUrl  string
+	ActiveUsers   *activeusers.ActiveUsers
+	Analytics     *analytics.Analytics
+	Experiments   *experiments.Experiments
+	ModLists      *modlists.ModLists
+
+	// LanguageFilteringEnabled drops posts that aren't in one of the requester's Accept-Language languages
+	LanguageFilteringEnabled bool
 
 	PostRegistry *search.PostRegistry
 
@@ -46,14 +56,22 @@ type DidResponse struct {
 	Service []did.Service `json:"service"`
 }
 
-func NewEndpoints(feedGenerator *feedgenerator.FeedGenerator, graphJSONUrl string, postRegistry *search.PostRegistry) (*Endpoints, error) {
-	uniqueSeenUsers := bloom.NewWithEstimates(1000000, 0.01)
-
+func NewEndpoints(
+	feedGenerator *feedgenerator.FeedGenerator,
+	graphJSONUrl string,
+	postRegistry *search.PostRegistry,
+	activeUsers *activeusers.ActiveUsers,
+	feedAnalytics *analytics.Analytics,
+	feedExperiments *experiments.Experiments,
+	modLists *modlists.ModLists,
+) (*Endpoints, error) {
 	return &Endpoints{
 		FeedGenerator:       feedGenerator,
 		GraphJSONUrl:        graphJSONUrl,
-		UniqueSeenUsers:     uniqueSeenUsers,
-		FeedUsers:           map[string][]string{},
+		ActiveUsers:         activeUsers,
+		Analytics:           feedAnalytics,
+		Experiments:         feedExperiments,
+		ModLists:            modLists,
 		PostRegistry:        postRegistry,
 		DescriptionCacheTTL: 30 * time.Minute,
 	}, nil
@@ -163,7 +181,7 @@ func (ep *Endpoints) GetFeedSkeleton(c *gin.Context) {
 	}
 
 	// Count the user
-	ep.ProcessUser(feedName, userDID)
+	ep.ProcessUser(ctx, feedName, userDID)
 
 	span.SetAttributes(attribute.String("feed.name", feedName))
 	c.Set("feedName", feedName)
@@ -204,6 +222,17 @@ func (ep *Endpoints) GetFeedSkeleton(c *gin.Context) {
 		return
 	}
 
+	// Bucket the user into an experiment variant if one is running on this feed
+	// The variant travels with the context so the feed can pick its ranker and config
+	ctx, variant := ep.Experiments.AssignVariant(ctx, feedName, userDID)
+	if variant != nil {
+		span.SetAttributes(attribute.String("feed.experiment", variant.Experiment))
+		span.SetAttributes(attribute.String("feed.experiment.variant", variant.Name))
+		c.Set("experiment", variant.Experiment)
+		c.Set("experimentVariant", variant.Name)
+		experimentRequestCounter.WithLabelValues(feedName, variant.Experiment, variant.Name).Inc()
+	}
+
 	// Get the feed items
 	feedItems, newCursor, err := feed.GetPage(ctx, feedName, userDID, limit, cursor)
 	if err != nil {
@@ -212,8 +241,54 @@ func (ep *Endpoints) GetFeedSkeleton(c *gin.Context) {
 		return
 	}
 
+	// Drop posts from deleted accounts, failing to do so shouldn't fail the feed request
+	feedItems, err = ep.FilterTombstonedAuthors(ctx, feedItems)
+	if err != nil {
+		span.RecordError(err)
+		log.Printf("failed to filter tombstoned authors for feed %s: %+v", feedName, err)
+	}
+
+	// Drop posts from authors on the requester's subscribed mod lists
+	feedItems, err = ep.ModLists.FilterFeedItems(ctx, userDID, feedItems)
+	if err != nil {
+		span.RecordError(err)
+		log.Printf("failed to filter feed %s with mod lists: %+v", feedName, err)
+	}
+
+	// Drop posts that aren't in the requester's languages, language feeds are served as-is
+	if ep.LanguageFilteringEnabled && !strings.HasPrefix(feedName, search.LanguageLabelPrefix) {
+		langs := language.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+		feedItems, err = ep.FilterFeedItemsByLanguage(ctx, langs, feedItems)
+		if err != nil {
+			span.RecordError(err)
+			log.Printf("failed to filter feed %s by language: %+v", feedName, err)
+		}
+	}
+
 	span.SetAttributes(attribute.Int("feed.items.length", len(feedItems)))
 
+	postURIs := make([]string, len(feedItems))
+	for i, feedItem := range feedItems {
+		postURIs[i] = feedItem.Post
+	}
+
+	// Record the page for feed analytics, failing to do so shouldn't fail the feed request
+	pageDepth, err := ep.Analytics.RecordPage(ctx, feedName, userDID, cursor, postURIs)
+	if err != nil {
+		span.RecordError(err)
+		log.Printf("failed to record feed page for feed %s: %+v", feedName, err)
+	}
+	span.SetAttributes(attribute.Int64("feed.page_depth", pageDepth))
+
+	// Track variant pages under their own segment so engagement can be compared across variants
+	if variant != nil {
+		err = ep.ProcessVariantPage(ctx, variant, userDID, cursor, postURIs)
+		if err != nil {
+			span.RecordError(err)
+			log.Printf("failed to record experiment page for feed %s: %+v", feedName, err)
+		}
+	}
+
 	feedRequestLatency.WithLabelValues(feedName).Observe(time.Since(start).Seconds())
 
 	c.JSON(http.StatusOK, appbsky.FeedGetFeedSkeleton_Output{
@@ -222,31 +297,146 @@ func (ep *Endpoints) GetFeedSkeleton(c *gin.Context) {
 	})
 }
 
-func (ep *Endpoints) ProcessUser(feedName string, userDID string) {
-	// Check if the user has ever been seen before
-	if !ep.UniqueSeenUsers.TestString(userDID) {
-		ep.UniqueSeenUsers.AddString(userDID)
-		uniqueFeedUserCounter.Inc()
+// FilterTombstonedAuthors removes posts by authors whose accounts have been deleted
+// On error the feed items are returned unfiltered
+func (ep *Endpoints) FilterTombstonedAuthors(ctx context.Context, feedItems []*appbsky.FeedDefs_SkeletonFeedPost) ([]*appbsky.FeedDefs_SkeletonFeedPost, error) {
+	tracer := otel.Tracer("feed-generator")
+	ctx, span := tracer.Start(ctx, "FeedGenerator:Endpoints:FilterTombstonedAuthors")
+	defer span.End()
+
+	if len(feedItems) == 0 {
+		return feedItems, nil
+	}
+
+	authorDIDs := make([]string, len(feedItems))
+	for i, feedItem := range feedItems {
+		authorDIDs[i] = feedgenerator.AuthorDIDFromURI(feedItem.Post)
+	}
+
+	tombstoned, err := ep.PostRegistry.GetTombstonedAuthors(ctx, authorDIDs)
+	if err != nil {
+		return feedItems, err
+	}
+
+	if len(tombstoned) == 0 {
+		return feedItems, nil
 	}
 
-	// Check if the feed user list exists
-	if ep.FeedUsers[feedName] == nil {
-		// If not, create the feed user list
-		ep.FeedUsers[feedName] = []string{
-			userDID,
+	filtered := make([]*appbsky.FeedDefs_SkeletonFeedPost, 0, len(feedItems))
+	for i, feedItem := range feedItems {
+		if _, ok := tombstoned[authorDIDs[i]]; ok {
+			continue
 		}
-	} else {
-		// Check if the user is already in the list
-		for _, existingUserDID := range ep.FeedUsers[feedName] {
-			if existingUserDID == userDID {
-				return
-			}
+		filtered = append(filtered, feedItem)
+	}
+
+	span.SetAttributes(attribute.Int("feed.items.tombstoned", len(feedItems)-len(filtered)))
+
+	return filtered, nil
+}
+
+// FilterFeedItemsByLanguage removes posts that aren't written in one of the given languages
+// Posts in an unknown language are kept, on error the feed items are returned unfiltered
+func (ep *Endpoints) FilterFeedItemsByLanguage(ctx context.Context, langs []string, feedItems []*appbsky.FeedDefs_SkeletonFeedPost) ([]*appbsky.FeedDefs_SkeletonFeedPost, error) {
+	tracer := otel.Tracer("feed-generator")
+	ctx, span := tracer.Start(ctx, "FeedGenerator:Endpoints:FilterFeedItemsByLanguage")
+	defer span.End()
+
+	span.SetAttributes(attribute.StringSlice("feed.langs", langs))
+
+	if len(langs) == 0 || len(feedItems) == 0 {
+		return feedItems, nil
+	}
+
+	postIDs := make([]string, len(feedItems))
+	for i, feedItem := range feedItems {
+		postIDs[i] = feedgenerator.PostIDFromURI(feedItem.Post)
+	}
+
+	postLangs, err := ep.PostRegistry.GetPostLanguages(ctx, postIDs)
+	if err != nil {
+		return feedItems, err
+	}
+
+	filtered := make([]*appbsky.FeedDefs_SkeletonFeedPost, 0, len(feedItems))
+	for i, feedItem := range feedItems {
+		if !postLangs[postIDs[i]].Matches(langs) {
+			continue
 		}
+		filtered = append(filtered, feedItem)
+	}
+
+	span.SetAttributes(attribute.Int("feed.items.language_filtered", len(feedItems)-len(filtered)))
+
+	return filtered, nil
+}
+
+func (ep *Endpoints) ProcessUser(ctx context.Context, feedName string, userDID string) {
+	tracer := otel.Tracer("feed-generator")
+	ctx, span := tracer.Start(ctx, "FeedGenerator:Endpoints:ProcessUser")
+	defer span.End()
+
+	newToFeed, newGlobally, err := ep.ActiveUsers.AddUser(ctx, feedName, userDID)
+	if err != nil {
+		// Failing to count a user shouldn't fail the feed request
+		span.RecordError(err)
+		log.Printf("failed to record active user for feed %s: %+v", feedName, err)
+		return
+	}
+
+	if newGlobally {
+		uniqueFeedUserCounter.Inc()
+	}
+
+	if newToFeed {
+		feedUserCounter.WithLabelValues(feedName).Inc()
+	}
+}
+
+// ProcessVariantPage records a page served under an experiment variant
+// Variants are tracked as their own segment in active users and analytics,
+// so they get daily rollups alongside the feeds they run on
+func (ep *Endpoints) ProcessVariantPage(ctx context.Context, variant *experiments.Variant, userDID string, cursor string, postURIs []string) error {
+	tracer := otel.Tracer("feed-generator")
+	ctx, span := tracer.Start(ctx, "FeedGenerator:Endpoints:ProcessVariantPage")
+	defer span.End()
+
+	segment := variant.Segment()
+	span.SetAttributes(attribute.String("feed.segment", segment))
+
+	_, _, err := ep.ActiveUsers.AddUser(ctx, segment, userDID)
+	if err != nil {
+		return err
+	}
 
-		ep.FeedUsers[feedName] = append(ep.FeedUsers[feedName], userDID)
+	_, err = ep.Analytics.RecordPage(ctx, segment, userDID, cursor, postURIs)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RefreshActiveUserGauges updates the active user Prometheus gauges from the HyperLogLogs in Redis
+func (ep *Endpoints) RefreshActiveUserGauges(ctx context.Context) error {
+	tracer := otel.Tracer("feed-generator")
+	ctx, span := tracer.Start(ctx, "FeedGenerator:Endpoints:RefreshActiveUserGauges")
+	defer span.End()
+
+	allCounts, err := ep.ActiveUsers.GetAllCounts(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to get active user counts: %w", err)
 	}
 
-	feedUserCounter.WithLabelValues(feedName).Inc()
+	for feedName, counts := range allCounts {
+		activeUsersGauge.WithLabelValues(feedName, "daily").Set(float64(counts.Daily))
+		activeUsersGauge.WithLabelValues(feedName, "weekly").Set(float64(counts.Weekly))
+		activeUsersGauge.WithLabelValues(feedName, "monthly").Set(float64(counts.Monthly))
+		activeUsersGauge.WithLabelValues(feedName, "all_time").Set(float64(counts.AllTime))
+	}
+
+	return nil
 }
 
 //
@@ -525,3 +715,225 @@ func (ep *Endpoints) GetFeedMembers(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"authors": authors})
 }
+
+func (ep *Endpoints) GetActiveUsers(c *gin.Context) {
+	tracer := otel.Tracer("feed-generator")
+	ctx, span := tracer.Start(c.Request.Context(), "FeedGenerator:Endpoints:GetActiveUsers")
+	defer span.End()
+
+	feedName := c.Query("feedName")
+	if feedName != "" {
+		span.SetAttributes(attribute.String("feed.name", feedName))
+		counts, err := ep.ActiveUsers.GetCounts(ctx, feedName)
+		if err != nil {
+			span.RecordError(err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error getting active users: %s", err.Error())})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"active_users": map[string]*activeusers.Counts{feedName: counts}})
+		return
+	}
+
+	allCounts, err := ep.ActiveUsers.GetAllCounts(ctx)
+	if err != nil {
+		span.RecordError(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error getting active users: %s", err.Error())})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"active_users": allCounts})
+}
+
+func (ep *Endpoints) GetFeedStats(c *gin.Context) {
+	tracer := otel.Tracer("feed-generator")
+	ctx, span := tracer.Start(c.Request.Context(), "FeedGenerator:Endpoints:GetFeedStats")
+	defer span.End()
+
+	feedName := c.Param("name")
+	span.SetAttributes(attribute.String("feed.name", feedName))
+
+	days, err := parseStatsDays(c.Query("days"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	today, err := ep.Analytics.GetDayStats(ctx, feedName, time.Now())
+	if err != nil {
+		span.RecordError(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error getting live feed stats: %s", err.Error())})
+		return
+	}
+
+	history, err := ep.PostRegistry.GetFeedStats(ctx, feedName, days)
+	if err != nil {
+		span.RecordError(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error getting feed stats history: %s", err.Error())})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"feed_name": feedName,
+		"today":     today,
+		"history":   history,
+	})
+}
+
+// parseStatsDays parses the number of days of feed stats history to return, default to 7, maximum of 90
+func parseStatsDays(rawDays string) (int32, error) {
+	if rawDays == "" {
+		return 7, nil
+	}
+
+	days, err := strconv.ParseInt(rawDays, 10, 32)
+	if err != nil || days < 1 {
+		return 0, fmt.Errorf("days must be a positive integer")
+	}
+	if days > 90 {
+		days = 90
+	}
+
+	return int32(days), nil
+}
+
+type variantStats struct {
+	Variant *experiments.Variant `json:"variant"`
+	Today   *search.FeedStats    `json:"today"`
+	History []*search.FeedStats  `json:"history"`
+}
+
+type experimentStats struct {
+	Name     string          `json:"name"`
+	FeedName string          `json:"feed_name"`
+	Variants []*variantStats `json:"variants"`
+}
+
+func (ep *Endpoints) GetExperiments(c *gin.Context) {
+	tracer := otel.Tracer("feed-generator")
+	ctx, span := tracer.Start(c.Request.Context(), "FeedGenerator:Endpoints:GetExperiments")
+	defer span.End()
+
+	allExperimentStats := []*experimentStats{}
+	if ep.Experiments == nil {
+		c.JSON(http.StatusOK, gin.H{"experiments": allExperimentStats})
+		return
+	}
+
+	for _, experiment := range ep.Experiments.ExperimentsByFeed {
+		stats := &experimentStats{
+			Name:     experiment.Name,
+			FeedName: experiment.FeedName,
+		}
+
+		for _, variant := range experiment.Variants {
+			today, err := ep.Analytics.GetDayStats(ctx, variant.Segment(), time.Now())
+			if err != nil {
+				span.RecordError(err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error getting live variant stats: %s", err.Error())})
+				return
+			}
+
+			history, err := ep.PostRegistry.GetFeedStats(ctx, variant.Segment(), 7)
+			if err != nil {
+				span.RecordError(err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error getting variant stats history: %s", err.Error())})
+				return
+			}
+
+			stats.Variants = append(stats.Variants, &variantStats{
+				Variant: variant,
+				Today:   today,
+				History: history,
+			})
+		}
+
+		allExperimentStats = append(allExperimentStats, stats)
+	}
+
+	span.SetAttributes(attribute.Int("experiments.length", len(allExperimentStats)))
+
+	c.JSON(http.StatusOK, gin.H{"experiments": allExperimentStats})
+}
+
+func (ep *Endpoints) SubscribeToModList(c *gin.Context) {
+	tracer := otel.Tracer("feed-generator")
+	ctx, span := tracer.Start(c.Request.Context(), "FeedGenerator:Endpoints:SubscribeToModList")
+	defer span.End()
+
+	userDID := c.GetString("user_did")
+	if userDID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authorized: no user DID in context"})
+		return
+	}
+
+	listURI := c.Query("list")
+	if listURI == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "list is required"})
+		return
+	}
+
+	err := ep.ModLists.Subscribe(ctx, userDID, listURI)
+	if err != nil {
+		span.RecordError(err)
+		switch {
+		case errors.As(err, &search.NotFoundError{}):
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("list not found: %s", listURI)})
+		case errors.Is(err, modlists.ErrNotModList), errors.Is(err, modlists.ErrTooManySubscriptions):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error subscribing to mod list: %s", err.Error())})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+func (ep *Endpoints) UnsubscribeFromModList(c *gin.Context) {
+	tracer := otel.Tracer("feed-generator")
+	ctx, span := tracer.Start(c.Request.Context(), "FeedGenerator:Endpoints:UnsubscribeFromModList")
+	defer span.End()
+
+	userDID := c.GetString("user_did")
+	if userDID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authorized: no user DID in context"})
+		return
+	}
+
+	listURI := c.Query("list")
+	if listURI == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "list is required"})
+		return
+	}
+
+	err := ep.ModLists.Unsubscribe(ctx, userDID, listURI)
+	if err != nil {
+		span.RecordError(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error unsubscribing from mod list: %s", err.Error())})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+func (ep *Endpoints) GetModListSubscriptions(c *gin.Context) {
+	tracer := otel.Tracer("feed-generator")
+	ctx, span := tracer.Start(c.Request.Context(), "FeedGenerator:Endpoints:GetModListSubscriptions")
+	defer span.End()
+
+	userDID := c.GetString("user_did")
+	if userDID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "not authorized: no user DID in context"})
+		return
+	}
+
+	subscriptions, err := ep.ModLists.GetSubscriptions(ctx, userDID)
+	if err != nil {
+		span.RecordError(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("error getting mod list subscriptions: %s", err.Error())})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"lists": subscriptions})
+}
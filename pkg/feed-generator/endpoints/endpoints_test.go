@@ -0,0 +1,53 @@
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStatsDays(t *testing.T) {
+	testCases := []struct {
+		rawDays string
+		days    int32
+		wantErr bool
+	}{
+		{rawDays: "", days: 7},
+		{rawDays: "1", days: 1},
+		{rawDays: "30", days: 30},
+		{rawDays: "365", days: 90},
+		{rawDays: "0", wantErr: true},
+		{rawDays: "-3", wantErr: true},
+		{rawDays: "week", wantErr: true},
+		{rawDays: "99999999999", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.rawDays, func(t *testing.T) {
+			days, err := parseStatsDays(tc.rawDays)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.days, days)
+		})
+	}
+}
+
+func TestGetFeedStatsRejectsInvalidDays(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// The request is rejected before the analytics or the registry are touched
+	router := gin.New()
+	router.GET("/admin/feeds/:name/stats", (&Endpoints{}).GetFeedStats)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/feeds/cats/stats?days=week", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error": "days must be a positive integer"}`, w.Body.String())
+}
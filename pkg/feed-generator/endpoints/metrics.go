@@ -20,6 +20,16 @@ var uniqueFeedUserCounter = promauto.NewCounter(prometheus.CounterOpts{
 	Help: "The total number of unique feed users",
 })
 
+var activeUsersGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "feed_active_users",
+	Help: "The number of unique feed users over a window (daily, weekly, monthly, all_time)",
+}, []string{"feed_name", "window"})
+
+var experimentRequestCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "feed_experiment_request_count",
+	Help: "The total number of feed requests served under an experiment variant",
+}, []string{"feed_name", "experiment", "variant"})
+
 var feedRequestLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
 	Name:    "feed_request_latency",
 	Help:    "The latency of feed requests",
@@ -0,0 +1,182 @@
+// Package experiments buckets feed requesters into experiment variants so ranking changes can be compared.
+// Assignment is deterministic: a given user always lands in the same variant of a given experiment.
+package experiments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"strconv"
+)
+
+// Variant is one arm of an experiment
+// Config is handed to the feed serving the request, which uses it to pick a ranker or tune parameters
+type Variant struct {
+	Name   string            `json:"name"`
+	Weight uint64            `json:"weight"`
+	Config map[string]string `json:"config"`
+
+	Experiment string `json:"-"`
+	FeedName   string `json:"-"`
+}
+
+// Experiment splits the requesters of a single feed across a set of weighted variants
+type Experiment struct {
+	Name     string     `json:"name"`
+	FeedName string     `json:"feed_name"`
+	Variants []*Variant `json:"variants"`
+
+	totalWeight uint64
+}
+
+// Experiments holds the active experiments keyed by the feed they run on
+type Experiments struct {
+	ExperimentsByFeed map[string]*Experiment
+}
+
+type variantContextKey struct{}
+
+// NewExperiments validates the given experiments and indexes them by feed name
+// Only one experiment may run on a feed at a time
+func NewExperiments(experiments []*Experiment) (*Experiments, error) {
+	experimentsByFeed := map[string]*Experiment{}
+
+	for _, experiment := range experiments {
+		if experiment.Name == "" {
+			return nil, fmt.Errorf("experiment for feed %s has no name", experiment.FeedName)
+		}
+		if experiment.FeedName == "" {
+			return nil, fmt.Errorf("experiment %s has no feed name", experiment.Name)
+		}
+		if _, ok := experimentsByFeed[experiment.FeedName]; ok {
+			return nil, fmt.Errorf("feed %s has more than one experiment", experiment.FeedName)
+		}
+		if len(experiment.Variants) == 0 {
+			return nil, fmt.Errorf("experiment %s has no variants", experiment.Name)
+		}
+
+		experiment.totalWeight = 0
+		for _, variant := range experiment.Variants {
+			if variant.Name == "" {
+				return nil, fmt.Errorf("experiment %s has a variant with no name", experiment.Name)
+			}
+			variant.Experiment = experiment.Name
+			variant.FeedName = experiment.FeedName
+			experiment.totalWeight += variant.Weight
+		}
+
+		if experiment.totalWeight == 0 {
+			return nil, fmt.Errorf("experiment %s has no weighted variants", experiment.Name)
+		}
+
+		experimentsByFeed[experiment.FeedName] = experiment
+	}
+
+	return &Experiments{
+		ExperimentsByFeed: experimentsByFeed,
+	}, nil
+}
+
+// LoadExperiments reads a JSON list of experiments
+func LoadExperiments(r io.Reader) (*Experiments, error) {
+	experiments := []*Experiment{}
+	decoder := json.NewDecoder(r)
+	if err := decoder.Decode(&experiments); err != nil {
+		return nil, fmt.Errorf("failed to decode experiments: %w", err)
+	}
+
+	return NewExperiments(experiments)
+}
+
+// LoadExperimentsFromFile reads a JSON list of experiments from the given path
+func LoadExperimentsFromFile(path string) (*Experiments, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open experiments file: %w", err)
+	}
+	defer file.Close()
+
+	return LoadExperiments(file)
+}
+
+// Assign deterministically picks a variant for the given user
+// Users are hashed together with the experiment name so buckets are independent across experiments
+func (e *Experiment) Assign(userDID string) *Variant {
+	hash := fnv.New64a()
+	hash.Write([]byte(e.Name + ":" + userDID))
+	bucket := hash.Sum64() % e.totalWeight
+
+	for _, variant := range e.Variants {
+		if bucket < variant.Weight {
+			return variant
+		}
+		bucket -= variant.Weight
+	}
+
+	// Unreachable as long as totalWeight is the sum of the variant weights
+	return e.Variants[len(e.Variants)-1]
+}
+
+// AssignVariant returns the variant the user is bucketed into for the given feed
+// along with a context carrying it, or a nil variant if no experiment runs on the feed
+func (ex *Experiments) AssignVariant(ctx context.Context, feedName string, userDID string) (context.Context, *Variant) {
+	if ex == nil {
+		return ctx, nil
+	}
+
+	experiment, ok := ex.ExperimentsByFeed[feedName]
+	if !ok {
+		return ctx, nil
+	}
+
+	variant := experiment.Assign(userDID)
+
+	return context.WithValue(ctx, variantContextKey{}, variant), variant
+}
+
+// Segment is the name under which usage and engagement of a variant are tracked
+// Formatted as <feed_name>@<experiment>.<variant>
+func (v *Variant) Segment() string {
+	return fmt.Sprintf("%s@%s.%s", v.FeedName, v.Experiment, v.Name)
+}
+
+// VariantFromContext returns the variant assigned to the current request, if any
+func VariantFromContext(ctx context.Context) *Variant {
+	variant, _ := ctx.Value(variantContextKey{}).(*Variant)
+	return variant
+}
+
+// ConfigValue returns the value of a config key for the variant assigned to the current request
+// or the fallback if there is no variant or it doesn't set the key
+func ConfigValue(ctx context.Context, key string, fallback string) string {
+	variant := VariantFromContext(ctx)
+	if variant == nil {
+		return fallback
+	}
+
+	value, ok := variant.Config[key]
+	if !ok {
+		return fallback
+	}
+
+	return value
+}
+
+// ConfigInt returns the integer value of a config key for the variant assigned to the current request
+// or the fallback if there is no variant, it doesn't set the key, or the value isn't an integer
+func ConfigInt(ctx context.Context, key string, fallback int64) int64 {
+	value := ConfigValue(ctx, key, "")
+	if value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return fallback
+	}
+
+	return parsed
+}
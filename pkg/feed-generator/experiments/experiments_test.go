@@ -0,0 +1,85 @@
+package experiments
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadExperiments(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{
+			name:   "Valid experiment",
+			config: `[{"name": "ranker", "feed_name": "cv:cat", "variants": [{"name": "control", "weight": 1}, {"name": "chrono", "weight": 1, "config": {"ranker": "chronological"}}]}]`,
+		},
+		{
+			name:    "Duplicate feed",
+			config:  `[{"name": "a", "feed_name": "cv:cat", "variants": [{"name": "control", "weight": 1}]}, {"name": "b", "feed_name": "cv:cat", "variants": [{"name": "control", "weight": 1}]}]`,
+			wantErr: true,
+		},
+		{
+			name:    "No weight",
+			config:  `[{"name": "a", "feed_name": "cv:cat", "variants": [{"name": "control", "weight": 0}]}]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := LoadExperiments(strings.NewReader(tc.config))
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAssignVariant(t *testing.T) {
+	ex, err := NewExperiments([]*Experiment{
+		{
+			Name:     "ranker",
+			FeedName: "cv:cat",
+			Variants: []*Variant{
+				{Name: "control", Weight: 1},
+				{Name: "chrono", Weight: 1, Config: map[string]string{"ranker": "chronological"}},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+
+	// Feeds without an experiment get no variant
+	_, variant := ex.AssignVariant(ctx, "cv:dog", "did:plc:test")
+	assert.Nil(t, variant)
+
+	// Assignment is deterministic and roughly follows the weights
+	counts := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		userDID := fmt.Sprintf("did:plc:user%d", i)
+		variantCtx, variant := ex.AssignVariant(ctx, "cv:cat", userDID)
+		_, again := ex.AssignVariant(ctx, "cv:cat", userDID)
+		assert.Equal(t, variant, again)
+		assert.Equal(t, variant, VariantFromContext(variantCtx))
+		counts[variant.Name]++
+
+		if variant.Name == "chrono" {
+			assert.Equal(t, "chronological", ConfigValue(variantCtx, "ranker", "hotness"))
+			assert.Equal(t, "cv:cat@ranker.chrono", variant.Segment())
+		} else {
+			assert.Equal(t, "hotness", ConfigValue(variantCtx, "ranker", "hotness"))
+		}
+	}
+
+	assert.Greater(t, counts["control"], 400)
+	assert.Greater(t, counts["chrono"], 400)
+}
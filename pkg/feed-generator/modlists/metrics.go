@@ -0,0 +1,11 @@
+package modlists
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var feedItemsFilteredCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "feed_items_filtered_by_mod_lists_total",
+	Help: "The total number of feed items removed because their author is on a subscribed mod list",
+})
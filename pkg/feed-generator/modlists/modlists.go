@@ -0,0 +1,163 @@
+package modlists
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	appbsky "github.com/bluesky-social/indigo/api/bsky"
+	feedgenerator "github.com/ericvolp12/bsky-experiments/pkg/feed-generator"
+	"github.com/ericvolp12/bsky-experiments/pkg/search"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// maxSubscriptions bounds how many mod lists a user can filter their feeds with
+const maxSubscriptions = 50
+
+// ErrNotModList is returned when subscribing to a list that isn't a moderation list
+var ErrNotModList = errors.New("list is not a moderation list")
+
+// ErrTooManySubscriptions is returned when a user is already subscribed to maxSubscriptions lists
+var ErrTooManySubscriptions = fmt.Errorf("users can subscribe to at most %d mod lists", maxSubscriptions)
+
+// ModLists tracks the moderation lists users have subscribed to for feed filtering
+// Mute list subscriptions aren't published in repos, so users subscribe with the feed generator directly
+// Subscriptions are kept in a Redis set per user, list membership comes from the PostRegistry
+type ModLists struct {
+	Client       *redis.Client
+	Prefix       string
+	PostRegistry *search.PostRegistry
+}
+
+func NewModLists(ctx context.Context, client *redis.Client, prefix string, postRegistry *search.PostRegistry) (*ModLists, error) {
+	// Check if the client is connected
+	_, err := client.Ping(ctx).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to Redis: %w", err)
+	}
+
+	return &ModLists{
+		Client:       client,
+		Prefix:       prefix,
+		PostRegistry: postRegistry,
+	}, nil
+}
+
+func (ml *ModLists) subscriptionsKey(userDID string) string {
+	return fmt.Sprintf("%s:subscriptions:%s", ml.Prefix, userDID)
+}
+
+// Subscribe adds a mod list to the lists used to filter a user's feeds
+func (ml *ModLists) Subscribe(ctx context.Context, userDID string, listURI string) error {
+	tracer := otel.Tracer("modlists")
+	ctx, span := tracer.Start(ctx, "ModLists:Subscribe")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("list.uri", listURI))
+
+	list, err := ml.PostRegistry.GetList(ctx, listURI)
+	if err != nil {
+		return err
+	}
+
+	if list.Purpose != search.ModListPurpose {
+		return ErrNotModList
+	}
+
+	key := ml.subscriptionsKey(userDID)
+	count, err := ml.Client.SCard(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("error getting subscription count: %w", err)
+	}
+
+	if count >= maxSubscriptions {
+		return ErrTooManySubscriptions
+	}
+
+	err = ml.Client.SAdd(ctx, key, listURI).Err()
+	if err != nil {
+		return fmt.Errorf("error adding subscription: %w", err)
+	}
+
+	return nil
+}
+
+// Unsubscribe removes a mod list from the lists used to filter a user's feeds
+func (ml *ModLists) Unsubscribe(ctx context.Context, userDID string, listURI string) error {
+	tracer := otel.Tracer("modlists")
+	ctx, span := tracer.Start(ctx, "ModLists:Unsubscribe")
+	defer span.End()
+
+	err := ml.Client.SRem(ctx, ml.subscriptionsKey(userDID), listURI).Err()
+	if err != nil {
+		return fmt.Errorf("error removing subscription: %w", err)
+	}
+
+	return nil
+}
+
+// GetSubscriptions returns the URIs of the mod lists a user is subscribed to
+func (ml *ModLists) GetSubscriptions(ctx context.Context, userDID string) ([]string, error) {
+	tracer := otel.Tracer("modlists")
+	ctx, span := tracer.Start(ctx, "ModLists:GetSubscriptions")
+	defer span.End()
+
+	subscriptions, err := ml.Client.SMembers(ctx, ml.subscriptionsKey(userDID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error getting subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// FilterFeedItems removes posts by authors on any of the user's subscribed mod lists
+// ModLists may be nil, in which case feed items are returned unfiltered
+func (ml *ModLists) FilterFeedItems(ctx context.Context, userDID string, feedItems []*appbsky.FeedDefs_SkeletonFeedPost) ([]*appbsky.FeedDefs_SkeletonFeedPost, error) {
+	if ml == nil || userDID == "" || len(feedItems) == 0 {
+		return feedItems, nil
+	}
+
+	tracer := otel.Tracer("modlists")
+	ctx, span := tracer.Start(ctx, "ModLists:FilterFeedItems")
+	defer span.End()
+
+	subscriptions, err := ml.GetSubscriptions(ctx, userDID)
+	if err != nil {
+		return feedItems, err
+	}
+
+	span.SetAttributes(attribute.Int("modlists.subscriptions", len(subscriptions)))
+
+	if len(subscriptions) == 0 {
+		return feedItems, nil
+	}
+
+	authorDIDs := make([]string, len(feedItems))
+	for i, feedItem := range feedItems {
+		authorDIDs[i] = feedgenerator.AuthorDIDFromURI(feedItem.Post)
+	}
+
+	listed, err := ml.PostRegistry.GetListedSubjects(ctx, subscriptions, authorDIDs)
+	if err != nil {
+		return feedItems, err
+	}
+
+	if len(listed) == 0 {
+		return feedItems, nil
+	}
+
+	filtered := make([]*appbsky.FeedDefs_SkeletonFeedPost, 0, len(feedItems))
+	for i, feedItem := range feedItems {
+		if _, ok := listed[authorDIDs[i]]; ok {
+			continue
+		}
+		filtered = append(filtered, feedItem)
+	}
+
+	span.SetAttributes(attribute.Int("modlists.filtered", len(feedItems)-len(filtered)))
+	feedItemsFilteredCounter.Add(float64(len(feedItems) - len(filtered)))
+
+	return filtered, nil
+}
@@ -0,0 +1,16 @@
+package feedgenerator
+
+import "strings"
+
+// PostIDFromURI extracts the record key from an AT URI
+// at://did:plc:xyz/app.bsky.feed.post/3jx... -> 3jx...
+func PostIDFromURI(uri string) string {
+	return uri[strings.LastIndex(uri, "/")+1:]
+}
+
+// AuthorDIDFromURI extracts the repo DID from an AT URI
+// at://did:plc:xyz/app.bsky.feed.post/3jx... -> did:plc:xyz
+func AuthorDIDFromURI(uri string) string {
+	did, _, _ := strings.Cut(strings.TrimPrefix(uri, "at://"), "/")
+	return did
+}
@@ -0,0 +1,46 @@
+package feedgenerator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURIParts(t *testing.T) {
+	testCases := []struct {
+		uri       string
+		postID    string
+		authorDID string
+	}{
+		{
+			uri:       "at://did:plc:q6gjnaw2blty4crticxkmujt/app.bsky.feed.post/3jwvwlajglc2w",
+			postID:    "3jwvwlajglc2w",
+			authorDID: "did:plc:q6gjnaw2blty4crticxkmujt",
+		},
+		{
+			uri:       "at://did:web:feedsky.jazco.io/app.bsky.feed.post/3k2abc",
+			postID:    "3k2abc",
+			authorDID: "did:web:feedsky.jazco.io",
+		},
+		{
+			uri:       "did:plc:q6gjnaw2blty4crticxkmujt/app.bsky.feed.post/3jwvwlajglc2w",
+			postID:    "3jwvwlajglc2w",
+			authorDID: "did:plc:q6gjnaw2blty4crticxkmujt",
+		},
+		{
+			uri:       "at://did:plc:q6gjnaw2blty4crticxkmujt",
+			postID:    "did:plc:q6gjnaw2blty4crticxkmujt",
+			authorDID: "did:plc:q6gjnaw2blty4crticxkmujt",
+		},
+		{
+			uri: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.uri, func(t *testing.T) {
+			assert.Equal(t, tc.postID, PostIDFromURI(tc.uri))
+			assert.Equal(t, tc.authorDID, AuthorDIDFromURI(tc.uri))
+		})
+	}
+}
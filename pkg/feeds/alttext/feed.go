@@ -0,0 +1,83 @@
+package alttext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	appbsky "github.com/bluesky-social/indigo/api/bsky"
+	"github.com/ericvolp12/bsky-experiments/pkg/feeds"
+	"github.com/ericvolp12/bsky-experiments/pkg/search"
+	"go.opentelemetry.io/otel"
+)
+
+// AltTextFeed serves recent posts with images that are missing alt text
+// so volunteers can find them and reply with descriptions
+type AltTextFeed struct {
+	FeedActorDID string
+	PostRegistry *search.PostRegistry
+}
+
+type NotFoundError struct {
+	error
+}
+
+func NewAltTextFeed(ctx context.Context, feedActorDID string, postRegistry *search.PostRegistry) (*AltTextFeed, []string, error) {
+	return &AltTextFeed{
+		FeedActorDID: feedActorDID,
+		PostRegistry: postRegistry,
+	}, []string{"needs-alt-text"}, nil
+}
+
+func (atf *AltTextFeed) GetPage(ctx context.Context, feed string, userDID string, limit int64, cursor string) ([]*appbsky.FeedDefs_SkeletonFeedPost, *string, error) {
+	tracer := otel.Tracer("alt-text-feed")
+	ctx, span := tracer.Start(ctx, "GetPage")
+	defer span.End()
+
+	cursorCreatedAt, cursorPostID, err := feeds.ParseKeysetCursor(cursor)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing cursor: %w", err)
+	}
+
+	postsFromRegistry, err := atf.PostRegistry.GetPostsMissingAltText(ctx, int32(limit), cursorCreatedAt, cursorPostID)
+	if err != nil {
+		if errors.As(err, &search.NotFoundError{}) {
+			return nil, nil, NotFoundError{fmt.Errorf("posts not found for feed %s", feed)}
+		}
+		return nil, nil, fmt.Errorf("error getting posts from registry for feed (%s): %w", feed, err)
+	}
+
+	// Convert to appbsky.FeedDefs_SkeletonFeedPost
+	posts := []*appbsky.FeedDefs_SkeletonFeedPost{}
+	for _, post := range postsFromRegistry {
+		postAtURL := fmt.Sprintf("at://%s/app.bsky.feed.post/%s", post.AuthorDID, post.PostID)
+		posts = append(posts, &appbsky.FeedDefs_SkeletonFeedPost{
+			Post: postAtURL,
+		})
+	}
+
+	// If we got less than the limit, we're at the end of the feed
+	if int64(len(postsFromRegistry)) < limit {
+		return posts, nil, nil
+	}
+
+	// Get the cursor for the next page
+	lastPost := postsFromRegistry[len(postsFromRegistry)-1]
+	newCursor := feeds.AssembleKeysetCursor(lastPost.CreatedAt, lastPost.PostID)
+
+	return posts, &newCursor, nil
+}
+
+func (atf *AltTextFeed) Describe(ctx context.Context) ([]appbsky.FeedDescribeFeedGenerator_Feed, error) {
+	tracer := otel.Tracer("alt-text-feed")
+	ctx, span := tracer.Start(ctx, "Describe")
+	defer span.End()
+
+	feeds := []appbsky.FeedDescribeFeedGenerator_Feed{
+		{
+			Uri: "at://" + atf.FeedActorDID + "/app.bsky.feed.generator/" + "needs-alt-text",
+		},
+	}
+
+	return feeds, nil
+}
@@ -8,9 +8,11 @@ import (
 	"time"
 
 	appbsky "github.com/bluesky-social/indigo/api/bsky"
+	"github.com/ericvolp12/bsky-experiments/pkg/feed-generator/experiments"
 	"github.com/ericvolp12/bsky-experiments/pkg/feeds"
 	"github.com/ericvolp12/bsky-experiments/pkg/search"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type ClusterFeed struct {
@@ -81,7 +83,11 @@ func (cf *ClusterFeed) GetPage(ctx context.Context, feed string, userDID string,
 	// Slice the cluster feed prefix off the feed name
 	clusterName := strings.TrimPrefix(feed, "cluster-")
 
-	postsFromRegistry, err := cf.PostRegistry.GetPostsPageForCluster(ctx, clusterName, cf.DefaultLookbackHours, int32(limit), createdAt)
+	// Experiment variants can widen or narrow the lookback window
+	lookbackHours := int32(experiments.ConfigInt(ctx, "lookback_hours", int64(cf.DefaultLookbackHours)))
+	span.SetAttributes(attribute.Int("feed.lookback_hours", int(lookbackHours)))
+
+	postsFromRegistry, err := cf.PostRegistry.GetPostsPageForCluster(ctx, clusterName, lookbackHours, int32(limit), createdAt)
 	if err != nil {
 		if errors.As(err, &search.NotFoundError{}) {
 			return nil, nil, NotFoundError{fmt.Errorf("posts not found for feed %s", feed)}
@@ -141,3 +141,28 @@ func AssembleTimebasedCursor(createdAt time.Time, bloomFilter *bloom.BloomFilter
 
 	return cursor, nil
 }
+
+// ParseKeysetCursor takes a cursor string and returns the created at time and post ID of the last post served
+// Cursors are formatted as follows:
+// <createdAtUnixNano>:<postID>
+func ParseKeysetCursor(cursor string) (time.Time, string, error) {
+	if cursor == "" {
+		return time.Now(), "", nil
+	}
+
+	createdAtString, postID, ok := strings.Cut(cursor, ":")
+	if !ok || postID == "" {
+		return time.Time{}, "", ErrInvalidCursor{fmt.Errorf("cursor is invalid (wrong number of parts)")}
+	}
+
+	createdAtUnixNano, err := strconv.ParseInt(createdAtString, 10, 64)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor{fmt.Errorf("cursor is invalid (failed to parse createdAt)")}
+	}
+
+	return time.Unix(0, createdAtUnixNano), postID, nil
+}
+
+func AssembleKeysetCursor(createdAt time.Time, postID string) string {
+	return fmt.Sprintf("%d:%s", createdAt.UnixNano(), postID)
+}
@@ -9,9 +9,11 @@ import (
 
 	"github.com/bits-and-blooms/bloom/v3"
 	appbsky "github.com/bluesky-social/indigo/api/bsky"
+	"github.com/ericvolp12/bsky-experiments/pkg/feed-generator/experiments"
 	"github.com/ericvolp12/bsky-experiments/pkg/feeds"
 	"github.com/ericvolp12/bsky-experiments/pkg/search"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type PostLabelFeed struct {
@@ -95,8 +97,12 @@ func (plf *PostLabelFeed) GetPage(ctx context.Context, feed string, userDID stri
 	var createdAt time.Time
 	var err error
 
+	// Experiment variants can swap hotness ranking for chronological ranking
+	ranker := experiments.ConfigValue(ctx, "ranker", "hotness")
+	span.SetAttributes(attribute.String("feed.ranker", ranker))
+
 	cursorType := "standard"
-	if strings.HasPrefix(feed, "hellthread") {
+	if strings.HasPrefix(feed, "hellthread") || ranker == "chronological" {
 		cursorType = "timebased"
 	}
 
@@ -114,9 +120,17 @@ func (plf *PostLabelFeed) GetPage(ctx context.Context, feed string, userDID stri
 
 	switch feed {
 	case "animals":
-		postsFromRegistry, err = plf.PostRegistry.GetPostsPageForPostLabelsByHotness(ctx, animalLabels, int32(limit), cursorHotness)
+		if ranker == "chronological" {
+			postsFromRegistry, err = plf.PostRegistry.GetPostsPageForPostLabelsChronological(ctx, animalLabels, int32(limit), createdAt)
+		} else {
+			postsFromRegistry, err = plf.PostRegistry.GetPostsPageForPostLabelsByHotness(ctx, animalLabels, int32(limit), cursorHotness)
+		}
 	case "food":
-		postsFromRegistry, err = plf.PostRegistry.GetPostsPageForPostLabelsByHotness(ctx, foodLabels, int32(limit), cursorHotness)
+		if ranker == "chronological" {
+			postsFromRegistry, err = plf.PostRegistry.GetPostsPageForPostLabelsChronological(ctx, foodLabels, int32(limit), createdAt)
+		} else {
+			postsFromRegistry, err = plf.PostRegistry.GetPostsPageForPostLabelsByHotness(ctx, foodLabels, int32(limit), cursorHotness)
+		}
 	case "hellthread":
 		// Sort hellthread by chronological order instead of hotness
 		postsFromRegistry, err = plf.PostRegistry.GetPostsPageForPostLabelChronological(ctx, feed, int32(limit), createdAt)
@@ -124,7 +138,11 @@ func (plf *PostLabelFeed) GetPage(ctx context.Context, feed string, userDID stri
 		// Sort hellthread by chronological order instead of hotness
 		postsFromRegistry, err = plf.PostRegistry.GetPostsPageForPostLabelChronological(ctx, feed, int32(limit), createdAt)
 	default:
-		postsFromRegistry, err = plf.PostRegistry.GetPostsPageForPostLabelByHotness(ctx, feed, int32(limit), cursorHotness)
+		if ranker == "chronological" {
+			postsFromRegistry, err = plf.PostRegistry.GetPostsPageForPostLabelChronological(ctx, feed, int32(limit), createdAt)
+		} else {
+			postsFromRegistry, err = plf.PostRegistry.GetPostsPageForPostLabelByHotness(ctx, feed, int32(limit), cursorHotness)
+		}
 	}
 
 	if err != nil {
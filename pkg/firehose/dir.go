@@ -0,0 +1,69 @@
+package firehose
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bluesky-social/indigo/events"
+)
+
+// frameFileExt is the extension of captured frame files in a DirSource
+const frameFileExt = ".cbor"
+
+// DirSource replays a directory of captured frames, one raw stream message per file
+// Files are replayed in lexical order, so they should be named with zero-padded indexes
+type DirSource struct {
+	Dir string
+}
+
+func NewDirSource(dir string) *DirSource {
+	return &DirSource{Dir: dir}
+}
+
+func (ds *DirSource) Name() string {
+	return ds.Dir
+}
+
+func (ds *DirSource) Stream(ctx context.Context, cursor int64, sched events.Scheduler) error {
+	entries, err := os.ReadDir(ds.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to read frame directory: %w", err)
+	}
+
+	frameFiles := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), frameFileExt) {
+			continue
+		}
+		frameFiles = append(frameFiles, entry.Name())
+	}
+
+	sort.Strings(frameFiles)
+
+	for _, frameFile := range frameFiles {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		frame, err := os.ReadFile(filepath.Join(ds.Dir, frameFile))
+		if err != nil {
+			return fmt.Errorf("failed to read frame file %s: %w", frameFile, err)
+		}
+
+		err = replayFrame(ctx, frame, cursor, sched)
+		if err != nil {
+			return fmt.Errorf("failed to replay frame file %s: %w", frameFile, err)
+		}
+	}
+
+	return nil
+}
+
+// FrameFileName returns the name of the file the nth captured frame is stored in
+func FrameFileName(index int64) string {
+	return fmt.Sprintf("%012d%s", index, frameFileExt)
+}
@@ -0,0 +1,111 @@
+package firehose
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bluesky-social/indigo/events"
+)
+
+// maxFrameSize guards against reading a corrupt length prefix as a huge allocation
+const maxFrameSize = 64 << 20
+
+// FileSource replays frames recorded to a single file by a FrameWriter
+// Frames are stored back to back, each prefixed with its length as a big-endian uint32
+type FileSource struct {
+	Path string
+}
+
+func NewFileSource(path string) *FileSource {
+	return &FileSource{Path: path}
+}
+
+func (fs *FileSource) Name() string {
+	return fs.Path
+}
+
+func (fs *FileSource) Stream(ctx context.Context, cursor int64, sched events.Scheduler) error {
+	f, err := os.Open(fs.Path)
+	if err != nil {
+		return fmt.Errorf("failed to open recorded stream: %w", err)
+	}
+	defer f.Close()
+
+	reader := NewFrameReader(bufio.NewReader(f))
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		frame, err := reader.ReadFrame()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		err = replayFrame(ctx, frame, cursor, sched)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// FrameReader reads length-prefixed frames
+type FrameReader struct {
+	r io.Reader
+}
+
+func NewFrameReader(r io.Reader) *FrameReader {
+	return &FrameReader{r: r}
+}
+
+// ReadFrame returns the next frame, or io.EOF once the stream ends cleanly
+func (fr *FrameReader) ReadFrame() ([]byte, error) {
+	var size uint32
+	err := binary.Read(fr.r, binary.BigEndian, &size)
+	if err != nil {
+		return nil, err
+	}
+
+	if size > maxFrameSize {
+		return nil, fmt.Errorf("frame size %d exceeds maximum of %d", size, maxFrameSize)
+	}
+
+	frame := make([]byte, size)
+	_, err = io.ReadFull(fr.r, frame)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read frame: %w", err)
+	}
+
+	return frame, nil
+}
+
+// FrameWriter writes length-prefixed frames that can be replayed by a FileSource
+type FrameWriter struct {
+	w io.Writer
+}
+
+func NewFrameWriter(w io.Writer) *FrameWriter {
+	return &FrameWriter{w: w}
+}
+
+func (fw *FrameWriter) WriteFrame(frame []byte) error {
+	err := binary.Write(fw.w, binary.BigEndian, uint32(len(frame)))
+	if err != nil {
+		return fmt.Errorf("failed to write frame size: %w", err)
+	}
+
+	_, err = fw.w.Write(frame)
+	if err != nil {
+		return fmt.Errorf("failed to write frame: %w", err)
+	}
+
+	return nil
+}
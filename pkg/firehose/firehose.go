@@ -0,0 +1,129 @@
+// Package firehose provides sources of repo stream events, either live from a relay
+// or replayed from frames captured to disk, so the same pipeline can run against both.
+package firehose
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	comatproto "github.com/bluesky-social/indigo/api/atproto"
+	"github.com/bluesky-social/indigo/api/label"
+	"github.com/bluesky-social/indigo/events"
+)
+
+// Source is a stream of firehose events
+type Source interface {
+	// Stream reads events after the given cursor and hands them to the scheduler
+	// It returns nil once a finite source has been exhausted and an error if the stream breaks
+	Stream(ctx context.Context, cursor int64, sched events.Scheduler) error
+	// Name describes the source for logging
+	Name() string
+}
+
+// NewSource picks a Source implementation for the given location:
+// a ws:// or wss:// URL streams live from a relay, a directory replays captured frame files,
+// and any other path replays a recorded frame file
+func NewSource(location string) (Source, error) {
+	if strings.HasPrefix(location, "ws://") || strings.HasPrefix(location, "wss://") {
+		u, err := url.Parse(location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse relay URL: %w", err)
+		}
+		return NewRelaySource(*u), nil
+	}
+
+	info, err := os.Stat(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat firehose source: %w", err)
+	}
+
+	if info.IsDir() {
+		return NewDirSource(location), nil
+	}
+
+	return NewFileSource(location), nil
+}
+
+// DecodeFrame decodes a single binary stream message (a CBOR header followed by a CBOR body)
+// into an event, along with the repo it belongs to and its sequence number if it has one
+func DecodeFrame(frame []byte) (*events.XRPCStreamEvent, string, int64, error) {
+	r := bytes.NewReader(frame)
+
+	var header events.EventHeader
+	if err := header.UnmarshalCBOR(r); err != nil {
+		return nil, "", 0, fmt.Errorf("reading header: %w", err)
+	}
+
+	switch header.Op {
+	case events.EvtKindMessage:
+		switch header.MsgType {
+		case "#commit":
+			var evt comatproto.SyncSubscribeRepos_Commit
+			if err := evt.UnmarshalCBOR(r); err != nil {
+				return nil, "", 0, fmt.Errorf("reading repoCommit event: %w", err)
+			}
+			return &events.XRPCStreamEvent{RepoCommit: &evt}, evt.Repo, evt.Seq, nil
+		case "#handle":
+			var evt comatproto.SyncSubscribeRepos_Handle
+			if err := evt.UnmarshalCBOR(r); err != nil {
+				return nil, "", 0, fmt.Errorf("reading repoHandle event: %w", err)
+			}
+			return &events.XRPCStreamEvent{RepoHandle: &evt}, evt.Did, evt.Seq, nil
+		case "#info":
+			var evt comatproto.SyncSubscribeRepos_Info
+			if err := evt.UnmarshalCBOR(r); err != nil {
+				return nil, "", 0, fmt.Errorf("reading repoInfo event: %w", err)
+			}
+			return &events.XRPCStreamEvent{RepoInfo: &evt}, "", 0, nil
+		case "#migrate":
+			var evt comatproto.SyncSubscribeRepos_Migrate
+			if err := evt.UnmarshalCBOR(r); err != nil {
+				return nil, "", 0, fmt.Errorf("reading repoMigrate event: %w", err)
+			}
+			return &events.XRPCStreamEvent{RepoMigrate: &evt}, evt.Did, evt.Seq, nil
+		case "#tombstone":
+			var evt comatproto.SyncSubscribeRepos_Tombstone
+			if err := evt.UnmarshalCBOR(r); err != nil {
+				return nil, "", 0, fmt.Errorf("reading repoTombstone event: %w", err)
+			}
+			return &events.XRPCStreamEvent{RepoTombstone: &evt}, evt.Did, evt.Seq, nil
+		case "#labebatch":
+			var evt label.SubscribeLabels_Labels
+			if err := evt.UnmarshalCBOR(r); err != nil {
+				return nil, "", 0, fmt.Errorf("reading labels event: %w", err)
+			}
+			return &events.XRPCStreamEvent{LabelLabels: &evt}, "", evt.Seq, nil
+		default:
+			return nil, "", 0, fmt.Errorf("unrecognized message type: %s", header.MsgType)
+		}
+	case events.EvtKindErrorFrame:
+		var errframe events.ErrorFrame
+		if err := errframe.UnmarshalCBOR(r); err != nil {
+			return nil, "", 0, fmt.Errorf("reading error frame: %w", err)
+		}
+		return &events.XRPCStreamEvent{Error: &errframe}, "", 0, nil
+	default:
+		return nil, "", 0, fmt.Errorf("unrecognized event stream type: %d", header.Op)
+	}
+}
+
+// replayFrame decodes a captured frame and schedules it if it comes after the cursor
+func replayFrame(ctx context.Context, frame []byte, cursor int64, sched events.Scheduler) error {
+	evt, repo, seq, err := DecodeFrame(frame)
+	if err != nil {
+		return err
+	}
+
+	// Skip events at or before the cursor, events without a sequence number are always replayed
+	if seq != 0 && seq <= cursor {
+		return nil
+	}
+
+	framesReplayedCounter.Inc()
+
+	return sched.AddWork(ctx, repo, evt)
+}
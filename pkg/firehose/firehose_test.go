@@ -0,0 +1,91 @@
+package firehose
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	comatproto "github.com/bluesky-social/indigo/api/atproto"
+	"github.com/bluesky-social/indigo/events"
+	"github.com/stretchr/testify/assert"
+)
+
+type collectingScheduler struct {
+	seqs []int64
+}
+
+func (cs *collectingScheduler) AddWork(ctx context.Context, repo string, xe *events.XRPCStreamEvent) error {
+	cs.seqs = append(cs.seqs, xe.RepoHandle.Seq)
+	return nil
+}
+
+func handleFrame(t *testing.T, seq int64) []byte {
+	buf := bytes.Buffer{}
+	header := events.EventHeader{Op: events.EvtKindMessage, MsgType: "#handle"}
+	assert.NoError(t, header.MarshalCBOR(&buf))
+	evt := comatproto.SyncSubscribeRepos_Handle{
+		Did:    "did:plc:test",
+		Handle: "test.bsky.social",
+		Seq:    seq,
+		Time:   "2023-06-01T00:00:00Z",
+	}
+	assert.NoError(t, evt.MarshalCBOR(&buf))
+	return buf.Bytes()
+}
+
+func TestReplaySources(t *testing.T) {
+	dir := t.TempDir()
+
+	// Record the same frames to a file and to a directory
+	framesDir := filepath.Join(dir, "frames")
+	assert.NoError(t, os.Mkdir(framesDir, 0755))
+
+	f, err := os.Create(filepath.Join(dir, "stream.frames"))
+	assert.NoError(t, err)
+	writer := NewFrameWriter(f)
+
+	for i := int64(1); i <= 5; i++ {
+		frame := handleFrame(t, i+100)
+		assert.NoError(t, writer.WriteFrame(frame))
+		assert.NoError(t, os.WriteFile(filepath.Join(framesDir, FrameFileName(i)), frame, 0644))
+	}
+	assert.NoError(t, f.Close())
+
+	testCases := []struct {
+		name     string
+		location string
+		cursor   int64
+		expected []int64
+	}{
+		{
+			name:     "File from start",
+			location: filepath.Join(dir, "stream.frames"),
+			expected: []int64{101, 102, 103, 104, 105},
+		},
+		{
+			name:     "File from cursor",
+			location: filepath.Join(dir, "stream.frames"),
+			cursor:   103,
+			expected: []int64{104, 105},
+		},
+		{
+			name:     "Directory from start",
+			location: framesDir,
+			expected: []int64{101, 102, 103, 104, 105},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			source, err := NewSource(tc.location)
+			assert.NoError(t, err)
+
+			sched := &collectingScheduler{}
+			err = source.Stream(context.Background(), tc.cursor, sched)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, sched.seqs)
+		})
+	}
+}
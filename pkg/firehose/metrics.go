@@ -0,0 +1,11 @@
+package firehose
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var framesReplayedCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "firehose_frames_replayed_total",
+	Help: "The total number of captured frames replayed from disk",
+})
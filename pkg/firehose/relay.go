@@ -0,0 +1,46 @@
+package firehose
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/bluesky-social/indigo/events"
+	"github.com/gorilla/websocket"
+)
+
+// RelaySource streams events live from a relay's subscribeRepos websocket
+type RelaySource struct {
+	URL url.URL
+}
+
+func NewRelaySource(u url.URL) *RelaySource {
+	return &RelaySource{URL: u}
+}
+
+func (rs *RelaySource) Name() string {
+	return rs.URL.String()
+}
+
+func (rs *RelaySource) Stream(ctx context.Context, cursor int64, sched events.Scheduler) error {
+	u := rs.URL
+	if cursor > 0 {
+		u.RawQuery = fmt.Sprintf("cursor=%d", cursor)
+	}
+
+	c, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect to websocket: %w", err)
+	}
+	defer c.Close()
+
+	// Close the websocket when the context is cancelled to unblock the reader
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-streamCtx.Done()
+		c.Close()
+	}()
+
+	return events.HandleRepoStream(streamCtx, c, sched)
+}
@@ -0,0 +1,237 @@
+// Package imagehash computes perceptual hashes of images so near-duplicate images can be found
+// by the Hamming distance between their hashes, even after they've been resized or re-encoded.
+package imagehash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"math/bits"
+	"net/http"
+	"sort"
+
+	// Register the decoders for the formats images are served in
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"go.opentelemetry.io/otel"
+)
+
+// MaxImageBytes is the largest image Fetch will download
+const MaxImageBytes = 10 << 20
+
+const (
+	dHashWidth   = 9
+	dHashHeight  = 8
+	pHashSize    = 32
+	pHashLowFreq = 8
+	// pHashMinContrast is how many gray levels one of the low frequencies has to vary an image by for it to be hashed,
+	// the low frequencies of flatter images are rounding noise that would match every other flat image
+	pHashMinContrast = 2
+	// lowEntropyBits is how close to all zeros or all ones a pHash can be before it's considered low entropy
+	lowEntropyBits = 8
+)
+
+// ErrDecode is returned for images that can't be decoded, retrying them won't help
+var ErrDecode = errors.New("failed to decode image")
+
+type Hashes struct {
+	PHash uint64 `json:"phash"`
+	DHash uint64 `json:"dhash"`
+}
+
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code fetching image: %d", e.StatusCode)
+}
+
+// Distance returns the number of bits that differ between two hashes
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// LowEntropy reports whether a pHash is too close to all zeros or all ones to tell images apart
+// Splitting the frequencies around their median sets about half the bits of an image with any detail,
+// so these hashes come from flat or nearly flat images and match each other regardless of what's in them
+func LowEntropy(phash uint64) bool {
+	ones := bits.OnesCount64(phash)
+	return ones < lowEntropyBits || ones > 64-lowEntropyBits
+}
+
+// Compute returns both perceptual hashes of an image
+func Compute(img image.Image) Hashes {
+	return Hashes{
+		PHash: PHash(img),
+		DHash: DHash(img),
+	}
+}
+
+// DHash is a difference hash, each bit records whether a pixel of the image shrunk to 9x8 is darker than its right neighbor
+func DHash(img image.Image) uint64 {
+	pixels := grayscale(img, dHashWidth, dHashHeight)
+
+	var hash uint64
+	for y := 0; y < dHashHeight; y++ {
+		for x := 0; x < dHashWidth-1; x++ {
+			hash <<= 1
+			if pixels[y*dHashWidth+x] < pixels[y*dHashWidth+x+1] {
+				hash |= 1
+			}
+		}
+	}
+
+	return hash
+}
+
+// PHash is a DCT based hash, each bit records whether one of the 8x8 lowest frequencies
+// of the image shrunk to 32x32 is above the median of those frequencies
+// Flat images have no frequencies to speak of and hash to 0
+func PHash(img image.Image) uint64 {
+	pixels := grayscale(img, pHashSize, pHashSize)
+	coefficients := dct2D(pixels, pHashSize)
+
+	lowFreq := make([]float64, 0, pHashLowFreq*pHashLowFreq)
+	contrast := 0.0
+	for v := 0; v < pHashLowFreq; v++ {
+		for u := 0; u < pHashLowFreq; u++ {
+			coefficient := coefficients[v*pHashSize+u]
+			lowFreq = append(lowFreq, coefficient)
+			if u > 0 || v > 0 {
+				contrast = math.Max(contrast, math.Abs(coefficient))
+			}
+		}
+	}
+
+	// A frequency that varies the image by one gray level sums to about a quarter of the grid
+	if contrast < pHashMinContrast*pHashSize*pHashSize/4 {
+		return 0
+	}
+
+	// The DC term is the average brightness of the image so it's left out of the median
+	sorted := make([]float64, len(lowFreq)-1)
+	copy(sorted, lowFreq[1:])
+	sort.Float64s(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var hash uint64
+	for _, coefficient := range lowFreq {
+		hash <<= 1
+		if coefficient > median {
+			hash |= 1
+		}
+	}
+
+	return hash
+}
+
+// Decode decodes a GIF, JPEG, or PNG image
+func Decode(r io.Reader) (image.Image, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecode, err)
+	}
+	return img, nil
+}
+
+// Fetch downloads and decodes an image, non-200 responses are returned as a *StatusError
+func Fetch(ctx context.Context, client *http.Client, url string) (image.Image, error) {
+	tracer := otel.Tracer("imagehash")
+	ctx, span := tracer.Start(ctx, "Fetch")
+	defer span.End()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{StatusCode: resp.StatusCode}
+	}
+
+	return Decode(io.LimitReader(resp.Body, MaxImageBytes))
+}
+
+// grayscale shrinks an image to width x height by averaging the luma of the pixels that fall in each cell
+func grayscale(img image.Image, width, height int) []float64 {
+	bounds := img.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+
+	sums := make([]float64, width*height)
+	counts := make([]float64, width*height)
+
+	for y := 0; y < srcHeight; y++ {
+		cellY := y * height / srcHeight
+		for x := 0; x < srcWidth; x++ {
+			cellX := x * width / srcWidth
+			r, g, b, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			luma := 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+			sums[cellY*width+cellX] += luma / 0xffff * 255
+			counts[cellY*width+cellX]++
+		}
+	}
+
+	// Images smaller than the grid leave empty cells, fill them from the nearest source pixel
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			i := y*width + x
+			if counts[i] > 0 {
+				sums[i] /= counts[i]
+				continue
+			}
+			if srcWidth == 0 || srcHeight == 0 {
+				continue
+			}
+			r, g, b, _ := img.At(bounds.Min.X+x*srcWidth/width, bounds.Min.Y+y*srcHeight/height).RGBA()
+			sums[i] = (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)) / 0xffff * 255
+		}
+	}
+
+	return sums
+}
+
+// dct2D runs a type-II discrete cosine transform over the rows and then the columns of a size x size grid
+func dct2D(pixels []float64, size int) []float64 {
+	cosines := make([]float64, size*size)
+	for u := 0; u < size; u++ {
+		for x := 0; x < size; x++ {
+			cosines[u*size+x] = math.Cos(float64(2*x+1) * float64(u) * math.Pi / float64(2*size))
+		}
+	}
+
+	rows := make([]float64, size*size)
+	for y := 0; y < size; y++ {
+		for u := 0; u < size; u++ {
+			var sum float64
+			for x := 0; x < size; x++ {
+				sum += pixels[y*size+x] * cosines[u*size+x]
+			}
+			rows[y*size+u] = sum
+		}
+	}
+
+	coefficients := make([]float64, size*size)
+	for u := 0; u < size; u++ {
+		for v := 0; v < size; v++ {
+			var sum float64
+			for y := 0; y < size; y++ {
+				sum += rows[y*size+u] * cosines[v*size+y]
+			}
+			coefficients[v*size+u] = sum
+		}
+	}
+
+	return coefficients
+}
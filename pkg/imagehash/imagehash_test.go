@@ -0,0 +1,91 @@
+package imagehash
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func loadFixture(t *testing.T, name string) image.Image {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to open fixture %s: %v", name, err)
+	}
+	defer f.Close()
+
+	img, err := Decode(f)
+	if err != nil {
+		t.Fatalf("failed to decode fixture %s: %v", name, err)
+	}
+	return img
+}
+
+func TestDistance(t *testing.T) {
+	assert.Equal(t, 0, Distance(0xdeadbeef, 0xdeadbeef))
+	assert.Equal(t, 1, Distance(0, 1))
+	assert.Equal(t, 64, Distance(0, ^uint64(0)))
+}
+
+func TestUniformImage(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 64, 64))
+	for i := range img.Pix {
+		img.Pix[i] = 128
+	}
+
+	assert.Equal(t, uint64(0), DHash(img))
+	assert.Equal(t, uint64(0), DHash(image.NewRGBA(image.Rect(0, 0, 4, 4))))
+	assert.Equal(t, uint64(0), PHash(img))
+	assert.Equal(t, uint64(0), PHash(image.NewRGBA(image.Rect(0, 0, 4, 4))))
+
+	img.Set(0, 0, color.Gray{Y: 0})
+	assert.Equal(t, Compute(img), Compute(img))
+}
+
+func TestLowEntropy(t *testing.T) {
+	assert.True(t, LowEntropy(0))
+	assert.True(t, LowEntropy(^uint64(0)))
+	assert.True(t, LowEntropy(0x8000000000000001))
+	assert.True(t, LowEntropy(^uint64(0x8000000000000001)))
+	assert.False(t, LowEntropy(0x9692436c6d43f39d))
+
+	for _, fixture := range []string{"original.png", "resized.jpg", "brightened.jpg", "different.png"} {
+		assert.False(t, LowEntropy(PHash(loadFixture(t, fixture))), fixture)
+	}
+}
+
+func TestFixtures(t *testing.T) {
+	original := Compute(loadFixture(t, "original.png"))
+
+	tests := []struct {
+		fixture   string
+		duplicate bool
+	}{
+		{fixture: "original.png", duplicate: true},
+		{fixture: "resized.jpg", duplicate: true},
+		{fixture: "brightened.jpg", duplicate: true},
+		{fixture: "different.png", duplicate: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			hashes := Compute(loadFixture(t, tt.fixture))
+			pDistance := Distance(original.PHash, hashes.PHash)
+			dDistance := Distance(original.DHash, hashes.DHash)
+			t.Logf("phash distance %d, dhash distance %d", pDistance, dDistance)
+
+			if tt.duplicate {
+				assert.LessOrEqual(t, pDistance, 6)
+				assert.LessOrEqual(t, dDistance, 6)
+			} else {
+				assert.Greater(t, pDistance, 16)
+				assert.Greater(t, dDistance, 16)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package language
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pemistahl/lingua-go"
+)
+
+// Languages are the languages the Detector can tell apart, detection is
+// restricted to the languages commonly posted on Bluesky to keep it fast
+// Text in any other language comes back as the closest of these, usually with low confidence,
+// so callers should treat unconfident detections as unknown
+var Languages = []lingua.Language{
+	lingua.Arabic,
+	lingua.Chinese,
+	lingua.Dutch,
+	lingua.English,
+	lingua.French,
+	lingua.German,
+	lingua.Hindi,
+	lingua.Indonesian,
+	lingua.Italian,
+	lingua.Japanese,
+	lingua.Korean,
+	lingua.Persian,
+	lingua.Polish,
+	lingua.Portuguese,
+	lingua.Russian,
+	lingua.Spanish,
+	lingua.Turkish,
+	lingua.Ukrainian,
+}
+
+// Detector detects the language of post text
+type Detector struct {
+	detector lingua.LanguageDetector
+}
+
+// Detection is the most likely language of a text as a lowercase ISO 639-1 code
+type Detection struct {
+	Lang       string  `json:"lang"`
+	Confidence float64 `json:"confidence"`
+}
+
+func NewDetector() *Detector {
+	return &Detector{
+		detector: lingua.NewLanguageDetectorBuilder().
+			FromLanguages(Languages...).
+			Build(),
+	}
+}
+
+// Detect returns the most likely language of the text
+// ok is false when the text is too short or ambiguous to detect anything
+func (d *Detector) Detect(text string) (Detection, bool) {
+	values := d.detector.ComputeLanguageConfidenceValues(text)
+	if len(values) == 0 || values[0].Value() == 0 {
+		return Detection{}, false
+	}
+
+	return Detection{
+		Lang:       Code(values[0].Language()),
+		Confidence: values[0].Value(),
+	}, true
+}
+
+// Confidence returns the confidence that the text is written in the given language
+func (d *Detector) Confidence(text string, lang lingua.Language) float64 {
+	return d.detector.ComputeLanguageConfidence(text, lang)
+}
+
+// Code returns the lowercase ISO 639-1 code of a language
+func Code(lang lingua.Language) string {
+	return strings.ToLower(lang.IsoCode639_1().String())
+}
+
+// Normalize reduces a BCP 47 language tag like "pt-BR" to its lowercase primary language subtag
+func Normalize(tag string) string {
+	tag = strings.TrimSpace(tag)
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return strings.ToLower(tag)
+}
+
+// NormalizeAll normalizes a list of language tags, dropping empty and duplicate languages
+func NormalizeAll(tags []string) []string {
+	langs := []string{}
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		lang := Normalize(tag)
+		if lang == "" || lang == "*" || seen[lang] {
+			continue
+		}
+		seen[lang] = true
+		langs = append(langs, lang)
+	}
+	return langs
+}
+
+// ParseAcceptLanguage returns the languages in an Accept-Language header, most preferred first
+// Languages with a quality of zero are dropped
+func ParseAcceptLanguage(header string) []string {
+	type weightedTag struct {
+		tag     string
+		quality float64
+	}
+
+	tags := []weightedTag{}
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+			q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+			if err == nil {
+				quality = q
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+
+		tags = append(tags, weightedTag{tag: tag, quality: quality})
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].quality > tags[j].quality
+	})
+
+	raw := make([]string, len(tags))
+	for i, tag := range tags {
+		raw[i] = tag.tag
+	}
+
+	return NormalizeAll(raw)
+}
@@ -0,0 +1,44 @@
+package language
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		langs  []string
+	}{
+		{"empty", "", []string{}},
+		{"single", "ja", []string{"ja"}},
+		{"regions are dropped", "en-US,en;q=0.9,pt-BR;q=0.8", []string{"en", "pt"}},
+		{"sorted by quality", "de;q=0.5, fr, es;q=0.7", []string{"fr", "es", "de"}},
+		{"zero quality is dropped", "en, ko;q=0", []string{"en"}},
+		{"wildcard is dropped", "*, en;q=0.1", []string{"en"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.langs, ParseAcceptLanguage(tt.header))
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	detector := NewDetector()
+
+	detection, ok := detector.Detect("The weather has been lovely this week and I finally went for a long walk by the river.")
+	assert.True(t, ok)
+	assert.Equal(t, "en", detection.Lang)
+	assert.Greater(t, detection.Confidence, 0.5)
+
+	detection, ok = detector.Detect("Hoje o tempo está ótimo e eu fui caminhar na praia com os meus amigos.")
+	assert.True(t, ok)
+	assert.Equal(t, "pt", detection.Lang)
+
+	_, ok = detector.Detect("")
+	assert.False(t, ok)
+}
@@ -1,14 +1,11 @@
 package objectdetection
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
 	"time"
 
-	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"github.com/ericvolp12/bsky-experiments/pkg/sidecar"
 	"go.opentelemetry.io/otel"
 )
 
@@ -35,51 +32,39 @@ type ImageProcessor interface {
 }
 
 type ObjectDetectionImpl struct {
-	ObjectDetectionServiceHost string
-	Client                     *http.Client
+	Client *sidecar.Client
 }
 
 func NewObjectDetection(objectDetectionServiceHost string) *ObjectDetectionImpl {
-	client := http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
-
 	return &ObjectDetectionImpl{
-		ObjectDetectionServiceHost: objectDetectionServiceHost,
-		Client:                     &client,
+		Client: sidecar.NewClient("object-detection", objectDetectionServiceHost, sidecar.Options{
+			Timeout:      time.Minute,
+			MaxBatchSize: 25,
+		}),
 	}
 }
 
+// WaitUntilAvailable blocks while the object detection service is down
+func (o *ObjectDetectionImpl) WaitUntilAvailable(ctx context.Context) error {
+	return o.Client.WaitUntilAvailable(ctx)
+}
+
+// ProcessImages runs object detection over the images in batches
+// If a batch fails the results of the batches before it are returned along with the error
 func (o *ObjectDetectionImpl) ProcessImages(ctx context.Context, imageMetas []*ImageMeta) ([]*ImageResult, error) {
 	tracer := otel.Tracer("ObjectDetection")
 	ctx, span := tracer.Start(ctx, "ProcessImages")
 	defer span.End()
 
-	url := fmt.Sprintf("%s/detect_objects", o.ObjectDetectionServiceHost)
-
-	reqBody := imageMetas
-	jsonReqBody, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal object-detection request body: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonReqBody))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create object-detection request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := o.Client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send object-detection request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("object-detection request failed with status code %d", resp.StatusCode)
-	}
+	imageResults := make([]*ImageResult, 0, len(imageMetas))
 
-	var imageResults []*ImageResult
-	if err := json.NewDecoder(resp.Body).Decode(&imageResults); err != nil {
-		return nil, fmt.Errorf("failed to decode object-detection response body: %w", err)
+	for _, batch := range sidecar.Batches(imageMetas, o.Client.Options.MaxBatchSize) {
+		var batchResults []*ImageResult
+		err := o.Client.PostJSON(ctx, "/detect_objects", batch, &batchResults)
+		if err != nil {
+			return imageResults, fmt.Errorf("object-detection request failed: %w", err)
+		}
+		imageResults = append(imageResults, batchResults...)
 	}
 
 	return imageResults, nil
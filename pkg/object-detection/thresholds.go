@@ -0,0 +1,74 @@
+package objectdetection
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultThreshold is the confidence a detection needs to label its post when no threshold is set for its label
+const DefaultThreshold = 0.75
+
+// Thresholds are the minimum confidences detections need to label their posts
+type Thresholds struct {
+	Default float64
+	Labels  map[string]float64
+}
+
+// ParseThresholds parses a comma separated list of per-label confidence thresholds
+// i.e. "person=0.9,dog=0.6", labels not in the list use the default
+func ParseThresholds(raw string, def float64) (*Thresholds, error) {
+	thresholds := &Thresholds{
+		Default: def,
+		Labels:  map[string]float64{},
+	}
+
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		label, rawThreshold, ok := strings.Cut(part, "=")
+		label = strings.ToLower(strings.TrimSpace(label))
+		if !ok || label == "" {
+			return nil, fmt.Errorf("invalid label threshold %q, expected label=confidence", part)
+		}
+
+		threshold, err := strconv.ParseFloat(strings.TrimSpace(rawThreshold), 64)
+		if err != nil || threshold < 0 || threshold > 1 {
+			return nil, fmt.Errorf("invalid confidence for label %q, expected a number between 0 and 1", label)
+		}
+
+		thresholds.Labels[label] = threshold
+	}
+
+	return thresholds, nil
+}
+
+// For returns the confidence threshold for a label
+func (t *Thresholds) For(label string) float64 {
+	if threshold, ok := t.Labels[strings.ToLower(label)]; ok {
+		return threshold
+	}
+	return t.Default
+}
+
+// PassingLabels returns the distinct labels of the detections that meet their threshold, in order of first appearance
+func (t *Thresholds) PassingLabels(detections []DetectionResult) []string {
+	labels := []string{}
+	seen := map[string]struct{}{}
+
+	for _, detection := range detections {
+		if detection.Confidence < t.For(detection.Label) {
+			continue
+		}
+		if _, ok := seen[detection.Label]; ok {
+			continue
+		}
+		seen[detection.Label] = struct{}{}
+		labels = append(labels, detection.Label)
+	}
+
+	return labels
+}
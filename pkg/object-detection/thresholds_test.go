@@ -0,0 +1,69 @@
+package objectdetection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseThresholds(t *testing.T) {
+	thresholds, err := ParseThresholds(" Person=0.9, dog=0.6,", DefaultThreshold)
+	assert.NoError(t, err)
+	assert.Equal(t, &Thresholds{
+		Default: DefaultThreshold,
+		Labels:  map[string]float64{"person": 0.9, "dog": 0.6},
+	}, thresholds)
+
+	thresholds, err = ParseThresholds("", 0.5)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, thresholds.For("cat"))
+
+	for _, raw := range []string{"person", "=0.5", "person=high", "person=1.5", "person=-0.1"} {
+		_, err := ParseThresholds(raw, DefaultThreshold)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestPassingLabels(t *testing.T) {
+	thresholds := &Thresholds{
+		Default: 0.75,
+		Labels:  map[string]float64{"person": 0.9, "dog": 0.5},
+	}
+
+	tests := []struct {
+		name       string
+		detections []DetectionResult
+		want       []string
+	}{
+		{
+			name:       "no detections",
+			detections: nil,
+			want:       []string{},
+		},
+		{
+			name: "per label thresholds",
+			detections: []DetectionResult{
+				{Label: "person", Confidence: 0.85},
+				{Label: "dog", Confidence: 0.55},
+				{Label: "cat", Confidence: 0.7},
+				{Label: "car", Confidence: 0.8},
+			},
+			want: []string{"dog", "car"},
+		},
+		{
+			name: "duplicate labels",
+			detections: []DetectionResult{
+				{Label: "person", Confidence: 0.95},
+				{Label: "person", Confidence: 0.5},
+				{Label: "person", Confidence: 0.91},
+			},
+			want: []string{"person"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, thresholds.PassingLabels(tt.detections))
+		})
+	}
+}
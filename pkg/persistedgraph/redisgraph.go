@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -26,6 +27,9 @@ type PersistedGraph struct {
 	EdgeKey        string
 	LastUpdatedKey string
 	CursorKey      string
+	// AdjacencyPrefix prefixes the per-node sets of the identifiers of the edges to and from the node
+	AdjacencyPrefix     string
+	AdjacencyIndexedKey string
 }
 
 func NewPersistedGraph(ctx context.Context, client *redis.Client, prefix string) (*PersistedGraph, error) {
@@ -39,6 +43,8 @@ func NewPersistedGraph(ctx context.Context, client *redis.Client, prefix string)
 	edgeKey := prefix + ":edges"
 	lastUpdatedKey := prefix + ":last-updated"
 	cursorKey := prefix + ":cursor"
+	adjacencyPrefix := prefix + ":adjacency:"
+	adjacencyIndexedKey := prefix + ":adjacency-indexed"
 
 	// Look for the last updated time and cursor
 	lastUpdated, err := client.Get(ctx, lastUpdatedKey).Result()
@@ -67,17 +73,93 @@ func NewPersistedGraph(ctx context.Context, client *redis.Client, prefix string)
 		cursor = ""
 	}
 
-	return &PersistedGraph{
-		Client:         client,
-		Prefix:         prefix,
-		NodeKey:        nodeKey,
-		EdgeKey:        edgeKey,
-		LastUpdatedKey: lastUpdatedKey,
-		CursorKey:      cursorKey,
-		LastUpdated:    lastUpdatedTime,
-		Cursor:         cursor,
-		CursorMux:      sync.RWMutex{},
-	}, nil
+	g := &PersistedGraph{
+		Client:              client,
+		Prefix:              prefix,
+		NodeKey:             nodeKey,
+		EdgeKey:             edgeKey,
+		LastUpdatedKey:      lastUpdatedKey,
+		CursorKey:           cursorKey,
+		AdjacencyPrefix:     adjacencyPrefix,
+		AdjacencyIndexedKey: adjacencyIndexedKey,
+		LastUpdated:         lastUpdatedTime,
+		Cursor:              cursor,
+		CursorMux:           sync.RWMutex{},
+	}
+
+	return g, nil
+}
+
+// IsAdjacencyIndexed reports whether the adjacency sets have been built for the edges in the graph
+// Graphs persisted before the adjacency sets existed need BuildAdjacencyIndex run on them once
+func (g *PersistedGraph) IsAdjacencyIndexed(ctx context.Context) (bool, error) {
+	indexed, err := g.Client.Exists(ctx, g.AdjacencyIndexedKey).Result()
+	if err != nil {
+		return false, fmt.Errorf("error checking adjacency index in Redis: %w", err)
+	}
+	return indexed > 0, nil
+}
+
+// BuildAdjacencyIndex adds every edge in the graph to the adjacency sets of its nodes and marks the graph as indexed
+func (g *PersistedGraph) BuildAdjacencyIndex(ctx context.Context) error {
+	tracer := otel.Tracer("persistentgraph")
+	ctx, span := tracer.Start(ctx, "BuildAdjacencyIndex")
+	defer span.End()
+
+	pipe := g.Client.Pipeline()
+	iter := g.Client.HScan(ctx, g.EdgeKey, 0, "*", 100000).Iterator()
+	for iter.Next(ctx) {
+		edgeIdentifier := iter.Val()
+		// Skip the value
+		iter.Next(ctx)
+
+		edge := strings.Split(edgeIdentifier, "-")
+		if len(edge) != 2 {
+			log.Printf("Invalid edge identifier: %s", edgeIdentifier)
+			continue
+		}
+		pipe.SAdd(ctx, g.adjacencyKey(graph.NodeID(edge[0])), edgeIdentifier)
+		pipe.SAdd(ctx, g.adjacencyKey(graph.NodeID(edge[1])), edgeIdentifier)
+
+		if pipe.Len() >= 10000 {
+			if _, err := pipe.Exec(ctx); err != nil {
+				return fmt.Errorf("error building adjacency index in Redis: %w", err)
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return fmt.Errorf("error scanning edges from Redis: %w", err)
+	}
+
+	if pipe.Len() > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return fmt.Errorf("error building adjacency index in Redis: %w", err)
+		}
+	}
+
+	err := g.Client.Set(ctx, g.AdjacencyIndexedKey, time.Now().Format(time.RFC3339), 0).Err()
+	if err != nil {
+		return fmt.Errorf("error marking adjacency index as built in Redis: %w", err)
+	}
+
+	return nil
+}
+
+func (g *PersistedGraph) adjacencyKey(nodeID graph.NodeID) string {
+	return g.AdjacencyPrefix + string(nodeID)
+}
+
+// addAdjacency records the edge in the adjacency sets of both of its nodes
+func (g *PersistedGraph) addAdjacency(ctx context.Context, from, to graph.NodeID, edgeIdentifier string) error {
+	_, err := g.Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, g.adjacencyKey(from), edgeIdentifier)
+		pipe.SAdd(ctx, g.adjacencyKey(to), edgeIdentifier)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error setting edge adjacency in Redis: %w", err)
+	}
+	return nil
 }
 
 // AddNode adds a new node with the given NodeID to the graph.
@@ -86,9 +168,15 @@ func (g *PersistedGraph) AddNode(ctx context.Context, node graph.Node) error {
 	tracer := otel.Tracer("persistentgraph")
 	ctx, span := tracer.Start(ctx, "AddNode")
 	defer span.End()
-	cmd := g.Client.HSet(ctx, g.NodeKey, string(node.DID), node.Handle)
-	if cmd.Err() != nil {
-		return fmt.Errorf("error setting node in Redis: %w", cmd.Err())
+	// Don't clobber a known handle when the caller doesn't have one
+	var err error
+	if node.Handle == "" {
+		err = g.Client.HSetNX(ctx, g.NodeKey, string(node.DID), node.Handle).Err()
+	} else {
+		err = g.Client.HSet(ctx, g.NodeKey, string(node.DID), node.Handle).Err()
+	}
+	if err != nil {
+		return fmt.Errorf("error setting node in Redis: %w", err)
 	}
 
 	// Update the last updated time
@@ -134,6 +222,11 @@ func (g *PersistedGraph) AddEdge(ctx context.Context, from, to graph.Node, weigh
 		return fmt.Errorf("error setting edge in Redis: %w", cmd.Err())
 	}
 
+	err = g.addAdjacency(ctx, from.DID, to.DID, edgeIdentifier)
+	if err != nil {
+		return err
+	}
+
 	// Update the last updated time
 	g.CursorMux.Lock()
 	g.LastUpdated = time.Now()
@@ -205,6 +298,113 @@ func (g *PersistedGraph) IncrementEdge(ctx context.Context, from, to graph.Node,
 		return fmt.Errorf("error setting edge in Redis: %w", cmd.Err())
 	}
 
+	err = g.addAdjacency(ctx, from.DID, to.DID, edgeIdentifier)
+	if err != nil {
+		return err
+	}
+
+	// Update the last updated time
+	g.CursorMux.Lock()
+	g.LastUpdated = time.Now()
+	g.Client.Set(ctx, g.LastUpdatedKey, g.LastUpdated, 0)
+	g.CursorMux.Unlock()
+
+	return nil
+}
+
+// DecrementEdge decrements the weight of an edge between two nodes by the specified value.
+// If the weight drops to zero or below, the edge is removed from the graph.
+func (g *PersistedGraph) DecrementEdge(ctx context.Context, from, to graph.Node, weight int) error {
+	tracer := otel.Tracer("persistentgraph")
+	ctx, span := tracer.Start(ctx, "DecrementEdge")
+	defer span.End()
+
+	edgeIdentifier := string(from.DID) + "-" + string(to.DID)
+	newWeight, err := g.Client.HIncrBy(ctx, g.EdgeKey, edgeIdentifier, int64(-weight)).Result()
+	if err != nil {
+		return fmt.Errorf("error decrementing edge in Redis: %w", err)
+	}
+
+	if newWeight <= 0 {
+		_, err = g.Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HDel(ctx, g.EdgeKey, edgeIdentifier)
+			pipe.SRem(ctx, g.adjacencyKey(from.DID), edgeIdentifier)
+			pipe.SRem(ctx, g.adjacencyKey(to.DID), edgeIdentifier)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("error removing edge from Redis: %w", err)
+		}
+	}
+
+	// Update the last updated time
+	g.CursorMux.Lock()
+	g.LastUpdated = time.Now()
+	g.Client.Set(ctx, g.LastUpdatedKey, g.LastUpdated, 0)
+	g.CursorMux.Unlock()
+
+	return nil
+}
+
+// UpdateNodeHandle sets the handle of a node if the node is already in the graph.
+func (g *PersistedGraph) UpdateNodeHandle(ctx context.Context, node graph.Node) error {
+	tracer := otel.Tracer("persistentgraph")
+	ctx, span := tracer.Start(ctx, "UpdateNodeHandle")
+	defer span.End()
+
+	exists, err := g.Client.HExists(ctx, g.NodeKey, string(node.DID)).Result()
+	if err != nil {
+		return fmt.Errorf("error checking node in Redis: %w", err)
+	}
+
+	if !exists {
+		return nil
+	}
+
+	err = g.Client.HSet(ctx, g.NodeKey, string(node.DID), node.Handle).Err()
+	if err != nil {
+		return fmt.Errorf("error setting node in Redis: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveNode removes a node and all of its inbound and outbound edges from the graph.
+func (g *PersistedGraph) RemoveNode(ctx context.Context, nodeID graph.NodeID) error {
+	tracer := otel.Tracer("persistentgraph")
+	ctx, span := tracer.Start(ctx, "RemoveNode")
+	defer span.End()
+
+	// Find all edges to and from the node
+	edgeIdentifiers, err := g.Client.SMembers(ctx, g.adjacencyKey(nodeID)).Result()
+	if err != nil {
+		return fmt.Errorf("error getting node adjacency from Redis: %w", err)
+	}
+
+	// Remove the edges and drop them from the adjacency sets of the nodes on their other ends
+	_, err = g.Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		if len(edgeIdentifiers) > 0 {
+			pipe.HDel(ctx, g.EdgeKey, edgeIdentifiers...)
+		}
+		for _, edgeIdentifier := range edgeIdentifiers {
+			for _, other := range strings.Split(edgeIdentifier, "-") {
+				if other != string(nodeID) {
+					pipe.SRem(ctx, g.adjacencyKey(graph.NodeID(other)), edgeIdentifier)
+				}
+			}
+		}
+		pipe.Del(ctx, g.adjacencyKey(nodeID))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error removing edges from Redis: %w", err)
+	}
+
+	err = g.Client.HDel(ctx, g.NodeKey, string(nodeID)).Err()
+	if err != nil {
+		return fmt.Errorf("error removing node from Redis: %w", err)
+	}
+
 	// Update the last updated time
 	g.CursorMux.Lock()
 	g.LastUpdated = time.Now()
@@ -300,3 +500,60 @@ func (g *PersistedGraph) Write(ctx context.Context, writer io.Writer) error {
 
 	return nil
 }
+
+// ReadGraph loads the persisted graph into an in-memory graph.Graph so it can be
+// exported with any of the graph.ReaderWriter implementations.
+func (g *PersistedGraph) ReadGraph(ctx context.Context) (graph.Graph, error) {
+	tracer := otel.Tracer("persistentgraph")
+	ctx, span := tracer.Start(ctx, "ReadGraph")
+	defer span.End()
+
+	out := graph.NewGraph()
+
+	// Get all nodes from Redis in chunks of 10000
+	iter := g.Client.HScan(ctx, g.NodeKey, 0, "*", 10000).Iterator()
+	for iter.Next(ctx) {
+		did := iter.Val()
+		hasVal := iter.Next(ctx)
+		if !hasVal {
+			log.Printf("Iterator stopped mid-node: %s", did)
+			continue
+		}
+		out.AddNode(graph.Node{DID: graph.NodeID(did), Handle: iter.Val()})
+	}
+	if err := iter.Err(); err != nil {
+		return out, fmt.Errorf("error scanning nodes from Redis: %w", err)
+	}
+
+	// Get all edges from Redis in chunks of 100000
+	iter = g.Client.HScan(ctx, g.EdgeKey, 0, "*", 100000).Iterator()
+	for iter.Next(ctx) {
+		edgeIdentifier := iter.Val()
+		hasVal := iter.Next(ctx)
+		if !hasVal {
+			log.Printf("Iterator stopped mid-edge: %s", edgeIdentifier)
+			continue
+		}
+
+		edge := strings.Split(edgeIdentifier, "-")
+		if len(edge) != 2 {
+			log.Printf("Invalid edge identifier: %s", edgeIdentifier)
+			continue
+		}
+
+		weight, err := strconv.Atoi(iter.Val())
+		if err != nil {
+			log.Printf("Invalid edge weight for %s: %s", edgeIdentifier, iter.Val())
+			continue
+		}
+
+		from := graph.NodeID(edge[0])
+		to := graph.NodeID(edge[1])
+		out.AddEdge(graph.Node{DID: from, Handle: out.Nodes[from].Handle}, graph.Node{DID: to, Handle: out.Nodes[to].Handle}, weight)
+	}
+	if err := iter.Err(); err != nil {
+		return out, fmt.Errorf("error scanning edges from Redis: %w", err)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,102 @@
+package plc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// maxDIDDocumentSize caps how much of a DID document is read, did:web documents can come from any host
+const maxDIDDocumentSize = 1 << 20
+
+// DIDDocument is the subset of a DID document needed to find the services a DID points at
+type DIDDocument struct {
+	ID      string       `json:"id"`
+	Service []DIDService `json:"service"`
+}
+
+type DIDService struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	ServiceEndpoint string `json:"serviceEndpoint"`
+}
+
+// ServiceEndpoint returns the endpoint of the service with the given ID fragment (i.e. "#atproto_pds")
+func (doc *DIDDocument) ServiceEndpoint(fragment string) (string, error) {
+	for _, service := range doc.Service {
+		if service.ID == fragment || service.ID == doc.ID+fragment {
+			if service.ServiceEndpoint == "" {
+				return "", fmt.Errorf("service %s has no endpoint", fragment)
+			}
+			return service.ServiceEndpoint, nil
+		}
+	}
+	return "", fmt.Errorf("DID document has no %s service", fragment)
+}
+
+// DIDDocumentURL returns the URL the DID document of a did:plc or did:web can be fetched from
+func DIDDocumentURL(plcDirectory string, did string) (string, error) {
+	switch {
+	case strings.HasPrefix(did, "did:plc:"):
+		return strings.TrimSuffix(plcDirectory, "/") + "/" + did, nil
+	case strings.HasPrefix(did, "did:web:"):
+		domain := strings.TrimPrefix(did, "did:web:")
+		// Colons separate path segments in did:web, which atproto doesn't use
+		if strings.Contains(domain, ":") {
+			return "", fmt.Errorf("did:web with a path is not supported: %q", did)
+		}
+		// Ports are percent-encoded
+		host, err := url.PathUnescape(domain)
+		if err != nil || host == "" || strings.Contains(host, "/") {
+			return "", fmt.Errorf("invalid did:web %q", did)
+		}
+		return "https://" + host + "/.well-known/did.json", nil
+	default:
+		return "", fmt.Errorf("unsupported DID method: %q", did)
+	}
+}
+
+// ResolveDIDDocument fetches the DID document of a did:plc from the PLC directory or of a did:web from its domain
+// The client should have a short timeout since did:web hosts aren't trusted
+func ResolveDIDDocument(ctx context.Context, client *http.Client, plcDirectory string, did string) (*DIDDocument, error) {
+	tracer := otel.Tracer("plc")
+	ctx, span := tracer.Start(ctx, "ResolveDIDDocument")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("did", did))
+
+	docURL, err := DIDDocumentURL(plcDirectory, did)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", docURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request for %s: %w", did, err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error getting DID document for %s: %w", did, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code getting DID document for %s: %d", did, resp.StatusCode)
+	}
+
+	doc := &DIDDocument{}
+	err = json.NewDecoder(io.LimitReader(resp.Body, maxDIDDocumentSize)).Decode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding DID document for %s: %w", did, err)
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,95 @@
+package plc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDIDDocumentURL(t *testing.T) {
+	testCases := []struct {
+		name    string
+		did     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "PLC DID",
+			did:  "did:plc:q6gjnaw2blty4crticxkmujt",
+			want: "https://plc.directory/did:plc:q6gjnaw2blty4crticxkmujt",
+		},
+		{
+			name: "Web DID",
+			did:  "did:web:feedgen.jazco.io",
+			want: "https://feedgen.jazco.io/.well-known/did.json",
+		},
+		{
+			name: "Web DID with port",
+			did:  "did:web:localhost%3A8080",
+			want: "https://localhost:8080/.well-known/did.json",
+		},
+		{
+			name:    "Web DID with path",
+			did:     "did:web:example.com:feeds",
+			wantErr: true,
+		},
+		{
+			name:    "Unsupported method",
+			did:     "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := DIDDocumentURL("https://plc.directory", tc.did)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestServiceEndpoint(t *testing.T) {
+	testCases := []struct {
+		name    string
+		doc     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Relative service ID",
+			doc:  `{"id": "did:web:feedgen.jazco.io", "service": [{"id": "#bsky_fg", "type": "BskyFeedGenerator", "serviceEndpoint": "https://feedgen.jazco.io"}]}`,
+			want: "https://feedgen.jazco.io",
+		},
+		{
+			name: "Absolute service ID",
+			doc:  `{"id": "did:web:feedgen.jazco.io", "service": [{"id": "did:web:feedgen.jazco.io#bsky_fg", "type": "BskyFeedGenerator", "serviceEndpoint": "https://feedgen.jazco.io"}]}`,
+			want: "https://feedgen.jazco.io",
+		},
+		{
+			name:    "Only a PDS",
+			doc:     `{"id": "did:plc:q6gjnaw2blty4crticxkmujt", "service": [{"id": "#atproto_pds", "type": "AtprotoPersonalDataServer", "serviceEndpoint": "https://bsky.social"}]}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := DIDDocument{}
+			assert.NoError(t, json.Unmarshal([]byte(tc.doc), &doc))
+
+			got, err := doc.ServiceEndpoint("#bsky_fg")
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search/search_queries"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// AccessibilityScorePriorImages is how many images at the global alt text coverage every author's score starts from
+// This keeps authors with only a handful of images from landing at 0 or 100
+const AccessibilityScorePriorImages = 10
+
+type AltTextCoverage struct {
+	ImageCount   int64   `json:"image_count"`
+	AltTextCount int64   `json:"alt_text_count"`
+	Coverage     float64 `json:"coverage"`
+}
+
+type ClusterAltTextCoverage struct {
+	ClusterID   int32  `json:"cluster_id"`
+	LookupAlias string `json:"lookup_alias"`
+	Name        string `json:"name"`
+	AltTextCoverage
+}
+
+type AuthorAltTextCoverage struct {
+	AuthorDID          string  `json:"author_did"`
+	Handle             string  `json:"handle,omitempty"`
+	AccessibilityScore float64 `json:"accessibility_score"`
+	AltTextCoverage
+}
+
+type AltTextStats struct {
+	AltTextCoverage
+	AuthorCount     int64                    `json:"author_count"`
+	Clusters        []ClusterAltTextCoverage `json:"clusters"`
+	TopImagePosters []AuthorAltTextCoverage  `json:"top_image_posters"`
+}
+
+type PostMissingAltText struct {
+	PostID     string    `json:"post_id"`
+	AuthorDID  string    `json:"author_did"`
+	CreatedAt  time.Time `json:"created_at"`
+	ImageCount int64     `json:"image_count"`
+}
+
+// NewAltTextCoverage returns the share of images that have alt text
+func NewAltTextCoverage(imageCount int64, altTextCount int64) AltTextCoverage {
+	coverage := AltTextCoverage{
+		ImageCount:   imageCount,
+		AltTextCount: altTextCount,
+	}
+	if imageCount > 0 {
+		coverage.Coverage = float64(altTextCount) / float64(imageCount)
+	}
+	return coverage
+}
+
+// AccessibilityScore rates how consistently an author adds alt text to their images from 0 to 100
+// The score is smoothed towards the global coverage so it only moves far from it once an author has posted enough images
+func AccessibilityScore(coverage AltTextCoverage, globalCoverage float64) float64 {
+	prior := float64(AccessibilityScorePriorImages)
+	return 100 * (float64(coverage.AltTextCount) + prior*globalCoverage) / (float64(coverage.ImageCount) + prior)
+}
+
+// GetAltTextCoverage returns the share of images across every author that have alt text
+func (pr *PostRegistry) GetAltTextCoverage(ctx context.Context) (*AltTextCoverage, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetAltTextCoverage")
+	defer span.End()
+
+	global, err := pr.queries.GetAltTextCoverage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alt text coverage: %w", err)
+	}
+
+	coverage := NewAltTextCoverage(global.ImageCount, global.AltTextCount)
+	return &coverage, nil
+}
+
+// GetAltTextStats returns alt text coverage across every author, for each cluster, and for the authors who post the most images
+func (pr *PostRegistry) GetAltTextStats(ctx context.Context, topPosterLimit int32) (*AltTextStats, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetAltTextStats")
+	defer span.End()
+
+	rows, err := pr.queries.GetAltTextStats(ctx, topPosterLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alt text stats: %w", err)
+	}
+
+	stats := &AltTextStats{
+		Clusters:        []ClusterAltTextCoverage{},
+		TopImagePosters: []AuthorAltTextCoverage{},
+	}
+
+	// The global row is needed to score the top posters, so collect it first
+	for _, row := range rows {
+		if row.Kind == "global" {
+			stats.AltTextCoverage = NewAltTextCoverage(row.ImageCount, row.AltTextCount)
+			stats.AuthorCount = row.AuthorCount
+		}
+	}
+
+	for _, row := range rows {
+		coverage := NewAltTextCoverage(row.ImageCount, row.AltTextCount)
+		switch row.Kind {
+		case "cluster":
+			stats.Clusters = append(stats.Clusters, ClusterAltTextCoverage{
+				ClusterID:       row.ClusterID.Int32,
+				LookupAlias:     row.LookupAlias.String,
+				Name:            row.Name.String,
+				AltTextCoverage: coverage,
+			})
+		case "author":
+			stats.TopImagePosters = append(stats.TopImagePosters, AuthorAltTextCoverage{
+				AuthorDID:          row.AuthorDid.String,
+				Handle:             row.Handle.String,
+				AccessibilityScore: AccessibilityScore(coverage, stats.Coverage),
+				AltTextCoverage:    coverage,
+			})
+		}
+	}
+
+	// UNION ALL doesn't keep the order of each part, so sort by image count here
+	sort.Slice(stats.Clusters, func(i, j int) bool {
+		return stats.Clusters[i].ImageCount > stats.Clusters[j].ImageCount
+	})
+	sort.Slice(stats.TopImagePosters, func(i, j int) bool {
+		return stats.TopImagePosters[i].ImageCount > stats.TopImagePosters[j].ImageCount
+	})
+
+	return stats, nil
+}
+
+// GetAltTextCoverageForAuthor returns the alt text coverage and accessibility score of an author's images
+func (pr *PostRegistry) GetAltTextCoverageForAuthor(ctx context.Context, authorDID string, globalCoverage float64) (*AuthorAltTextCoverage, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetAltTextCoverageForAuthor")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("author.did", authorDID))
+
+	author, err := pr.queries.GetAltTextCoverageForAuthor(ctx, authorDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alt text coverage for author: %w", err)
+	}
+
+	if author.ImageCount == 0 {
+		return nil, NotFoundError{fmt.Errorf("no images found for author %s", authorDID)}
+	}
+
+	coverage := NewAltTextCoverage(author.ImageCount, author.AltTextCount)
+	return &AuthorAltTextCoverage{
+		AuthorDID:          authorDID,
+		AccessibilityScore: AccessibilityScore(coverage, globalCoverage),
+		AltTextCoverage:    coverage,
+	}, nil
+}
+
+// GetPostsMissingAltText returns a page of the posts with images that have no alt text that come after the (createdAt, postID) cursor, newest first
+func (pr *PostRegistry) GetPostsMissingAltText(ctx context.Context, limit int32, cursorCreatedAt time.Time, cursorPostID string) ([]*PostMissingAltText, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetPostsMissingAltText")
+	defer span.End()
+
+	posts, err := pr.queries.GetPostsMissingAltText(ctx, search_queries.GetPostsMissingAltTextParams{
+		CursorCreatedAt: cursorCreatedAt,
+		CursorPostID:    cursorPostID,
+		Limit:           limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get posts missing alt text: %w", err)
+	}
+
+	if len(posts) == 0 {
+		return nil, NotFoundError{fmt.Errorf("no posts missing alt text found")}
+	}
+
+	retPosts := make([]*PostMissingAltText, len(posts))
+	for i, post := range posts {
+		retPosts[i] = &PostMissingAltText{
+			PostID:     post.PostID,
+			AuthorDID:  post.AuthorDid,
+			CreatedAt:  post.CreatedAt,
+			ImageCount: post.ImageCount,
+		}
+	}
+
+	return retPosts, nil
+}
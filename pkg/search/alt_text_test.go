@@ -0,0 +1,55 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAltTextCoverage(t *testing.T) {
+	assert.Equal(t, AltTextCoverage{ImageCount: 4, AltTextCount: 1, Coverage: 0.25}, NewAltTextCoverage(4, 1))
+	assert.Equal(t, AltTextCoverage{}, NewAltTextCoverage(0, 0))
+}
+
+func TestAccessibilityScore(t *testing.T) {
+	tests := []struct {
+		name           string
+		imageCount     int64
+		altTextCount   int64
+		globalCoverage float64
+		want           float64
+	}{
+		{
+			name:           "no images scores the global coverage",
+			globalCoverage: 0.3,
+			want:           30,
+		},
+		{
+			name:           "few images stay near the global coverage",
+			imageCount:     2,
+			altTextCount:   2,
+			globalCoverage: 0.4,
+			want:           50,
+		},
+		{
+			name:           "many images approach the author's coverage",
+			imageCount:     990,
+			altTextCount:   990,
+			globalCoverage: 0,
+			want:           99,
+		},
+		{
+			name:           "never adding alt text",
+			imageCount:     90,
+			globalCoverage: 0.5,
+			want:           5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coverage := NewAltTextCoverage(tt.imageCount, tt.altTextCount)
+			assert.InDelta(t, tt.want, AccessibilityScore(coverage, tt.globalCoverage), 1e-9)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search/search_queries"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Weights of the signals combined into an AuthorMatch's score
+const (
+	exactHandleMatchBoost = 2.0
+	prefixMatchBoost      = 1.0
+	degreeWeight          = 0.25
+)
+
+type AuthorMatch struct {
+	DID         string  `json:"did"`
+	Handle      string  `json:"handle"`
+	DisplayName *string `json:"display_name"`
+	Degree      int     `json:"degree"`
+	Score       float64 `json:"score"`
+
+	prefixMatch bool
+	similarity  float64
+}
+
+// NormalizeAuthorQuery lowercases a typeahead query and strips the @ users tend to type in front of handles
+func NormalizeAuthorQuery(query string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(query), "@"))
+}
+
+// likePrefix returns a LIKE pattern matching strings that start with s
+func likePrefix(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s) + "%"
+}
+
+// SearchAuthors returns up to limit authors whose handle or display name starts with or is similar to the query
+// The matches are unranked, see RankAuthorMatches
+func (pr *PostRegistry) SearchAuthors(ctx context.Context, query string, limit int32) ([]*AuthorMatch, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:SearchAuthors")
+	defer span.End()
+
+	rows, err := pr.queries.SearchAuthors(ctx, search_queries.SearchAuthorsParams{
+		Prefix: likePrefix(query),
+		Query:  query,
+		Limit:  limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search authors: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("results.count", len(rows)))
+
+	matches := make([]*AuthorMatch, len(rows))
+	for i, row := range rows {
+		matches[i] = &AuthorMatch{
+			DID:         row.Did,
+			Handle:      row.Handle,
+			DisplayName: ptrFromNullString(row.DisplayName),
+			prefixMatch: row.PrefixMatch,
+			similarity:  row.Similarity,
+		}
+	}
+
+	return matches, nil
+}
+
+// RankAuthorMatches scores and sorts matches for a query, best first
+// Exact handle matches come first, then prefix matches, with text similarity and
+// the author's interaction degree in the social graph breaking ties
+func RankAuthorMatches(query string, matches []*AuthorMatch, degree func(did string) int) {
+	for _, match := range matches {
+		match.Degree = degree(match.DID)
+
+		match.Score = match.similarity + degreeWeight*math.Log10(1+float64(match.Degree))
+		if match.prefixMatch {
+			match.Score += prefixMatchBoost
+		}
+		if match.Handle == query || match.Handle == query+".bsky.social" {
+			match.Score += exactHandleMatchBoost
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+}
@@ -0,0 +1,42 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeAuthorQuery(t *testing.T) {
+	assert.Equal(t, "jaz.bsky.social", NormalizeAuthorQuery("  @Jaz.bsky.social "))
+	assert.Equal(t, "jaz", NormalizeAuthorQuery("jaz"))
+}
+
+func TestLikePrefix(t *testing.T) {
+	assert.Equal(t, "jaz%", likePrefix("jaz"))
+	assert.Equal(t, `100\%\_real\\%`, likePrefix(`100%_real\`))
+}
+
+func TestRankAuthorMatches(t *testing.T) {
+	degrees := map[string]int{
+		"did:plc:popular": 5000,
+		"did:plc:quiet":   1,
+	}
+	degree := func(did string) int { return degrees[did] }
+
+	matches := []*AuthorMatch{
+		{DID: "did:plc:fuzzy", Handle: "jzz.bsky.social", similarity: 0.4},
+		{DID: "did:plc:quiet", Handle: "jazzy.bsky.social", prefixMatch: true, similarity: 0.3},
+		{DID: "did:plc:popular", Handle: "jazmine.bsky.social", prefixMatch: true, similarity: 0.3},
+		{DID: "did:plc:exact", Handle: "jaz.bsky.social", prefixMatch: true, similarity: 0.5},
+	}
+
+	RankAuthorMatches("jaz", matches, degree)
+
+	ranked := make([]string, len(matches))
+	for i, match := range matches {
+		ranked[i] = match.DID
+	}
+
+	assert.Equal(t, []string{"did:plc:exact", "did:plc:popular", "did:plc:quiet", "did:plc:fuzzy"}, ranked)
+	assert.Equal(t, 5000, matches[1].Degree)
+}
@@ -279,3 +279,47 @@ func (pr *PostRegistry) GetBlocksPageForTarget(ctx context.Context, targetDID st
 
 	return retBlocks, nil
 }
+
+func (pr *PostRegistry) UpdateAuthorHandle(ctx context.Context, did string, handle string) error {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:UpdateAuthorHandle")
+	defer span.End()
+
+	err := pr.queries.UpdateAuthorHandle(ctx, search_queries.UpdateAuthorHandleParams{
+		Did:    did,
+		Handle: handle,
+	})
+	return err
+}
+
+// AddAuthorTombstone marks an author's account as deleted
+func (pr *PostRegistry) AddAuthorTombstone(ctx context.Context, did string, tombstonedAt time.Time) error {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:AddAuthorTombstone")
+	defer span.End()
+
+	err := pr.queries.AddAuthorTombstone(ctx, search_queries.AddAuthorTombstoneParams{
+		Did:          did,
+		TombstonedAt: tombstonedAt,
+	})
+	return err
+}
+
+// GetTombstonedAuthors returns the subset of the given DIDs whose accounts have been deleted
+func (pr *PostRegistry) GetTombstonedAuthors(ctx context.Context, dids []string) (map[string]struct{}, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetTombstonedAuthors")
+	defer span.End()
+
+	tombstoned, err := pr.queries.GetTombstonedAuthors(ctx, dids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tombstoned authors: %w", err)
+	}
+
+	tombstonedSet := make(map[string]struct{}, len(tombstoned))
+	for _, did := range tombstoned {
+		tombstonedSet[did] = struct{}{}
+	}
+
+	return tombstonedSet, nil
+}
@@ -0,0 +1,667 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search/search_queries"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+type pendingLike struct {
+	postID    string
+	authorDID string
+	count     int64
+}
+
+// BatchWriter buffers author, post, image, facet, language, and like writes to the PostRegistry
+// and flushes them as multi-row upserts when a batch fills up or the flush interval passes
+// Writers block once MaxPending writes are buffered or being flushed, so a slow or unreachable database
+// pushes back on the firehose instead of growing the buffer without bound
+type BatchWriter struct {
+	PostRegistry  *PostRegistry
+	BatchSize     int
+	FlushInterval time.Duration
+	// MaxPending is the number of writes that can be buffered or in a flush before writers block
+	MaxPending int
+	Logger     *zap.SugaredLogger
+
+	lk       sync.Mutex
+	authors  map[string]string
+	posts    []*Post
+	images   []*Image
+	mentions []*PostMention
+	links    []*PostLink
+	tags     []*PostTag
+	langs    []*PostLanguage
+	likes    map[string]*pendingLike
+	pending  int
+	closed   bool
+	// flushing is the number of writes taken from the buffer by the flush in progress
+	flushing int
+	// freed is closed and replaced whenever a flush finishes to wake writers waiting for room
+	freed chan struct{}
+	// flushReady asks Run to flush once a batch fills up
+	flushReady chan struct{}
+
+	// flushLk serializes flushes so rows are written in the order they were added
+	flushLk sync.Mutex
+}
+
+func NewBatchWriter(postRegistry *PostRegistry, batchSize int, flushInterval time.Duration, logger *zap.SugaredLogger) *BatchWriter {
+	return &BatchWriter{
+		PostRegistry:  postRegistry,
+		BatchSize:     batchSize,
+		FlushInterval: flushInterval,
+		MaxPending:    10 * batchSize,
+		Logger:        logger,
+
+		authors:    map[string]string{},
+		likes:      map[string]*pendingLike{},
+		freed:      make(chan struct{}),
+		flushReady: make(chan struct{}, 1),
+	}
+}
+
+// Run flushes the buffered writes when a batch fills up or on the flush interval until the context is cancelled
+// After a failed flush it waits for the flush interval before trying again, a final flush is made on the way out
+func (bw *BatchWriter) Run(ctx context.Context) {
+	ticker := time.NewTicker(bw.FlushInterval)
+	defer ticker.Stop()
+
+	failing := false
+	for {
+		flushReady := bw.flushReady
+		if failing {
+			flushReady = nil
+		}
+
+		select {
+		case <-ticker.C:
+			err := bw.Flush(ctx)
+			if err != nil {
+				bw.Logger.Errorf("failed to flush batch: %+v", err)
+			}
+			failing = err != nil
+		case <-flushReady:
+			err := bw.Flush(ctx)
+			if err != nil {
+				bw.Logger.Errorf("failed to flush batch: %+v", err)
+			}
+			failing = err != nil
+		case <-ctx.Done():
+			err := bw.Close(context.Background())
+			if err != nil {
+				bw.Logger.Errorf("failed to flush batch on shutdown: %+v", err)
+			}
+			return
+		}
+	}
+}
+
+// Close flushes the buffered writes, writes made after Close are written immediately
+func (bw *BatchWriter) Close(ctx context.Context) error {
+	bw.lk.Lock()
+	bw.closed = true
+	// Wake writers waiting for room so they write directly
+	bw.signalFreed()
+	bw.lk.Unlock()
+
+	return bw.Flush(ctx)
+}
+
+// Pending returns the number of buffered writes waiting to be flushed
+func (bw *BatchWriter) Pending() int {
+	bw.lk.Lock()
+	defer bw.lk.Unlock()
+	return bw.pending
+}
+
+// add buffers a write with buffer, which returns false if the write was merged into one already buffered
+// It blocks while the buffer is full and only returns an error if the write itself was dropped
+// Once the writer is closed the write is made immediately with write instead
+func (bw *BatchWriter) add(ctx context.Context, buffer func() bool, write func(context.Context) error) error {
+	bw.lk.Lock()
+	for !bw.closed && bw.pending+bw.flushing >= bw.MaxPending {
+		freed := bw.freed
+		bw.lk.Unlock()
+
+		batchWriterBlockedWritesCounter.Inc()
+		select {
+		case <-freed:
+		case <-ctx.Done():
+			return fmt.Errorf("batch writer is full: %w", ctx.Err())
+		}
+
+		bw.lk.Lock()
+	}
+
+	if bw.closed {
+		bw.lk.Unlock()
+		return write(ctx)
+	}
+
+	if buffer() {
+		bw.pending++
+		batchWriterPendingGauge.Set(float64(bw.pending))
+	}
+	full := bw.pending >= bw.BatchSize
+	bw.lk.Unlock()
+
+	if full {
+		select {
+		case bw.flushReady <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// signalFreed wakes the writers waiting for room, it's called with the lock held
+func (bw *BatchWriter) signalFreed() {
+	close(bw.freed)
+	bw.freed = make(chan struct{})
+}
+
+// AddAuthor buffers an author upsert, the latest handle for a DID wins
+func (bw *BatchWriter) AddAuthor(ctx context.Context, author *Author) error {
+	return bw.add(ctx, func() bool {
+		_, buffered := bw.authors[author.DID]
+		bw.authors[author.DID] = author.Handle
+		return !buffered
+	}, func(ctx context.Context) error {
+		_, err := writeRows(ctx, "authors", []*Author{author}, bw.writeAuthors)
+		return err
+	})
+}
+
+// AddPost buffers a post insert, posts that already exist are skipped when the batch is flushed
+func (bw *BatchWriter) AddPost(ctx context.Context, post *Post) error {
+	return bw.add(ctx, func() bool {
+		bw.posts = append(bw.posts, post)
+		return true
+	}, func(ctx context.Context) error {
+		_, err := writeRows(ctx, "posts", []*Post{post}, bw.writePosts)
+		return err
+	})
+}
+
+// AddImage buffers an image insert, only images that haven't been through CV yet can be batched
+func (bw *BatchWriter) AddImage(ctx context.Context, image *Image) error {
+	if image.CVCompleted {
+		return fmt.Errorf("can't batch image %s with CV results", image.CID)
+	}
+
+	return bw.add(ctx, func() bool {
+		bw.images = append(bw.images, image)
+		return true
+	}, func(ctx context.Context) error {
+		_, err := writeRows(ctx, "images", []*Image{image}, bw.writeImages)
+		return err
+	})
+}
+
+// AddPostMention buffers a mention insert, mentions that already exist are skipped when the batch is flushed
+func (bw *BatchWriter) AddPostMention(ctx context.Context, mention *PostMention) error {
+	return bw.add(ctx, func() bool {
+		bw.mentions = append(bw.mentions, mention)
+		return true
+	}, func(ctx context.Context) error {
+		_, err := writeRows(ctx, "post_mentions", []*PostMention{mention}, bw.writeMentions)
+		return err
+	})
+}
+
+// AddPostLink buffers a link insert, links that already exist are skipped when the batch is flushed
+func (bw *BatchWriter) AddPostLink(ctx context.Context, link *PostLink) error {
+	return bw.add(ctx, func() bool {
+		bw.links = append(bw.links, link)
+		return true
+	}, func(ctx context.Context) error {
+		_, err := writeRows(ctx, "post_links", []*PostLink{link}, bw.writeLinks)
+		return err
+	})
+}
+
+// AddPostTag buffers a tag insert, tags that already exist are skipped when the batch is flushed
+func (bw *BatchWriter) AddPostTag(ctx context.Context, tag *PostTag) error {
+	return bw.add(ctx, func() bool {
+		bw.tags = append(bw.tags, tag)
+		return true
+	}, func(ctx context.Context) error {
+		_, err := writeRows(ctx, "post_tags", []*PostTag{tag}, bw.writeTags)
+		return err
+	})
+}
+
+// AddPostLanguage buffers the declared languages of a post, posts without declared languages are skipped when the batch is flushed
+func (bw *BatchWriter) AddPostLanguage(ctx context.Context, lang *PostLanguage) error {
+	return bw.add(ctx, func() bool {
+		bw.langs = append(bw.langs, lang)
+		return true
+	}, func(ctx context.Context) error {
+		_, err := writeRows(ctx, "post_languages", []*PostLanguage{lang}, bw.writeLanguages)
+		return err
+	})
+}
+
+// AddLikeToPost buffers a like, likes of the same post are summed into one increment
+func (bw *BatchWriter) AddLikeToPost(ctx context.Context, postID string, authorDID string) error {
+	return bw.add(ctx, func() bool {
+		if like, ok := bw.likes[postID]; ok {
+			like.count++
+			return false
+		}
+		bw.likes[postID] = &pendingLike{postID: postID, authorDID: authorDID, count: 1}
+		return true
+	}, func(ctx context.Context) error {
+		_, err := writeRows(ctx, "likes", []*pendingLike{{postID: postID, authorDID: authorDID, count: 1}}, bw.writeLikes)
+		return err
+	})
+}
+
+// Flush writes all buffered writes to the PostRegistry
+// Authors are written before posts and posts before images to satisfy foreign keys
+// Rows the database rejects are dropped and counted in the failed rows metric without taking the rest of their batch with them
+// If the database can't be reached, everything that wasn't written goes back in the buffer for the next flush
+func (bw *BatchWriter) Flush(ctx context.Context) error {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "BatchWriter:Flush")
+	defer span.End()
+
+	bw.flushLk.Lock()
+	defer bw.flushLk.Unlock()
+
+	bw.lk.Lock()
+	b := &batch{
+		authors:  make([]*Author, 0, len(bw.authors)),
+		posts:    bw.posts,
+		images:   bw.images,
+		mentions: bw.mentions,
+		links:    bw.links,
+		tags:     bw.tags,
+		langs:    bw.langs,
+		likes:    make([]*pendingLike, 0, len(bw.likes)),
+	}
+	for did, handle := range bw.authors {
+		b.authors = append(b.authors, &Author{DID: did, Handle: handle})
+	}
+	for _, like := range bw.likes {
+		b.likes = append(b.likes, like)
+	}
+	bw.authors = map[string]string{}
+	bw.posts = nil
+	bw.images = nil
+	bw.mentions = nil
+	bw.links = nil
+	bw.tags = nil
+	bw.langs = nil
+	bw.likes = map[string]*pendingLike{}
+	bw.flushing = bw.pending
+	bw.pending = 0
+	batchWriterPendingGauge.Set(0)
+	bw.lk.Unlock()
+
+	span.SetAttributes(
+		attribute.Int("batch.authors", len(b.authors)),
+		attribute.Int("batch.posts", len(b.posts)),
+		attribute.Int("batch.images", len(b.images)),
+		attribute.Int("batch.mentions", len(b.mentions)),
+		attribute.Int("batch.links", len(b.links)),
+		attribute.Int("batch.tags", len(b.tags)),
+		attribute.Int("batch.langs", len(b.langs)),
+		attribute.Int("batch.likes", len(b.likes)),
+	)
+
+	start := time.Now()
+	defer func() {
+		batchWriterFlushDurationHistogram.Observe(time.Since(start).Seconds())
+	}()
+
+	fs := &flushState{}
+	retry := &batch{}
+	retry.authors = flushTable(ctx, fs, "authors", b.authors, bw.writeAuthors)
+	retry.posts = flushTable(ctx, fs, "posts", b.posts, bw.writePosts)
+	retry.images = flushTable(ctx, fs, "images", b.images, bw.writeImages)
+	retry.mentions = flushTable(ctx, fs, "post_mentions", b.mentions, bw.writeMentions)
+	retry.links = flushTable(ctx, fs, "post_links", b.links, bw.writeLinks)
+	retry.tags = flushTable(ctx, fs, "post_tags", b.tags, bw.writeTags)
+	retry.langs = flushTable(ctx, fs, "post_languages", b.langs, bw.writeLanguages)
+	retry.likes = flushTable(ctx, fs, "likes", b.likes, bw.writeLikes)
+
+	if retry.size() > 0 {
+		span.SetAttributes(attribute.Int("batch.retried", retry.size()))
+		bw.requeue(retry)
+	}
+
+	bw.lk.Lock()
+	bw.flushing = 0
+	bw.signalFreed()
+	bw.lk.Unlock()
+
+	err := errors.Join(fs.errs...)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return err
+}
+
+// batch holds the writes taken from the buffer by a flush
+type batch struct {
+	authors  []*Author
+	posts    []*Post
+	images   []*Image
+	mentions []*PostMention
+	links    []*PostLink
+	tags     []*PostTag
+	langs    []*PostLanguage
+	likes    []*pendingLike
+}
+
+func (b *batch) size() int {
+	return len(b.authors) + len(b.posts) + len(b.images) + len(b.mentions) + len(b.links) + len(b.tags) + len(b.langs) + len(b.likes)
+}
+
+// requeue puts writes that couldn't be flushed back in front of the ones buffered since
+// Handles buffered since win over requeued ones and requeued likes are added to any buffered since
+func (bw *BatchWriter) requeue(b *batch) {
+	bw.lk.Lock()
+	defer bw.lk.Unlock()
+
+	for _, author := range b.authors {
+		if _, ok := bw.authors[author.DID]; !ok {
+			bw.authors[author.DID] = author.Handle
+		}
+	}
+	for _, like := range b.likes {
+		if pending, ok := bw.likes[like.postID]; ok {
+			pending.count += like.count
+			continue
+		}
+		bw.likes[like.postID] = like
+	}
+
+	bw.posts = append(b.posts, bw.posts...)
+	bw.images = append(b.images, bw.images...)
+	bw.mentions = append(b.mentions, bw.mentions...)
+	bw.links = append(b.links, bw.links...)
+	bw.tags = append(b.tags, bw.tags...)
+	bw.langs = append(b.langs, bw.langs...)
+
+	bw.pending = len(bw.authors) + len(bw.posts) + len(bw.images) + len(bw.mentions) + len(bw.links) + len(bw.tags) + len(bw.langs) + len(bw.likes)
+	batchWriterPendingGauge.Set(float64(bw.pending))
+}
+
+// flushState tracks a flush across tables
+type flushState struct {
+	// Once a table has to be retried the tables after it are retried whole so they don't fail their foreign keys
+	retrying bool
+	errs     []error
+}
+
+// flushTable writes a table's rows and returns the ones to retry on the next flush
+func flushTable[T any](ctx context.Context, fs *flushState, table string, rows []T, write func(context.Context, []T) error) []T {
+	if fs.retrying {
+		return rows
+	}
+
+	retry, err := writeRows(ctx, table, rows, write)
+	if err != nil {
+		fs.errs = append(fs.errs, err)
+	}
+	fs.retrying = len(retry) > 0
+
+	return retry
+}
+
+// writeRows writes rows to a table with one multi-row upsert
+// If the database rejects the upsert, each row is written on its own so only the rows it rejects are dropped
+// The rows that weren't written because the database couldn't be reached are returned to be retried
+func writeRows[T any](ctx context.Context, table string, rows []T, write func(context.Context, []T) error) ([]T, error) {
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	err := write(ctx, rows)
+	if err == nil {
+		batchWriterFlushedRowsCounter.WithLabelValues(table).Add(float64(len(rows)))
+		return nil, nil
+	}
+
+	if isTransientError(ctx, err) {
+		return rows, fmt.Errorf("failed to write %d %s, retrying on the next flush: %w", len(rows), table, err)
+	}
+
+	failed := 0
+	var rowErr error
+	for i := range rows {
+		err := write(ctx, rows[i:i+1])
+		if err == nil {
+			batchWriterFlushedRowsCounter.WithLabelValues(table).Inc()
+			continue
+		}
+
+		if isTransientError(ctx, err) {
+			batchWriterFailedRowsCounter.WithLabelValues(table).Add(float64(failed))
+			return rows[i:], fmt.Errorf("failed to write %d %s, retrying on the next flush: %w", len(rows)-i, table, err)
+		}
+
+		failed++
+		if rowErr == nil {
+			rowErr = err
+		}
+	}
+
+	batchWriterFailedRowsCounter.WithLabelValues(table).Add(float64(failed))
+	return nil, fmt.Errorf("dropped %d of %d %s rejected by the database: %w", failed, len(rows), table, rowErr)
+}
+
+// isTransientError reports whether a write failed because the database couldn't be reached rather than because it rejected the rows
+func isTransientError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		// Errors that don't come from Postgres itself are from the connection
+		return true
+	}
+
+	// Connection exceptions, transaction rollbacks, insufficient resources, operator intervention, and system errors
+	switch pqErr.Code.Class() {
+	case "08", "40", "53", "57", "58":
+		return true
+	}
+
+	return false
+}
+
+func (bw *BatchWriter) writeAuthors(ctx context.Context, authors []*Author) error {
+	params := search_queries.AddAuthorsParams{
+		Dids:    make([]string, len(authors)),
+		Handles: make([]string, len(authors)),
+	}
+
+	for i, author := range authors {
+		params.Dids[i] = author.DID
+		params.Handles[i] = author.Handle
+	}
+
+	return bw.PostRegistry.queries.AddAuthors(ctx, params)
+}
+
+func (bw *BatchWriter) writePosts(ctx context.Context, posts []*Post) error {
+	params := search_queries.AddPostsParams{
+		Ids:                  make([]string, len(posts)),
+		Texts:                make([]string, len(posts)),
+		ParentPostIds:        make([]string, len(posts)),
+		RootPostIds:          make([]string, len(posts)),
+		AuthorDids:           make([]string, len(posts)),
+		CreatedAts:           make([]time.Time, len(posts)),
+		HasEmbeddedMedias:    make([]bool, len(posts)),
+		ParentRelationships:  make([]string, len(posts)),
+		Sentiments:           make([]string, len(posts)),
+		SentimentConfidences: make([]string, len(posts)),
+	}
+
+	// Nullable columns are sent as empty strings and stored as NULL
+	for i, post := range posts {
+		params.Ids[i] = post.ID
+		params.Texts[i] = post.Text
+		params.AuthorDids[i] = post.AuthorDID
+		params.CreatedAts[i] = post.CreatedAt
+		params.HasEmbeddedMedias[i] = post.HasEmbeddedMedia
+
+		if post.ParentPostID != nil {
+			params.ParentPostIds[i] = *post.ParentPostID
+		}
+		if post.RootPostID != nil {
+			params.RootPostIds[i] = *post.RootPostID
+		}
+		if post.ParentRelationship != nil {
+			params.ParentRelationships[i] = *post.ParentRelationship
+		}
+		if post.Sentiment != nil {
+			params.Sentiments[i] = *post.Sentiment
+		}
+		if post.SentimentConfidence != nil {
+			params.SentimentConfidences[i] = strconv.FormatFloat(*post.SentimentConfidence, 'g', -1, 64)
+		}
+	}
+
+	return bw.PostRegistry.queries.AddPosts(ctx, params)
+}
+
+func (bw *BatchWriter) writeImages(ctx context.Context, images []*Image) error {
+	params := search_queries.AddImagesParams{
+		Cids:          make([]string, len(images)),
+		PostIds:       make([]string, len(images)),
+		AuthorDids:    make([]string, len(images)),
+		AltTexts:      make([]string, len(images)),
+		HasAltTexts:   make([]bool, len(images)),
+		MimeTypes:     make([]string, len(images)),
+		FullsizeUrls:  make([]string, len(images)),
+		ThumbnailUrls: make([]string, len(images)),
+		CreatedAts:    make([]time.Time, len(images)),
+	}
+
+	for i, image := range images {
+		params.Cids[i] = image.CID
+		params.PostIds[i] = image.PostID
+		params.AuthorDids[i] = image.AuthorDID
+		params.MimeTypes[i] = image.MimeType
+		params.FullsizeUrls[i] = image.FullsizeURL
+		params.ThumbnailUrls[i] = image.ThumbnailURL
+		params.CreatedAts[i] = image.CreatedAt
+
+		// Empty alt text is kept distinct from missing alt text
+		if image.AltText != nil {
+			params.AltTexts[i] = *image.AltText
+			params.HasAltTexts[i] = true
+		}
+	}
+
+	return bw.PostRegistry.queries.AddImages(ctx, params)
+}
+
+func (bw *BatchWriter) writeMentions(ctx context.Context, mentions []*PostMention) error {
+	params := search_queries.AddPostMentionsParams{
+		PostIds:       make([]string, len(mentions)),
+		AuthorDids:    make([]string, len(mentions)),
+		MentionedDids: make([]string, len(mentions)),
+		CreatedAts:    make([]time.Time, len(mentions)),
+	}
+
+	for i, mention := range mentions {
+		params.PostIds[i] = mention.PostID
+		params.AuthorDids[i] = mention.AuthorDID
+		params.MentionedDids[i] = mention.MentionedDID
+		params.CreatedAts[i] = mention.CreatedAt
+	}
+
+	return bw.PostRegistry.queries.AddPostMentions(ctx, params)
+}
+
+func (bw *BatchWriter) writeLinks(ctx context.Context, links []*PostLink) error {
+	params := search_queries.AddPostLinksParams{
+		PostIds:    make([]string, len(links)),
+		AuthorDids: make([]string, len(links)),
+		Urls:       make([]string, len(links)),
+		Domains:    make([]string, len(links)),
+		CreatedAts: make([]time.Time, len(links)),
+	}
+
+	for i, link := range links {
+		params.PostIds[i] = link.PostID
+		params.AuthorDids[i] = link.AuthorDID
+		params.Urls[i] = link.URL
+		params.Domains[i] = link.Domain
+		params.CreatedAts[i] = link.CreatedAt
+	}
+
+	return bw.PostRegistry.queries.AddPostLinks(ctx, params)
+}
+
+func (bw *BatchWriter) writeTags(ctx context.Context, tags []*PostTag) error {
+	params := search_queries.AddPostTagsParams{
+		PostIds:    make([]string, len(tags)),
+		AuthorDids: make([]string, len(tags)),
+		Tags:       make([]string, len(tags)),
+		CreatedAts: make([]time.Time, len(tags)),
+	}
+
+	for i, tag := range tags {
+		params.PostIds[i] = tag.PostID
+		params.AuthorDids[i] = tag.AuthorDID
+		params.Tags[i] = tag.Tag
+		params.CreatedAts[i] = tag.CreatedAt
+	}
+
+	return bw.PostRegistry.queries.AddPostTags(ctx, params)
+}
+
+func (bw *BatchWriter) writeLanguages(ctx context.Context, langs []*PostLanguage) error {
+	params := search_queries.AddPostDeclaredLangsParams{
+		PostIds:       make([]string, len(langs)),
+		AuthorDids:    make([]string, len(langs)),
+		DeclaredLangs: make([]string, len(langs)),
+		CreatedAts:    make([]time.Time, len(langs)),
+	}
+
+	// Each post's languages are sent comma-separated since Postgres can't unnest ragged arrays
+	for i, lang := range langs {
+		params.PostIds[i] = lang.PostID
+		params.AuthorDids[i] = lang.AuthorDID
+		params.DeclaredLangs[i] = strings.Join(lang.DeclaredLangs, ",")
+		params.CreatedAts[i] = lang.CreatedAt
+	}
+
+	return bw.PostRegistry.queries.AddPostDeclaredLangs(ctx, params)
+}
+
+func (bw *BatchWriter) writeLikes(ctx context.Context, likes []*pendingLike) error {
+	params := search_queries.AddLikesToPostsParams{
+		PostIds:    make([]string, len(likes)),
+		AuthorDids: make([]string, len(likes)),
+		LikeCounts: make([]int64, len(likes)),
+	}
+
+	for i, like := range likes {
+		params.PostIds[i] = like.postID
+		params.AuthorDids[i] = like.authorDID
+		params.LikeCounts[i] = like.count
+	}
+
+	return bw.PostRegistry.queries.AddLikesToPosts(ctx, params)
+}
@@ -0,0 +1,139 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestBatchWriterCoalescesWrites(t *testing.T) {
+	ctx := context.Background()
+
+	// The batch is never filled so nothing is written to the (missing) PostRegistry
+	bw := NewBatchWriter(nil, 100, time.Minute, zap.NewNop().Sugar())
+
+	assert.NoError(t, bw.AddAuthor(ctx, &Author{DID: "did:plc:a", Handle: "old.bsky.social"}))
+	assert.NoError(t, bw.AddAuthor(ctx, &Author{DID: "did:plc:a", Handle: "new.bsky.social"}))
+	assert.NoError(t, bw.AddAuthor(ctx, &Author{DID: "did:plc:b", Handle: "b.bsky.social"}))
+
+	assert.NoError(t, bw.AddLikeToPost(ctx, "post1", "did:plc:a"))
+	assert.NoError(t, bw.AddLikeToPost(ctx, "post1", "did:plc:a"))
+	assert.NoError(t, bw.AddLikeToPost(ctx, "post2", "did:plc:b"))
+
+	assert.NoError(t, bw.AddPost(ctx, &Post{ID: "post3", AuthorDID: "did:plc:a"}))
+
+	assert.Equal(t, 5, bw.Pending())
+	assert.Equal(t, "new.bsky.social", bw.authors["did:plc:a"])
+	assert.Equal(t, int64(2), bw.likes["post1"].count)
+	assert.Equal(t, int64(1), bw.likes["post2"].count)
+}
+
+func TestBatchWriterRejectsImagesWithCVResults(t *testing.T) {
+	bw := NewBatchWriter(nil, 100, time.Minute, zap.NewNop().Sugar())
+
+	err := bw.AddImage(context.Background(), &Image{CID: "cid", PostID: "post", CVCompleted: true})
+	assert.Error(t, err)
+	assert.Equal(t, 0, bw.Pending())
+}
+
+func TestWriteRowsDropsOnlyRejectedRows(t *testing.T) {
+	ctx := context.Background()
+	rejected := &pq.Error{Code: "23503"}
+
+	writes := 0
+	retry, err := writeRows(ctx, "posts", []string{"a", "bad", "c"}, func(ctx context.Context, rows []string) error {
+		writes++
+		for _, row := range rows {
+			if row == "bad" {
+				return rejected
+			}
+		}
+		return nil
+	})
+
+	assert.ErrorIs(t, err, rejected)
+	assert.Empty(t, retry)
+	// One multi-row write and then one write per row
+	assert.Equal(t, 4, writes)
+}
+
+func TestWriteRowsRetriesWhenDatabaseIsUnreachable(t *testing.T) {
+	ctx := context.Background()
+	unreachable := errors.New("dial tcp: connection refused")
+
+	retry, err := writeRows(ctx, "posts", []string{"a", "b"}, func(ctx context.Context, rows []string) error {
+		return unreachable
+	})
+	assert.ErrorIs(t, err, unreachable)
+	assert.Equal(t, []string{"a", "b"}, retry)
+
+	// The database going away part way through the per-row writes retries the rows that weren't written
+	retry, err = writeRows(ctx, "posts", []string{"a", "bad", "c"}, func(ctx context.Context, rows []string) error {
+		if len(rows) > 1 || rows[0] == "bad" {
+			return &pq.Error{Code: "23505"}
+		}
+		if rows[0] == "c" {
+			return &pq.Error{Code: "57P01"}
+		}
+		return nil
+	})
+	assert.Error(t, err)
+	assert.Equal(t, []string{"c"}, retry)
+}
+
+func TestBatchWriterRequeue(t *testing.T) {
+	ctx := context.Background()
+	bw := NewBatchWriter(nil, 100, time.Minute, zap.NewNop().Sugar())
+
+	assert.NoError(t, bw.AddAuthor(ctx, &Author{DID: "did:plc:a", Handle: "new.bsky.social"}))
+	assert.NoError(t, bw.AddPost(ctx, &Post{ID: "post2"}))
+	assert.NoError(t, bw.AddLikeToPost(ctx, "post1", "did:plc:a"))
+
+	bw.requeue(&batch{
+		authors: []*Author{{DID: "did:plc:a", Handle: "old.bsky.social"}, {DID: "did:plc:b", Handle: "b.bsky.social"}},
+		posts:   []*Post{{ID: "post1"}},
+		likes:   []*pendingLike{{postID: "post1", authorDID: "did:plc:a", count: 2}},
+	})
+
+	assert.Equal(t, 5, bw.Pending())
+	assert.Equal(t, "new.bsky.social", bw.authors["did:plc:a"])
+	assert.Equal(t, "post1", bw.posts[0].ID)
+	assert.Equal(t, "post2", bw.posts[1].ID)
+	assert.Equal(t, int64(3), bw.likes["post1"].count)
+}
+
+func TestBatchWriterBlocksWhenFull(t *testing.T) {
+	ctx := context.Background()
+	bw := NewBatchWriter(nil, 100, time.Minute, zap.NewNop().Sugar())
+	bw.MaxPending = 2
+
+	assert.NoError(t, bw.AddPost(ctx, &Post{ID: "post1"}))
+	assert.NoError(t, bw.AddPost(ctx, &Post{ID: "post2"}))
+
+	// The buffer is full so the write waits until its context runs out and isn't buffered
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, bw.AddPost(timeoutCtx, &Post{ID: "post3"}), context.DeadlineExceeded)
+	assert.Equal(t, 2, bw.Pending())
+
+	// Once a flush frees up room the waiting write goes through
+	added := make(chan error)
+	go func() {
+		added <- bw.AddPost(ctx, &Post{ID: "post3"})
+	}()
+
+	bw.lk.Lock()
+	bw.posts = bw.posts[1:]
+	bw.pending--
+	bw.signalFreed()
+	bw.lk.Unlock()
+
+	assert.NoError(t, <-added)
+	assert.Equal(t, 2, bw.Pending())
+	assert.Equal(t, "post3", bw.posts[1].ID)
+}
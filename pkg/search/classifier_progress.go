@@ -0,0 +1,128 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search/search_queries"
+	"go.opentelemetry.io/otel"
+)
+
+// ClassifierProgress is how far a classifier has gotten through the posts in the order they were inserted
+type ClassifierProgress struct {
+	Classifier       string    `json:"classifier"`
+	CursorInsertedAt time.Time `json:"cursor_inserted_at"`
+	CursorPostID     string    `json:"cursor_post_id"`
+	ProcessedCount   int64     `json:"processed_count"`
+	LabeledCount     int64     `json:"labeled_count"`
+	FailedCount      int64     `json:"failed_count"`
+	LastError        *string   `json:"last_error"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+func (pr *PostRegistry) GetClassifierProgress(ctx context.Context, classifier string) (*ClassifierProgress, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetClassifierProgress")
+	defer span.End()
+
+	progress, err := pr.queries.GetClassifierProgress(ctx, classifier)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, NotFoundError{fmt.Errorf("classifier progress not found")}
+		}
+		return nil, err
+	}
+
+	return &ClassifierProgress{
+		Classifier:       progress.Classifier,
+		CursorInsertedAt: progress.CursorInsertedAt,
+		CursorPostID:     progress.CursorPostID,
+		ProcessedCount:   progress.ProcessedCount,
+		LabeledCount:     progress.LabeledCount,
+		FailedCount:      progress.FailedCount,
+		LastError:        ptrFromNullString(progress.LastError),
+		UpdatedAt:        progress.UpdatedAt,
+	}, nil
+}
+
+// UpdateClassifierProgress moves a classifier's cursor to the given post and adds to its counts
+// A nil lastErr keeps the previously recorded error
+func (pr *PostRegistry) UpdateClassifierProgress(
+	ctx context.Context,
+	classifier string,
+	cursorInsertedAt time.Time,
+	cursorPostID string,
+	processed, labeled, failed int64,
+	lastErr error,
+) error {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:UpdateClassifierProgress")
+	defer span.End()
+
+	lastError := sql.NullString{}
+	if lastErr != nil {
+		lastError = sql.NullString{String: lastErr.Error(), Valid: true}
+	}
+
+	return pr.queries.UpdateClassifierProgress(ctx, search_queries.UpdateClassifierProgressParams{
+		Classifier:       classifier,
+		CursorInsertedAt: cursorInsertedAt,
+		CursorPostID:     cursorPostID,
+		Processed:        processed,
+		Labeled:          labeled,
+		Failed:           failed,
+		LastError:        lastError,
+	})
+}
+
+// GetPostsAfterCursor returns up to limit posts inserted after the cursor, in the order they were inserted
+// Posts inserted after insertedBefore are left out so writes that commit late aren't skipped by the cursor
+func (pr *PostRegistry) GetPostsAfterCursor(
+	ctx context.Context,
+	cursorInsertedAt time.Time,
+	cursorPostID string,
+	insertedBefore time.Time,
+	limit int32,
+) ([]*Post, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetPostsAfterCursor")
+	defer span.End()
+
+	posts, err := pr.queries.GetPostsAfterCursor(ctx, search_queries.GetPostsAfterCursorParams{
+		CursorInsertedAt: cursorInsertedAt,
+		CursorPostID:     cursorPostID,
+		InsertedBefore:   insertedBefore,
+		Limit:            limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get posts after cursor: %w", err)
+	}
+
+	retPosts := make([]*Post, len(posts))
+	for i, post := range posts {
+		retPosts[i] = &Post{
+			ID:                 post.ID,
+			Text:               post.Text,
+			ParentPostID:       ptrFromNullString(post.ParentPostID),
+			RootPostID:         ptrFromNullString(post.RootPostID),
+			AuthorDID:          post.AuthorDid,
+			CreatedAt:          post.CreatedAt,
+			HasEmbeddedMedia:   post.HasEmbeddedMedia,
+			ParentRelationship: ptrFromNullString(post.ParentRelationship),
+			Sentiment:          ptrFromNullString(post.Sentiment),
+		}
+		if post.SentimentConfidence.Valid {
+			retPosts[i].SentimentConfidence = &posts[i].SentimentConfidence.Float64
+		}
+		if post.IndexedAt.Valid {
+			retPosts[i].IndexedAt = &posts[i].IndexedAt.Time
+		}
+		if post.InsertedAt.Valid {
+			retPosts[i].InsertedAt = &posts[i].InsertedAt.Time
+		}
+	}
+
+	return retPosts, nil
+}
@@ -30,6 +30,7 @@ type ClusterManager struct {
 	Clusters         map[string]*Cluster
 	HandleClusterMap map[string]*HandleClusterMapEntry
 	DIDClusterMap    map[string]*DIDClusterMapEntry
+	DIDDegreeMap     map[string]int
 }
 
 type GraphData struct {
@@ -48,6 +49,10 @@ type GraphData struct {
 			Community int    `json:"community"`
 		} `json:"attributes"`
 	} `json:"nodes"`
+	Edges []struct {
+		Source string `json:"source"`
+		Target string `json:"target"`
+	} `json:"edges"`
 }
 
 func NewClusterManager(graphJSONUrl string) (*ClusterManager, error) {
@@ -56,6 +61,7 @@ func NewClusterManager(graphJSONUrl string) (*ClusterManager, error) {
 		Clusters:         make(map[string]*Cluster),
 		HandleClusterMap: make(map[string]*HandleClusterMapEntry),
 		DIDClusterMap:    make(map[string]*DIDClusterMapEntry),
+		DIDDegreeMap:     make(map[string]int),
 	}
 
 	log.Printf("getting graph data from %s", graphJSONUrl)
@@ -111,6 +117,23 @@ func NewClusterManager(graphJSONUrl string) (*ClusterManager, error) {
 
 	log.Printf("found %d users in clusters", len(cm.HandleClusterMap))
 
+	// Count the interactions each user has in the graph, edges reference nodes by key
+	nodeDIDs := make(map[string]string, len(graphData.Nodes))
+	for _, node := range graphData.Nodes {
+		nodeDIDs[node.Key] = node.Attributes.DID
+	}
+
+	for _, edge := range graphData.Edges {
+		if did, exists := nodeDIDs[edge.Source]; exists {
+			cm.DIDDegreeMap[did]++
+		}
+		if did, exists := nodeDIDs[edge.Target]; exists {
+			cm.DIDDegreeMap[did]++
+		}
+	}
+
+	log.Printf("found %d edges", len(graphData.Edges))
+
 	return cm, nil
 }
 
@@ -131,3 +154,8 @@ func (cm *ClusterManager) GetClusterForDID(ctx context.Context, userDID string)
 
 	return cm.Clusters[mapEntry.ClusterID], nil
 }
+
+// GetDegreeForDID returns the number of interaction edges a user has in the graph, 0 if the user isn't in it
+func (cm *ClusterManager) GetDegreeForDID(userDID string) int {
+	return cm.DIDDegreeMap[userDID]
+}
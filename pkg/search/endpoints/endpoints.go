@@ -6,17 +6,22 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	comatproto "github.com/bluesky-social/indigo/api/atproto"
 	"github.com/bluesky-social/indigo/api/bsky"
 	"github.com/bluesky-social/indigo/xrpc"
+	"github.com/ericvolp12/bsky-experiments/pkg/language"
 	"github.com/ericvolp12/bsky-experiments/pkg/layout"
+	objectdetection "github.com/ericvolp12/bsky-experiments/pkg/object-detection"
 	"github.com/ericvolp12/bsky-experiments/pkg/search"
 	"github.com/ericvolp12/bsky-experiments/pkg/search/clusters"
 
 	"github.com/ericvolp12/bsky-experiments/pkg/search/search_queries"
+	"github.com/ericvolp12/bsky-experiments/pkg/search/textsearch"
 	"github.com/ericvolp12/bsky-experiments/pkg/usercount"
 	"github.com/gin-gonic/gin"
 	lru "github.com/hashicorp/golang-lru/arc/v2"
@@ -25,6 +30,9 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 )
 
+// authorSearchCandidateFactor is how many more authors than requested are fetched for ranking
+const authorSearchCandidateFactor = 5
+
 type ThreadViewCacheEntry struct {
 	ThreadView []search.PostView
 	Expiration time.Time
@@ -50,11 +58,19 @@ type AuthorStatsResponse struct {
 	Brackets        []search.Bracket                  `json:"brackets"`
 	UpdatedAt       time.Time                         `json:"updated_at"`
 	TopPosters      []search_queries.GetTopPostersRow `json:"top_posters"`
+	AltText         *search.AltTextStats              `json:"alt_text"`
+}
+
+type AuthorProfileResponse struct {
+	DID     string                `json:"did"`
+	Handle  string                `json:"handle"`
+	Profile *search.AuthorProfile `json:"profile"`
 }
 
 type API struct {
 	PostRegistry *search.PostRegistry
 	UserCount    *usercount.UserCount
+	Searcher     textsearch.Searcher
 
 	ClusterManager *clusters.ClusterManager
 
@@ -77,6 +93,7 @@ func NewAPI(
 	threadViewCacheTTL time.Duration,
 	layoutCacheTTL time.Duration,
 	statsCacheTTL time.Duration,
+	searcher textsearch.Searcher,
 ) (*API, error) {
 	// Hellthread is around 300KB right now so 1000 worst-case threads should be around 300MB
 	threadViewCache, err := lru.NewARC[string, ThreadViewCacheEntry](1000)
@@ -97,6 +114,7 @@ func NewAPI(
 	return &API{
 		PostRegistry:       postRegistry,
 		UserCount:          userCount,
+		Searcher:           searcher,
 		ClusterManager:     clusterManager,
 		LayoutServiceHost:  layoutServiceHost,
 		ThreadViewCacheTTL: threadViewCacheTTL,
@@ -221,6 +239,18 @@ func (api *API) RefreshSiteStats(ctx context.Context) error {
 		return fmt.Errorf("error getting top posters: %w", err)
 	}
 
+	// Get alt text coverage overall, per cluster, and for the top 25 image posters
+	// Alt text stats are optional, keep serving the last ones we got if they fail
+	altTextStats, err := api.PostRegistry.GetAltTextStats(ctx, 25)
+	if err != nil {
+		log.Printf("Error getting alt text stats: %v", err)
+		api.StatsCacheRWMux.RLock()
+		if api.StatsCache != nil {
+			altTextStats = api.StatsCache.Stats.AltText
+		}
+		api.StatsCacheRWMux.RUnlock()
+	}
+
 	// Get usercount from UserCount service
 	userCount, err := api.UserCount.GetUserCount(ctx)
 	if err != nil {
@@ -234,6 +264,9 @@ func (api *API) RefreshSiteStats(ctx context.Context) error {
 	meanPostCount.Set(authorStats.MeanPostCount)
 	totalPostCount.Set(float64(authorStats.TotalPosts))
 	hellthreadPostCount.Set(float64(authorStats.HellthreadPosts))
+	if altTextStats != nil {
+		altTextCoverage.Set(altTextStats.Coverage)
+	}
 
 	// Lock the stats mux for writing
 	span.AddEvent("RefreshSiteStats:AcquireStatsCacheWLock")
@@ -251,6 +284,7 @@ func (api *API) RefreshSiteStats(ctx context.Context) error {
 			Brackets:        authorStats.Brackets,
 			UpdatedAt:       authorStats.UpdatedAt,
 			TopPosters:      topPosters,
+			AltText:         altTextStats,
 		},
 		Expiration: time.Now().Add(api.StatsCacheTTL),
 	}
@@ -666,3 +700,638 @@ func (api *API) getRootOrOldestParent(ctx context.Context, postID string) (*sear
 	// If we have neither, return the post
 	return post, nil
 }
+
+func (api *API) GetFollowCountsForDID(c *gin.Context) {
+	ctx := c.Request.Context()
+	tracer := otel.Tracer("search-api")
+	ctx, span := tracer.Start(ctx, "GetFollowCountsForDID")
+	defer span.End()
+
+	did := c.Param("did")
+	span.SetAttributes(attribute.String("did", did))
+
+	counts, err := api.PostRegistry.GetFollowCounts(ctx, did)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, counts)
+}
+
+func (api *API) GetMutualFollowsForDID(c *gin.Context) {
+	ctx := c.Request.Context()
+	tracer := otel.Tracer("search-api")
+	ctx, span := tracer.Start(ctx, "GetMutualFollowsForDID")
+	defer span.End()
+
+	did := c.Param("did")
+	span.SetAttributes(attribute.String("did", did))
+
+	limit, offset, err := parsePage(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	mutuals, err := api.PostRegistry.GetMutualFollows(ctx, did, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"did": did, "mutuals": mutuals})
+}
+
+func (api *API) GetRepostsForPost(c *gin.Context) {
+	ctx := c.Request.Context()
+	tracer := otel.Tracer("search-api")
+	ctx, span := tracer.Start(ctx, "GetRepostsForPost")
+	defer span.End()
+
+	postID := c.Param("id")
+	span.SetAttributes(attribute.String("post.id", postID))
+
+	limit, offset, err := parsePage(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	count, err := api.PostRegistry.GetRepostCount(ctx, postID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	reposts, err := api.PostRegistry.GetRepostersForPost(ctx, postID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"post_id": postID, "repost_count": count, "reposts": reposts})
+}
+
+// parsePage reads the limit and offset query params for paginated endpoints
+func parsePage(c *gin.Context) (int32, int32, error) {
+	limit := int64(100)
+	if limitQuery := c.Query("limit"); limitQuery != "" {
+		var err error
+		limit, err = strconv.ParseInt(limitQuery, 10, 32)
+		if err != nil || limit < 1 || limit > 1000 {
+			return 0, 0, fmt.Errorf("limit must be an integer between 1 and 1000")
+		}
+	}
+
+	offset := int64(0)
+	if offsetQuery := c.Query("offset"); offsetQuery != "" {
+		var err error
+		offset, err = strconv.ParseInt(offsetQuery, 10, 32)
+		if err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("offset must be a non-negative integer")
+		}
+	}
+
+	return int32(limit), int32(offset), nil
+}
+
+func (api *API) GetProfileForDID(c *gin.Context) {
+	did := c.Param("did")
+	author, err := api.PostRegistry.GetAuthor(c.Request.Context(), did)
+	if err != nil {
+		if errors.As(err, &search.NotFoundError{}) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("did '%s' not found", did)})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	api.writeAuthorProfile(c, author)
+}
+
+func (api *API) GetProfileForHandle(c *gin.Context) {
+	handle := c.Param("handle")
+	authors, err := api.PostRegistry.GetAuthorsByHandle(c.Request.Context(), handle)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(authors) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("handle '%s' not found", handle)})
+		return
+	}
+
+	api.writeAuthorProfile(c, authors[0])
+}
+
+// writeAuthorProfile responds with the author and their profile, if we've indexed one
+func (api *API) writeAuthorProfile(c *gin.Context, author *search.Author) {
+	profile, err := api.PostRegistry.GetAuthorProfile(c.Request.Context(), author.DID)
+	if err != nil && !errors.As(err, &search.NotFoundError{}) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, AuthorProfileResponse{
+		DID:     author.DID,
+		Handle:  author.Handle,
+		Profile: profile,
+	})
+}
+
+func (api *API) GetListMembers(c *gin.Context) {
+	ctx := c.Request.Context()
+	tracer := otel.Tracer("search-api")
+	ctx, span := tracer.Start(ctx, "GetListMembers")
+	defer span.End()
+
+	listURI := c.Query("uri")
+	if listURI == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "uri is required"})
+		return
+	}
+	span.SetAttributes(attribute.String("list.uri", listURI))
+
+	limit, offset, err := parsePage(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	list, err := api.PostRegistry.GetList(ctx, listURI)
+	if err != nil {
+		if errors.As(err, &search.NotFoundError{}) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("list '%s' not found", listURI)})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	members, err := api.PostRegistry.GetListMembers(ctx, listURI, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"list": list, "members": members})
+}
+
+func (api *API) GetListsForDID(c *gin.Context) {
+	ctx := c.Request.Context()
+	tracer := otel.Tracer("search-api")
+	ctx, span := tracer.Start(ctx, "GetListsForDID")
+	defer span.End()
+
+	did := c.Param("did")
+	span.SetAttributes(attribute.String("did", did))
+
+	limit, offset, err := parsePage(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lists, err := api.PostRegistry.GetListsForAuthor(ctx, did, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"did": did, "lists": lists})
+}
+
+func (api *API) SearchFeedGenerators(c *gin.Context) {
+	ctx := c.Request.Context()
+	tracer := otel.Tracer("search-api")
+	ctx, span := tracer.Start(ctx, "SearchFeedGenerators")
+	defer span.End()
+
+	query := c.Query("q")
+	span.SetAttributes(attribute.String("query", query))
+
+	limit, offset, err := parsePage(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	feedGenerators, err := api.PostRegistry.SearchFeedGenerators(ctx, query, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"query": query, "feed_generators": feedGenerators})
+}
+
+func (api *API) GetFeedGenerator(c *gin.Context) {
+	ctx := c.Request.Context()
+	tracer := otel.Tracer("search-api")
+	ctx, span := tracer.Start(ctx, "GetFeedGenerator")
+	defer span.End()
+
+	uri := c.Query("uri")
+	if uri == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "uri is required"})
+		return
+	}
+	span.SetAttributes(attribute.String("uri", uri))
+
+	feedGenerator, err := api.PostRegistry.GetFeedGenerator(ctx, uri)
+	if err != nil {
+		if errors.As(err, &search.NotFoundError{}) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "feed generator not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, feedGenerator)
+}
+
+func (api *API) GetTopLinkDomains(c *gin.Context) {
+	ctx := c.Request.Context()
+	tracer := otel.Tracer("search-api")
+	ctx, span := tracer.Start(ctx, "GetTopLinkDomains")
+	defer span.End()
+
+	hours := int64(24)
+	if hoursQuery := c.Query("hours"); hoursQuery != "" {
+		var err error
+		hours, err = strconv.ParseInt(hoursQuery, 10, 32)
+		if err != nil || hours < 1 || hours > 24*7 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "hours must be an integer between 1 and 168"})
+			return
+		}
+	}
+	span.SetAttributes(attribute.Int64("hours", hours))
+
+	limit, _, err := parsePage(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	domains, err := api.PostRegistry.GetTopLinkDomains(ctx, time.Now().Add(-time.Duration(hours)*time.Hour), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"hours": hours, "domains": domains})
+}
+
+// GetPostsLinkingTo returns a page of the posts linking to the given url or, failing that, the given domain
+func (api *API) GetPostsLinkingTo(c *gin.Context) {
+	ctx := c.Request.Context()
+	tracer := otel.Tracer("search-api")
+	ctx, span := tracer.Start(ctx, "GetPostsLinkingTo")
+	defer span.End()
+
+	limit, offset, err := parsePage(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if linkURL := c.Query("url"); linkURL != "" {
+		span.SetAttributes(attribute.String("url", linkURL))
+		posts, err := api.PostRegistry.GetPostsLinkingToURL(ctx, linkURL, limit, offset)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"url": linkURL, "posts": posts})
+		return
+	}
+
+	if domain := c.Query("domain"); domain != "" {
+		domain = strings.TrimPrefix(strings.ToLower(domain), "www.")
+		span.SetAttributes(attribute.String("domain", domain))
+		posts, err := api.PostRegistry.GetPostsLinkingToDomain(ctx, domain, limit, offset)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"domain": domain, "posts": posts})
+		return
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{"error": "url or domain is required"})
+}
+
+// GetAltTextCoverageForDID returns how consistently an author adds alt text to their images
+func (api *API) GetAltTextCoverageForDID(c *gin.Context) {
+	ctx := c.Request.Context()
+	tracer := otel.Tracer("search-api")
+	ctx, span := tracer.Start(ctx, "GetAltTextCoverageForDID")
+	defer span.End()
+
+	did := c.Param("did")
+	span.SetAttributes(attribute.String("author.did", did))
+
+	// Scores are relative to the global coverage, use the cached stats when they're available
+	var altTextStats *search.AltTextStats
+	api.StatsCacheRWMux.RLock()
+	if api.StatsCache != nil {
+		altTextStats = api.StatsCache.Stats.AltText
+	}
+	api.StatsCacheRWMux.RUnlock()
+
+	var globalCoverage float64
+	if altTextStats != nil {
+		globalCoverage = altTextStats.Coverage
+	} else {
+		coverage, err := api.PostRegistry.GetAltTextCoverage(ctx)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		globalCoverage = coverage.Coverage
+	}
+
+	coverage, err := api.PostRegistry.GetAltTextCoverageForAuthor(ctx, did, globalCoverage)
+	if err != nil {
+		if errors.As(err, &search.NotFoundError{}) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("no images found for did '%s'", did)})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"global_coverage": globalCoverage, "author": coverage})
+}
+
+// parseMinConfidence reads the min_confidence query param, a detection confidence between 0 and 1
+func parseMinConfidence(c *gin.Context, def float64) (float64, error) {
+	minConfidenceQuery := c.Query("min_confidence")
+	if minConfidenceQuery == "" {
+		return def, nil
+	}
+
+	minConfidence, err := strconv.ParseFloat(minConfidenceQuery, 64)
+	if err != nil || minConfidence < 0 || minConfidence > 1 {
+		return 0, fmt.Errorf("min_confidence must be a number between 0 and 1")
+	}
+
+	return minConfidence, nil
+}
+
+// GetTopDetectionLabels returns the objects detected in the most images over the last few hours
+func (api *API) GetTopDetectionLabels(c *gin.Context) {
+	ctx := c.Request.Context()
+	tracer := otel.Tracer("search-api")
+	ctx, span := tracer.Start(ctx, "GetTopDetectionLabels")
+	defer span.End()
+
+	hours := int64(24)
+	if hoursQuery := c.Query("hours"); hoursQuery != "" {
+		var err error
+		hours, err = strconv.ParseInt(hoursQuery, 10, 32)
+		if err != nil || hours < 1 || hours > 24*7 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "hours must be an integer between 1 and 168"})
+			return
+		}
+	}
+	span.SetAttributes(attribute.Int64("hours", hours))
+
+	minConfidence, err := parseMinConfidence(c, objectdetection.DefaultThreshold)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	span.SetAttributes(attribute.Float64("min_confidence", minConfidence))
+
+	limit, _, err := parsePage(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	labels, err := api.PostRegistry.GetTopDetectionLabels(ctx, time.Now().Add(-time.Duration(hours)*time.Hour), minConfidence, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"hours": hours, "min_confidence": minConfidence, "labels": labels})
+}
+
+// GetImagesByDetectionLabel returns a page of the images an object was detected in, newest first
+func (api *API) GetImagesByDetectionLabel(c *gin.Context) {
+	ctx := c.Request.Context()
+	tracer := otel.Tracer("search-api")
+	ctx, span := tracer.Start(ctx, "GetImagesByDetectionLabel")
+	defer span.End()
+
+	label := strings.TrimSpace(c.Query("label"))
+	if label == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "label is required"})
+		return
+	}
+	span.SetAttributes(attribute.String("label", label))
+
+	minConfidence, err := parseMinConfidence(c, objectdetection.DefaultThreshold)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	span.SetAttributes(attribute.Float64("min_confidence", minConfidence))
+
+	limit, offset, err := parsePage(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	images, err := api.PostRegistry.GetImagesByDetectionLabel(ctx, label, minConfidence, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"label": label, "min_confidence": minConfidence, "images": images})
+}
+
+// GetDetectionsForImage returns every object detected in an image
+func (api *API) GetDetectionsForImage(c *gin.Context) {
+	ctx := c.Request.Context()
+	tracer := otel.Tracer("search-api")
+	ctx, span := tracer.Start(ctx, "GetDetectionsForImage")
+	defer span.End()
+
+	imageCID := c.Param("cid")
+	span.SetAttributes(attribute.String("image.cid", imageCID))
+
+	detections, err := api.PostRegistry.GetDetectionsForImage(ctx, imageCID)
+	if err != nil {
+		if errors.As(err, &search.NotFoundError{}) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("no detections found for image '%s'", imageCID)})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"cid": imageCID, "detections": detections})
+}
+
+// GetSimilarImages returns the images that look like the given image, closest first
+func (api *API) GetSimilarImages(c *gin.Context) {
+	ctx := c.Request.Context()
+	tracer := otel.Tracer("search-api")
+	ctx, span := tracer.Start(ctx, "GetSimilarImages")
+	defer span.End()
+
+	imageCID := c.Param("cid")
+	span.SetAttributes(attribute.String("image.cid", imageCID))
+
+	maxDistance := int64(search.DuplicateImageMaxDistance)
+	if maxDistanceQuery := c.Query("max_distance"); maxDistanceQuery != "" {
+		var err error
+		maxDistance, err = strconv.ParseInt(maxDistanceQuery, 10, 32)
+		if err != nil || maxDistance < 0 || maxDistance > search.SimilarImageMaxDistance {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("max_distance must be an integer between 0 and %d", search.SimilarImageMaxDistance)})
+			return
+		}
+	}
+	span.SetAttributes(attribute.Int64("max_distance", maxDistance))
+
+	limit, _, err := parsePage(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	images, err := api.PostRegistry.GetSimilarImages(ctx, imageCID, int32(maxDistance), limit)
+	if err != nil {
+		if errors.As(err, &search.NotFoundError{}) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("no hash found for image '%s'", imageCID)})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"cid": imageCID, "max_distance": maxDistance, "images": images})
+}
+
+// SearchPosts runs a full-text search over posts
+// Results can be filtered by author DID, post label, cluster ID, language, and an RFC3339 since/until range
+func (api *API) SearchPosts(c *gin.Context) {
+	ctx := c.Request.Context()
+	tracer := otel.Tracer("search-api")
+	ctx, span := tracer.Start(ctx, "SearchPosts")
+	defer span.End()
+
+	query := search.PostSearchQuery{
+		Query:     strings.TrimSpace(c.Query("q")),
+		AuthorDID: c.Query("author"),
+		Label:     c.Query("label"),
+		Lang:      language.Normalize(c.Query("lang")),
+	}
+	if query.Query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	span.SetAttributes(attribute.String("query", query.Query))
+
+	if clusterQuery := c.Query("cluster"); clusterQuery != "" {
+		clusterID, err := strconv.ParseInt(clusterQuery, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "cluster must be an integer cluster ID"})
+			return
+		}
+		id := int32(clusterID)
+		query.ClusterID = &id
+	}
+
+	var err error
+	query.Since, err = parseTimeQuery(c, "since")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query.Until, err = parseTimeQuery(c, "until")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query.Limit, query.Offset, err = parsePage(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	posts, err := api.Searcher.SearchPosts(ctx, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"query": query.Query, "posts": posts})
+}
+
+// parseTimeQuery parses an optional RFC3339 timestamp query parameter
+func parseTimeQuery(c *gin.Context, name string) (*time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an RFC3339 timestamp", name)
+	}
+
+	return &t, nil
+}
+
+// SearchAuthors is a typeahead search over author handles and display names
+// Matches are ranked by how well they match and how connected the author is in the social graph
+func (api *API) SearchAuthors(c *gin.Context) {
+	ctx := c.Request.Context()
+	tracer := otel.Tracer("search-api")
+	ctx, span := tracer.Start(ctx, "SearchAuthors")
+	defer span.End()
+
+	query := search.NormalizeAuthorQuery(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	span.SetAttributes(attribute.String("query", query))
+
+	limit := int64(10)
+	if limitQuery := c.Query("limit"); limitQuery != "" {
+		var err error
+		limit, err = strconv.ParseInt(limitQuery, 10, 32)
+		if err != nil || limit < 1 || limit > 50 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be an integer between 1 and 50"})
+			return
+		}
+	}
+
+	// Over-fetch so well connected authors further down the text ranking can be pulled up
+	matches, err := api.PostRegistry.SearchAuthors(ctx, query, int32(limit*authorSearchCandidateFactor))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	search.RankAuthorMatches(query, matches, api.ClusterManager.GetDegreeForDID)
+
+	if len(matches) > int(limit) {
+		matches = matches[:limit]
+	}
+
+	c.JSON(http.StatusOK, gin.H{"query": query, "authors": matches})
+}
@@ -40,3 +40,8 @@ var hellthreadPostCount = promauto.NewGauge(prometheus.GaugeOpts{
 	Name: "bsky_hellthread_post_count",
 	Help: "The number of posts in the hellthread",
 })
+
+var altTextCoverage = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "bsky_alt_text_coverage",
+	Help: "The share of images that have alt text",
+})
@@ -0,0 +1,167 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search/search_queries"
+	"go.opentelemetry.io/otel"
+)
+
+type FeedGenerator struct {
+	URI           string     `json:"uri"`
+	CreatorDID    string     `json:"creator_did"`
+	RKey          string     `json:"rkey"`
+	DisplayName   string     `json:"display_name"`
+	Description   *string    `json:"description"`
+	ServiceDID    string     `json:"service_did"`
+	LikeCount     int64      `json:"like_count"`
+	Healthy       *bool      `json:"healthy"`
+	HealthError   *string    `json:"health_error"`
+	LastCheckedAt *time.Time `json:"last_checked_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func feedGeneratorFromRow(row search_queries.FeedGenerator) *FeedGenerator {
+	feedGenerator := &FeedGenerator{
+		URI:         row.Uri,
+		CreatorDID:  row.CreatorDid,
+		RKey:        row.Rkey,
+		DisplayName: row.DisplayName,
+		Description: ptrFromNullString(row.Description),
+		ServiceDID:  row.ServiceDid,
+		LikeCount:   row.LikeCount,
+		HealthError: ptrFromNullString(row.HealthError),
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+
+	if row.Healthy.Valid {
+		feedGenerator.Healthy = &row.Healthy.Bool
+	}
+
+	if row.LastCheckedAt.Valid {
+		feedGenerator.LastCheckedAt = &row.LastCheckedAt.Time
+	}
+
+	return feedGenerator
+}
+
+func (pr *PostRegistry) UpsertFeedGenerator(ctx context.Context, feedGenerator *FeedGenerator) error {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:UpsertFeedGenerator")
+	defer span.End()
+
+	err := pr.queries.UpsertFeedGenerator(ctx, search_queries.UpsertFeedGeneratorParams{
+		Uri:         feedGenerator.URI,
+		CreatorDid:  feedGenerator.CreatorDID,
+		Rkey:        feedGenerator.RKey,
+		DisplayName: feedGenerator.DisplayName,
+		Description: nullStringFromPtr(feedGenerator.Description),
+		ServiceDid:  feedGenerator.ServiceDID,
+		CreatedAt:   feedGenerator.CreatedAt,
+		UpdatedAt:   feedGenerator.UpdatedAt,
+	})
+	return err
+}
+
+func (pr *PostRegistry) DeleteFeedGenerator(ctx context.Context, uri string) error {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:DeleteFeedGenerator")
+	defer span.End()
+
+	return pr.queries.DeleteFeedGenerator(ctx, uri)
+}
+
+// AddLikeToFeedGenerator increments the like count of a feed generator, likes of feed generators that aren't indexed yet are counted once they are
+func (pr *PostRegistry) AddLikeToFeedGenerator(ctx context.Context, uri string) error {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:AddLikeToFeedGenerator")
+	defer span.End()
+
+	return pr.queries.IncrementFeedGeneratorLikeCount(ctx, uri)
+}
+
+func (pr *PostRegistry) GetFeedGenerator(ctx context.Context, uri string) (*FeedGenerator, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetFeedGenerator")
+	defer span.End()
+
+	feedGenerator, err := pr.queries.GetFeedGenerator(ctx, uri)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, NotFoundError{fmt.Errorf("feed generator not found")}
+		}
+		return nil, err
+	}
+
+	return feedGeneratorFromRow(feedGenerator), nil
+}
+
+// SearchFeedGenerators returns a page of feed generators matching the query, most liked first
+func (pr *PostRegistry) SearchFeedGenerators(ctx context.Context, query string, limit int32, offset int32) ([]*FeedGenerator, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:SearchFeedGenerators")
+	defer span.End()
+
+	feedGenerators, err := pr.queries.SearchFeedGenerators(ctx, search_queries.SearchFeedGeneratorsParams{
+		Query:  query,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search feed generators: %w", err)
+	}
+
+	retFeedGenerators := make([]*FeedGenerator, len(feedGenerators))
+	for i, feedGenerator := range feedGenerators {
+		retFeedGenerators[i] = feedGeneratorFromRow(feedGenerator)
+	}
+
+	return retFeedGenerators, nil
+}
+
+// GetFeedGeneratorsToCheck returns up to limit feed generators that haven't been health checked since checkedBefore
+func (pr *PostRegistry) GetFeedGeneratorsToCheck(ctx context.Context, checkedBefore time.Time, limit int32) ([]*FeedGenerator, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetFeedGeneratorsToCheck")
+	defer span.End()
+
+	feedGenerators, err := pr.queries.GetFeedGeneratorsToCheck(ctx, search_queries.GetFeedGeneratorsToCheckParams{
+		CheckedBefore: checkedBefore,
+		Limit:         limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed generators to check: %w", err)
+	}
+
+	retFeedGenerators := make([]*FeedGenerator, len(feedGenerators))
+	for i, feedGenerator := range feedGenerators {
+		retFeedGenerators[i] = feedGeneratorFromRow(feedGenerator)
+	}
+
+	return retFeedGenerators, nil
+}
+
+// UpdateFeedGeneratorHealth records the result of a health check, a nil checkErr marks the feed generator healthy
+func (pr *PostRegistry) UpdateFeedGeneratorHealth(ctx context.Context, uri string, checkErr error, checkedAt time.Time) error {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:UpdateFeedGeneratorHealth")
+	defer span.End()
+
+	healthError := sql.NullString{}
+	if checkErr != nil {
+		healthError = sql.NullString{String: checkErr.Error(), Valid: true}
+	}
+
+	err := pr.queries.UpdateFeedGeneratorHealth(ctx, search_queries.UpdateFeedGeneratorHealthParams{
+		Uri:           uri,
+		Healthy:       sql.NullBool{Bool: checkErr == nil, Valid: true},
+		HealthError:   healthError,
+		LastCheckedAt: sql.NullTime{Time: checkedAt, Valid: true},
+	})
+	return err
+}
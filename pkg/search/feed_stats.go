@@ -0,0 +1,133 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	_ "github.com/lib/pq" // postgres driver
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search/search_queries"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// FeedStats is a daily rollup of how a feed was used and how the posts it served performed
+type FeedStats struct {
+	FeedName          string    `json:"feed_name"`
+	Date              time.Time `json:"date"`
+	Requests          int64     `json:"requests"`
+	Sessions          int64     `json:"sessions"`
+	PostsServed       int64     `json:"posts_served"`
+	UniquePostsServed int64     `json:"unique_posts_served"`
+	UniqueUsers       int64     `json:"unique_users"`
+	ReturningUsers    int64     `json:"returning_users"`
+	LikedPosts        int64     `json:"liked_posts"`
+	TotalLikes        int64     `json:"total_likes"`
+	UpdatedAt         time.Time `json:"updated_at"`
+
+	// Derived rates, computed by ComputeRates
+	AveragePageDepth  float64 `json:"average_page_depth"`
+	ReturningUserRate float64 `json:"returning_user_rate"`
+	EngagementRate    float64 `json:"engagement_rate"`
+	LikesPerPost      float64 `json:"likes_per_post"`
+}
+
+// LikeTotals summarizes the likes received by a set of posts
+type LikeTotals struct {
+	LikedPosts int64 `json:"liked_posts"`
+	TotalLikes int64 `json:"total_likes"`
+}
+
+// ComputeRates fills in the derived rate fields from the raw counts
+func (fs *FeedStats) ComputeRates() {
+	if fs.Sessions > 0 {
+		fs.AveragePageDepth = float64(fs.Requests) / float64(fs.Sessions)
+	}
+	if fs.UniqueUsers > 0 {
+		fs.ReturningUserRate = float64(fs.ReturningUsers) / float64(fs.UniqueUsers)
+	}
+	if fs.UniquePostsServed > 0 {
+		fs.EngagementRate = float64(fs.LikedPosts) / float64(fs.UniquePostsServed)
+		fs.LikesPerPost = float64(fs.TotalLikes) / float64(fs.UniquePostsServed)
+	}
+}
+
+func (pr *PostRegistry) UpsertFeedStats(ctx context.Context, stats *FeedStats) error {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:UpsertFeedStats")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("feed.name", stats.FeedName))
+
+	err := pr.queries.UpsertFeedStats(ctx, search_queries.UpsertFeedStatsParams{
+		FeedName:          stats.FeedName,
+		Date:              stats.Date,
+		Requests:          stats.Requests,
+		Sessions:          stats.Sessions,
+		PostsServed:       stats.PostsServed,
+		UniquePostsServed: stats.UniquePostsServed,
+		UniqueUsers:       stats.UniqueUsers,
+		ReturningUsers:    stats.ReturningUsers,
+		LikedPosts:        stats.LikedPosts,
+		TotalLikes:        stats.TotalLikes,
+	})
+	return err
+}
+
+// GetFeedStats returns the most recent daily rollups for a feed, newest first
+func (pr *PostRegistry) GetFeedStats(ctx context.Context, feedName string, days int32) ([]*FeedStats, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetFeedStats")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("feed.name", feedName))
+
+	rows, err := pr.queries.GetFeedStats(ctx, search_queries.GetFeedStatsParams{
+		FeedName: feedName,
+		Limit:    days,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed stats: %w", err)
+	}
+
+	retStats := make([]*FeedStats, len(rows))
+	for i, row := range rows {
+		stats := &FeedStats{
+			FeedName:          row.FeedName,
+			Date:              row.Date,
+			Requests:          row.Requests,
+			Sessions:          row.Sessions,
+			PostsServed:       row.PostsServed,
+			UniquePostsServed: row.UniquePostsServed,
+			UniqueUsers:       row.UniqueUsers,
+			ReturningUsers:    row.ReturningUsers,
+			LikedPosts:        row.LikedPosts,
+			TotalLikes:        row.TotalLikes,
+			UpdatedAt:         row.UpdatedAt,
+		}
+		stats.ComputeRates()
+		retStats[i] = stats
+	}
+
+	return retStats, nil
+}
+
+// GetLikeTotalsForPosts returns how many of the given posts have been liked and their total like count
+func (pr *PostRegistry) GetLikeTotalsForPosts(ctx context.Context, postIDs []string) (*LikeTotals, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetLikeTotalsForPosts")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("posts.length", len(postIDs)))
+
+	totals, err := pr.queries.GetLikeTotalsForPosts(ctx, postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get like totals for posts: %w", err)
+	}
+
+	return &LikeTotals{
+		LikedPosts: totals.LikedPosts,
+		TotalLikes: totals.TotalLikes,
+	}, nil
+}
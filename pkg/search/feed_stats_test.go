@@ -0,0 +1,34 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeRates(t *testing.T) {
+	stats := &FeedStats{
+		Requests:          30,
+		Sessions:          10,
+		UniqueUsers:       8,
+		ReturningUsers:    2,
+		UniquePostsServed: 200,
+		LikedPosts:        50,
+		TotalLikes:        120,
+	}
+	stats.ComputeRates()
+
+	assert.Equal(t, 3.0, stats.AveragePageDepth)
+	assert.Equal(t, 0.25, stats.ReturningUserRate)
+	assert.Equal(t, 0.25, stats.EngagementRate)
+	assert.Equal(t, 0.6, stats.LikesPerPost)
+
+	// A feed that hasn't been served leaves the rates at zero instead of dividing by zero
+	empty := &FeedStats{}
+	empty.ComputeRates()
+
+	assert.Zero(t, empty.AveragePageDepth)
+	assert.Zero(t, empty.ReturningUserRate)
+	assert.Zero(t, empty.EngagementRate)
+	assert.Zero(t, empty.LikesPerPost)
+}
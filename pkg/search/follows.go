@@ -0,0 +1,95 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search/search_queries"
+	"go.opentelemetry.io/otel"
+)
+
+type FollowCounts struct {
+	DID       string `json:"did"`
+	Followers int64  `json:"followers"`
+	Following int64  `json:"following"`
+}
+
+// AddFollow records a follow and returns false if the follow record was already indexed
+func (pr *PostRegistry) AddFollow(ctx context.Context, actorDID, rkey, targetDID string, createdAt time.Time) (bool, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:AddFollow")
+	defer span.End()
+
+	inserted, err := pr.queries.AddFollow(ctx, search_queries.AddFollowParams{
+		ActorDid:  actorDID,
+		Rkey:      rkey,
+		TargetDid: targetDID,
+		CreatedAt: createdAt,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return inserted > 0, nil
+}
+
+// RemoveFollow deletes the follow record with the given rkey and returns the DID that was unfollowed
+func (pr *PostRegistry) RemoveFollow(ctx context.Context, actorDID, rkey string) (string, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:RemoveFollow")
+	defer span.End()
+
+	targetDID, err := pr.queries.RemoveFollow(ctx, search_queries.RemoveFollowParams{
+		ActorDid: actorDID,
+		Rkey:     rkey,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", NotFoundError{fmt.Errorf("follow not found")}
+		}
+		return "", err
+	}
+
+	return targetDID, nil
+}
+
+func (pr *PostRegistry) GetFollowCounts(ctx context.Context, did string) (*FollowCounts, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetFollowCounts")
+	defer span.End()
+
+	followers, err := pr.queries.GetFollowerCount(ctx, did)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get follower count: %w", err)
+	}
+
+	following, err := pr.queries.GetFollowingCount(ctx, did)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get following count: %w", err)
+	}
+
+	return &FollowCounts{
+		DID:       did,
+		Followers: followers,
+		Following: following,
+	}, nil
+}
+
+func (pr *PostRegistry) GetMutualFollows(ctx context.Context, did string, limit int32, offset int32) ([]string, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetMutualFollows")
+	defer span.End()
+
+	mutuals, err := pr.queries.GetMutualFollows(ctx, search_queries.GetMutualFollowsParams{
+		ActorDid: did,
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mutual follows: %w", err)
+	}
+
+	return mutuals, nil
+}
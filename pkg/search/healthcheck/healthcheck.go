@@ -0,0 +1,243 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	appbsky "github.com/bluesky-social/indigo/api/bsky"
+	"github.com/ericvolp12/bsky-experiments/pkg/plc"
+	"github.com/ericvolp12/bsky-experiments/pkg/search"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// feedGeneratorServiceID is the fragment of the DID document service entry that points at a feed generator
+const feedGeneratorServiceID = "#bsky_fg"
+
+// HealthChecker periodically calls describeFeedGenerator on every indexed feed generator's service
+// and records whether the service is up and still serving the feed in the PostRegistry
+type HealthChecker struct {
+	PostRegistry  *search.PostRegistry
+	Client        *http.Client
+	Limiter       *rate.Limiter
+	PLCDirectory  string
+	CheckInterval time.Duration
+	BatchSize     int32
+	Workers       int
+	Logger        *zap.SugaredLogger
+}
+
+func NewHealthChecker(postRegistry *search.PostRegistry, checkInterval time.Duration, logger *zap.SugaredLogger) *HealthChecker {
+	return &HealthChecker{
+		PostRegistry:  postRegistry,
+		Client:        NewPublicClient(10 * time.Second),
+		Limiter:       rate.NewLimiter(rate.Limit(5), 1),
+		PLCDirectory:  "https://plc.directory",
+		CheckInterval: checkInterval,
+		BatchSize:     100,
+		Workers:       4,
+		Logger:        logger,
+	}
+}
+
+// NewPublicClient returns an HTTP client that only connects to public addresses and doesn't follow redirects
+// Service endpoints come from third party DID documents, so they can't be trusted to point outside our network
+func NewPublicClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		// Control runs after the host is resolved, so every address actually dialed is checked
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !IsPublicIP(ip) {
+				return fmt.Errorf("refusing to connect to non-public address %s", host)
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be dialed instead of the service, skipping the address check
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Transport: otelhttp.NewTransport(transport),
+		Timeout:   timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// IsPublicIP reports whether the IP is routable on the public internet
+func IsPublicIP(ip net.IP) bool {
+	return !(ip.IsUnspecified() ||
+		ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast())
+}
+
+// Run checks feed generators that are due for a check until the context is cancelled
+func (hc *HealthChecker) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		hc.checkDueFeedGenerators(ctx)
+
+		select {
+		case <-ticker.C:
+			continue
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (hc *HealthChecker) checkDueFeedGenerators(ctx context.Context) {
+	tracer := otel.Tracer("healthcheck")
+	ctx, span := tracer.Start(ctx, "HealthChecker:checkDueFeedGenerators")
+	defer span.End()
+
+	feedGenerators, err := hc.PostRegistry.GetFeedGeneratorsToCheck(ctx, time.Now().Add(-hc.CheckInterval), hc.BatchSize)
+	if err != nil {
+		hc.Logger.Errorf("failed to get feed generators to check: %+v", err)
+		return
+	}
+
+	span.SetAttributes(attribute.Int("feed_generators.count", len(feedGenerators)))
+
+	queue := make(chan *search.FeedGenerator)
+	wg := sync.WaitGroup{}
+	for i := 0; i < hc.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for feedGenerator := range queue {
+				hc.recordCheck(ctx, feedGenerator)
+			}
+		}()
+	}
+
+	for _, feedGenerator := range feedGenerators {
+		queue <- feedGenerator
+	}
+	close(queue)
+	wg.Wait()
+}
+
+func (hc *HealthChecker) recordCheck(ctx context.Context, feedGenerator *search.FeedGenerator) {
+	start := time.Now()
+	checkErr := hc.CheckFeedGenerator(ctx, feedGenerator)
+	healthCheckDurationHistogram.Observe(time.Since(start).Seconds())
+
+	if checkErr != nil {
+		healthChecksCounter.WithLabelValues("unhealthy").Inc()
+		hc.Logger.Debugw("feed generator is unhealthy", "uri", feedGenerator.URI, "error", checkErr)
+	} else {
+		healthChecksCounter.WithLabelValues("healthy").Inc()
+	}
+
+	err := hc.PostRegistry.UpdateFeedGeneratorHealth(ctx, feedGenerator.URI, checkErr, time.Now())
+	if err != nil {
+		hc.Logger.Errorf("failed to record health of feed generator %s: %+v", feedGenerator.URI, err)
+	}
+}
+
+// CheckFeedGenerator resolves the feed generator's service DID and checks that its describeFeedGenerator lists the feed
+func (hc *HealthChecker) CheckFeedGenerator(ctx context.Context, feedGenerator *search.FeedGenerator) error {
+	tracer := otel.Tracer("healthcheck")
+	ctx, span := tracer.Start(ctx, "HealthChecker:CheckFeedGenerator")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("feed_generator.uri", feedGenerator.URI))
+	span.SetAttributes(attribute.String("feed_generator.service_did", feedGenerator.ServiceDID))
+
+	endpoint, err := hc.resolveServiceEndpoint(ctx, feedGenerator.ServiceDID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	span.SetAttributes(attribute.String("feed_generator.endpoint", endpoint))
+
+	description := appbsky.FeedDescribeFeedGenerator_Output{}
+	err = hc.getJSON(ctx, strings.TrimSuffix(endpoint, "/")+"/xrpc/app.bsky.feed.describeFeedGenerator", &description)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("error describing feed generator: %w", err)
+	}
+
+	if description.Did != feedGenerator.ServiceDID {
+		return fmt.Errorf("service describes itself as %q instead of %q", description.Did, feedGenerator.ServiceDID)
+	}
+
+	for _, feed := range description.Feeds {
+		if feed != nil && feed.Uri == feedGenerator.URI {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("feed is not listed by the service")
+}
+
+// resolveServiceEndpoint looks up the feed generator service endpoint in the service DID's document
+func (hc *HealthChecker) resolveServiceEndpoint(ctx context.Context, did string) (string, error) {
+	err := hc.Limiter.Wait(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error waiting for rate limiter: %w", err)
+	}
+
+	doc, err := plc.ResolveDIDDocument(ctx, hc.Client, hc.PLCDirectory, did)
+	if err != nil {
+		return "", err
+	}
+
+	return doc.ServiceEndpoint(feedGeneratorServiceID)
+}
+
+func (hc *HealthChecker) getJSON(ctx context.Context, url string, out interface{}) error {
+	err := hc.Limiter.Wait(ctx)
+	if err != nil {
+		return fmt.Errorf("error waiting for rate limiter: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+
+	resp, err := hc.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(out)
+	if err != nil {
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestCheckFeedGenerator(t *testing.T) {
+	serviceDID := "did:plc:q6gjnaw2blty4crticxkmujt"
+	feedURI := "at://did:plc:q6gjnaw2blty4crticxkmujt/app.bsky.feed.generator/cats"
+
+	testCases := []struct {
+		name        string
+		describedAs string
+		feedURI     string
+		wantErr     bool
+	}{
+		{
+			name:        "Feed is listed",
+			describedAs: serviceDID,
+			feedURI:     feedURI,
+		},
+		{
+			name:        "Feed is not listed",
+			describedAs: serviceDID,
+			feedURI:     "at://did:plc:q6gjnaw2blty4crticxkmujt/app.bsky.feed.generator/dogs",
+			wantErr:     true,
+		},
+		{
+			name:        "Service describes another DID",
+			describedAs: "did:web:feedgen.jazco.io",
+			feedURI:     feedURI,
+			wantErr:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			feedGenerator := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/xrpc/app.bsky.feed.describeFeedGenerator", r.URL.Path)
+				fmt.Fprintf(w, `{"did": %q, "feeds": [{"uri": %q}]}`, tc.describedAs, tc.feedURI)
+			}))
+			defer feedGenerator.Close()
+
+			plcDirectory := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/"+serviceDID, r.URL.Path)
+				fmt.Fprintf(w, `{"id": %q, "service": [{"id": "#bsky_fg", "type": "BskyFeedGenerator", "serviceEndpoint": %q}]}`, serviceDID, feedGenerator.URL)
+			}))
+			defer plcDirectory.Close()
+
+			hc := NewHealthChecker(nil, time.Hour, zap.NewNop().Sugar())
+			// The test servers listen on loopback, which the public client refuses
+			hc.Client = &http.Client{Timeout: time.Second}
+			hc.PLCDirectory = plcDirectory.URL
+
+			err := hc.CheckFeedGenerator(context.Background(), &search.FeedGenerator{
+				URI:        feedURI,
+				ServiceDID: serviceDID,
+			})
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPublicClientRefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	resp, err := NewPublicClient(time.Second).Get(server.URL)
+	if err == nil {
+		resp.Body.Close()
+	}
+	assert.ErrorContains(t, err, "refusing to connect to non-public address")
+}
+
+func TestIsPublicIP(t *testing.T) {
+	testCases := []struct {
+		ip     string
+		public bool
+	}{
+		{ip: "1.1.1.1", public: true},
+		{ip: "2606:4700:4700::1111", public: true},
+		{ip: "127.0.0.1"},
+		{ip: "::1"},
+		{ip: "10.0.0.1"},
+		{ip: "172.16.0.1"},
+		{ip: "192.168.1.1"},
+		{ip: "169.254.169.254"},
+		{ip: "fe80::1"},
+		{ip: "fd00::1"},
+		{ip: "0.0.0.0"},
+		{ip: "224.0.0.1"},
+		{ip: "::ffff:127.0.0.1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.ip, func(t *testing.T) {
+			assert.Equal(t, tc.public, IsPublicIP(net.ParseIP(tc.ip)))
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package healthcheck
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var healthChecksCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "feed_generator_health_checks_total",
+	Help: "The total number of feed generator health checks by result",
+}, []string{"result"})
+
+var healthCheckDurationHistogram = promauto.NewHistogram(prometheus.HistogramOpts{
+	Name: "feed_generator_health_check_duration_seconds",
+	Help: "Histogram of the time (in seconds) each feed generator health check takes",
+})
@@ -0,0 +1,184 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search/search_queries"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ImageDetection is a single object detected in an image
+// Box is the bounding box of the object as [x_min, y_min, x_max, y_max]
+type ImageDetection struct {
+	ImageCID       string     `json:"image_cid"`
+	PostID         string     `json:"post_id"`
+	AuthorDID      string     `json:"author_did"`
+	DetectionIndex int32      `json:"detection_index"`
+	Label          string     `json:"label"`
+	Confidence     float64    `json:"confidence"`
+	Box            [4]float64 `json:"box"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// DetectedImage is an image an object was detected in, Confidence is the best detection of the object in the image
+type DetectedImage struct {
+	CID            string    `json:"cid"`
+	PostID         string    `json:"post_id"`
+	AuthorDID      string    `json:"author_did"`
+	AltText        *string   `json:"alt_text"`
+	FullsizeURL    string    `json:"fullsize_url"`
+	ThumbnailURL   string    `json:"thumbnail_url"`
+	CreatedAt      time.Time `json:"created_at"`
+	Confidence     float64   `json:"confidence"`
+	DetectionCount int64     `json:"detection_count"`
+}
+
+type DetectionLabel struct {
+	Label         string  `json:"label"`
+	ImageCount    int64   `json:"image_count"`
+	AvgConfidence float64 `json:"avg_confidence"`
+}
+
+// AddImageDetections stores the objects detected in images, re-adding a detection overwrites it
+func (pr *PostRegistry) AddImageDetections(ctx context.Context, detections []*ImageDetection) error {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:AddImageDetections")
+	defer span.End()
+
+	if len(detections) == 0 {
+		return nil
+	}
+
+	span.SetAttributes(attribute.Int("detections", len(detections)))
+
+	params := search_queries.AddImageDetectionsParams{
+		ImageCids:        make([]string, len(detections)),
+		PostIds:          make([]string, len(detections)),
+		AuthorDids:       make([]string, len(detections)),
+		DetectionIndexes: make([]int32, len(detections)),
+		Labels:           make([]string, len(detections)),
+		Confidences:      make([]float64, len(detections)),
+		BoxXMins:         make([]float64, len(detections)),
+		BoxYMins:         make([]float64, len(detections)),
+		BoxXMaxes:        make([]float64, len(detections)),
+		BoxYMaxes:        make([]float64, len(detections)),
+		CreatedAts:       make([]time.Time, len(detections)),
+	}
+
+	for i, detection := range detections {
+		params.ImageCids[i] = detection.ImageCID
+		params.PostIds[i] = detection.PostID
+		params.AuthorDids[i] = detection.AuthorDID
+		params.DetectionIndexes[i] = detection.DetectionIndex
+		params.Labels[i] = detection.Label
+		params.Confidences[i] = detection.Confidence
+		params.BoxXMins[i] = detection.Box[0]
+		params.BoxYMins[i] = detection.Box[1]
+		params.BoxXMaxes[i] = detection.Box[2]
+		params.BoxYMaxes[i] = detection.Box[3]
+		params.CreatedAts[i] = detection.CreatedAt
+	}
+
+	err := pr.queries.AddImageDetections(ctx, params)
+	if err != nil {
+		return fmt.Errorf("failed to add image detections: %w", err)
+	}
+
+	return nil
+}
+
+// GetDetectionsForImage returns every object detected in an image
+func (pr *PostRegistry) GetDetectionsForImage(ctx context.Context, imageCID string) ([]*ImageDetection, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetDetectionsForImage")
+	defer span.End()
+
+	detections, err := pr.queries.GetDetectionsForImage(ctx, imageCID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get detections for image: %w", err)
+	}
+
+	if len(detections) == 0 {
+		return nil, NotFoundError{fmt.Errorf("no detections found for image %s", imageCID)}
+	}
+
+	retDetections := make([]*ImageDetection, len(detections))
+	for i, detection := range detections {
+		retDetections[i] = &ImageDetection{
+			ImageCID:       detection.ImageCid,
+			PostID:         detection.PostID,
+			AuthorDID:      detection.AuthorDid,
+			DetectionIndex: detection.DetectionIndex,
+			Label:          detection.Label,
+			Confidence:     detection.Confidence,
+			Box:            [4]float64{detection.BoxXMin, detection.BoxYMin, detection.BoxXMax, detection.BoxYMax},
+			CreatedAt:      detection.CreatedAt,
+		}
+	}
+
+	return retDetections, nil
+}
+
+// GetImagesByDetectionLabel returns a page of the images an object was detected in with at least minConfidence, newest first
+func (pr *PostRegistry) GetImagesByDetectionLabel(ctx context.Context, label string, minConfidence float64, limit int32, offset int32) ([]*DetectedImage, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetImagesByDetectionLabel")
+	defer span.End()
+
+	images, err := pr.queries.GetImagesByDetectionLabel(ctx, search_queries.GetImagesByDetectionLabelParams{
+		Label:         label,
+		MinConfidence: minConfidence,
+		Limit:         limit,
+		Offset:        offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get images by detection label: %w", err)
+	}
+
+	retImages := make([]*DetectedImage, len(images))
+	for i, image := range images {
+		retImages[i] = &DetectedImage{
+			CID:            image.Cid,
+			PostID:         image.PostID,
+			AuthorDID:      image.AuthorDid,
+			AltText:        ptrFromNullString(image.AltText),
+			FullsizeURL:    image.FullsizeUrl,
+			ThumbnailURL:   image.ThumbnailUrl,
+			CreatedAt:      image.CreatedAt,
+			Confidence:     image.Confidence,
+			DetectionCount: image.DetectionCount,
+		}
+	}
+
+	return retImages, nil
+}
+
+// GetTopDetectionLabels returns the objects detected in the most images since the given time
+func (pr *PostRegistry) GetTopDetectionLabels(ctx context.Context, since time.Time, minConfidence float64, limit int32) ([]*DetectionLabel, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetTopDetectionLabels")
+	defer span.End()
+
+	labels, err := pr.queries.GetTopDetectionLabels(ctx, search_queries.GetTopDetectionLabelsParams{
+		Since:         since,
+		MinConfidence: minConfidence,
+		Limit:         limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top detection labels: %w", err)
+	}
+
+	retLabels := make([]*DetectionLabel, len(labels))
+	for i, label := range labels {
+		retLabels[i] = &DetectionLabel{
+			Label:         label.Label,
+			ImageCount:    label.ImageCount,
+			AvgConfidence: label.AvgConfidence,
+		}
+	}
+
+	return retLabels, nil
+}
@@ -0,0 +1,279 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/imagehash"
+	"github.com/ericvolp12/bsky-experiments/pkg/search/search_queries"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// RepostedImageLabel marks posts with an image another author posted first
+	RepostedImageLabel = "image:reposted"
+	// ViralImageLabel marks every post of an image once enough authors have posted it
+	ViralImageLabel = "image:viral"
+
+	// SimilarImageMaxDistance is the furthest apart two pHashes are guaranteed to be found, they share one of four 16 bit bands
+	SimilarImageMaxDistance = 3
+	// DuplicateImageMaxDistance is how many bits two pHashes can differ by for the images to count as duplicates
+	DuplicateImageMaxDistance = SimilarImageMaxDistance
+	// DuplicateImageWindow is how far back posts of an image are looked for when labeling duplicates
+	DuplicateImageWindow = 7 * 24 * time.Hour
+	// ViralImageMinAuthors is how many authors need to post an image for it to be viral
+	ViralImageMinAuthors = 10
+)
+
+type UnhashedImage struct {
+	CID          string    `json:"cid"`
+	PostID       string    `json:"post_id"`
+	AuthorDID    string    `json:"author_did"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type SimilarImage struct {
+	CID           string    `json:"cid"`
+	Distance      int32     `json:"distance"`
+	PostCount     int64     `json:"post_count"`
+	FirstPostedAt time.Time `json:"first_posted_at"`
+	ThumbnailURL  string    `json:"thumbnail_url"`
+}
+
+type DuplicateImagePost struct {
+	CID       string    `json:"cid"`
+	PostID    string    `json:"post_id"`
+	AuthorDID string    `json:"author_did"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AddImageHash stores the perceptual hashes of an image's thumbnail
+// A nil hashes records that the thumbnail couldn't be hashed so it isn't retried,
+// low entropy pHashes are left out so flat images don't match each other as duplicates
+func (pr *PostRegistry) AddImageHash(ctx context.Context, cid string, hashes *imagehash.Hashes, hashedAt time.Time) error {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:AddImageHash")
+	defer span.End()
+
+	params := search_queries.AddImageHashParams{
+		Cid:      cid,
+		HashedAt: hashedAt,
+	}
+
+	// Hashes are stored as BIGINT so they're reinterpreted as signed
+	if hashes != nil {
+		params.Phash = sql.NullInt64{Int64: int64(hashes.PHash), Valid: !imagehash.LowEntropy(hashes.PHash)}
+		params.Dhash = sql.NullInt64{Int64: int64(hashes.DHash), Valid: true}
+	}
+
+	err := pr.queries.AddImageHash(ctx, params)
+	if err != nil {
+		return fmt.Errorf("failed to add image hash: %w", err)
+	}
+
+	return nil
+}
+
+// GetUnhashedImages returns the newest images whose thumbnails haven't been hashed yet
+func (pr *PostRegistry) GetUnhashedImages(ctx context.Context, limit int32) ([]*UnhashedImage, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetUnhashedImages")
+	defer span.End()
+
+	images, err := pr.queries.GetUnhashedImages(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unhashed images: %w", err)
+	}
+
+	if len(images) == 0 {
+		return nil, NotFoundError{fmt.Errorf("no unhashed images found")}
+	}
+
+	retImages := make([]*UnhashedImage, len(images))
+	for i, image := range images {
+		retImages[i] = &UnhashedImage{
+			CID:          image.Cid,
+			PostID:       image.PostID,
+			AuthorDID:    image.AuthorDid,
+			ThumbnailURL: image.ThumbnailUrl,
+			CreatedAt:    image.CreatedAt,
+		}
+	}
+
+	return retImages, nil
+}
+
+// GetSimilarImages returns the images whose pHash is within maxDistance bits of an image's, closest first
+// Candidates must share a 16 bit band of the pHash with the image, so maxDistance can't be more than SimilarImageMaxDistance
+// A NotFoundError is returned if the image hasn't been hashed
+func (pr *PostRegistry) GetSimilarImages(ctx context.Context, cid string, maxDistance int32, limit int32) ([]*SimilarImage, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetSimilarImages")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("image.cid", cid), attribute.Int("max_distance", int(maxDistance)))
+
+	hash, err := pr.queries.GetImageHash(ctx, cid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, NotFoundError{fmt.Errorf("no hash found for image %s", cid)}
+		}
+		return nil, fmt.Errorf("failed to get image hash: %w", err)
+	}
+
+	if !hash.Phash.Valid {
+		return nil, NotFoundError{fmt.Errorf("image %s couldn't be hashed or has a low entropy pHash", cid)}
+	}
+
+	images, err := pr.queries.GetSimilarImages(ctx, search_queries.GetSimilarImagesParams{
+		Cid:         cid,
+		MaxDistance: maxDistance,
+		Limit:       limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get similar images: %w", err)
+	}
+
+	retImages := make([]*SimilarImage, len(images))
+	for i, image := range images {
+		retImages[i] = &SimilarImage{
+			CID:           image.Cid,
+			Distance:      image.Distance,
+			PostCount:     image.PostCount,
+			FirstPostedAt: image.FirstPostedAt,
+			ThumbnailURL:  image.ThumbnailUrl,
+		}
+	}
+
+	return retImages, nil
+}
+
+// GetPostsWithSimilarImages returns the posts since the given time with an image within maxDistance bits of an image, oldest first
+func (pr *PostRegistry) GetPostsWithSimilarImages(ctx context.Context, cid string, maxDistance int32, since time.Time) ([]*DuplicateImagePost, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetPostsWithSimilarImages")
+	defer span.End()
+
+	posts, err := pr.queries.GetPostsWithSimilarImages(ctx, search_queries.GetPostsWithSimilarImagesParams{
+		Cid:         cid,
+		MaxDistance: maxDistance,
+		Since:       since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get posts with similar images: %w", err)
+	}
+
+	retPosts := make([]*DuplicateImagePost, len(posts))
+	for i, post := range posts {
+		retPosts[i] = &DuplicateImagePost{
+			CID:       post.Cid,
+			PostID:    post.PostID,
+			AuthorDID: post.AuthorDid,
+			CreatedAt: post.CreatedAt,
+		}
+	}
+
+	return retPosts, nil
+}
+
+// LabelDuplicateImages labels the posts of an image and its near-duplicates as reposted or viral
+// Only the labels the image's posts add are written, the labels of the posts matched before it are already there
+func (pr *PostRegistry) LabelDuplicateImages(ctx context.Context, cid string, now time.Time) (int, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:LabelDuplicateImages")
+	defer span.End()
+
+	posts, err := pr.GetPostsWithSimilarImages(ctx, cid, DuplicateImageMaxDistance, now.Add(-DuplicateImageWindow))
+	if err != nil {
+		return 0, err
+	}
+
+	labels, postIDs, authorDIDs := newDuplicateImageLabels(posts, cid, ViralImageMinAuthors)
+	if len(labels) == 0 {
+		return 0, nil
+	}
+
+	span.SetAttributes(attribute.Int("labels", len(labels)))
+
+	err = pr.AddOneLabelPerPost(ctx, labels, postIDs, authorDIDs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add duplicate image labels: %w", err)
+	}
+
+	return len(labels), nil
+}
+
+// duplicateImageLabel is a label for one of the posts of an image
+type duplicateImageLabel struct {
+	label     string
+	postID    string
+	authorDID string
+}
+
+// newDuplicateImageLabels returns the labels the posts of an image add to the posts of its near-duplicates
+// Images aren't hashed in the order they're posted, so an image can make older posts reposts or tip every post into viral
+func newDuplicateImageLabels(posts []*DuplicateImagePost, cid string, minViralAuthors int) (labels []string, postIDs []string, authorDIDs []string) {
+	matchedBefore := []*DuplicateImagePost{}
+	for _, post := range posts {
+		if post.CID != cid {
+			matchedBefore = append(matchedBefore, post)
+		}
+	}
+
+	existing := map[duplicateImageLabel]struct{}{}
+	for _, label := range duplicateImageLabels(matchedBefore, minViralAuthors) {
+		existing[label] = struct{}{}
+	}
+
+	for _, label := range duplicateImageLabels(posts, minViralAuthors) {
+		if _, ok := existing[label]; ok {
+			continue
+		}
+		labels = append(labels, label.label)
+		postIDs = append(postIDs, label.postID)
+		authorDIDs = append(authorDIDs, label.authorDID)
+	}
+
+	return labels, postIDs, authorDIDs
+}
+
+// duplicateImageLabels decides which posts of the same image are reposts and whether the image has gone viral
+// A post is a repost if another author posted the image before it, every post is viral once minViralAuthors have posted it
+func duplicateImageLabels(posts []*DuplicateImagePost, minViralAuthors int) []duplicateImageLabel {
+	// A post with more than one of the images is only labeled once
+	sorted := make([]*DuplicateImagePost, 0, len(posts))
+	seen := map[string]struct{}{}
+	for _, post := range posts {
+		if _, ok := seen[post.PostID]; ok {
+			continue
+		}
+		seen[post.PostID] = struct{}{}
+		sorted = append(sorted, post)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	labels := []duplicateImageLabel{}
+	authors := map[string]struct{}{}
+	for _, post := range sorted {
+		_, postedBefore := authors[post.AuthorDID]
+		if len(authors) > 1 || (len(authors) == 1 && !postedBefore) {
+			labels = append(labels, duplicateImageLabel{label: RepostedImageLabel, postID: post.PostID, authorDID: post.AuthorDID})
+		}
+		authors[post.AuthorDID] = struct{}{}
+	}
+
+	if len(authors) >= minViralAuthors {
+		for _, post := range sorted {
+			labels = append(labels, duplicateImageLabel{label: ViralImageLabel, postID: post.PostID, authorDID: post.AuthorDID})
+		}
+	}
+
+	return labels
+}
@@ -0,0 +1,135 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuplicateImageLabels(t *testing.T) {
+	start := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+	post := func(id string, author string, minutes int) *DuplicateImagePost {
+		return &DuplicateImagePost{PostID: id, AuthorDID: author, CreatedAt: start.Add(time.Duration(minutes) * time.Minute)}
+	}
+
+	tests := []struct {
+		name    string
+		posts   []*DuplicateImagePost
+		labels  []string
+		postIDs []string
+	}{
+		{
+			name:  "single post",
+			posts: []*DuplicateImagePost{post("a", "did:alice", 0)},
+		},
+		{
+			name:  "same author posting again",
+			posts: []*DuplicateImagePost{post("a", "did:alice", 0), post("b", "did:alice", 5)},
+		},
+		{
+			name: "reposts by other authors out of order",
+			posts: []*DuplicateImagePost{
+				post("c", "did:carol", 10),
+				post("a", "did:alice", 0),
+				post("b", "did:alice", 5),
+				post("d", "did:alice", 15),
+			},
+			labels:  []string{RepostedImageLabel, RepostedImageLabel},
+			postIDs: []string{"c", "d"},
+		},
+		{
+			name: "viral",
+			posts: []*DuplicateImagePost{
+				post("a", "did:alice", 0),
+				post("b", "did:bob", 1),
+				post("c", "did:carol", 2),
+			},
+			labels:  []string{RepostedImageLabel, RepostedImageLabel, ViralImageLabel, ViralImageLabel, ViralImageLabel},
+			postIDs: []string{"b", "c", "a", "b", "c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labels, postIDs, authorDIDs := newDuplicateImageLabels(tt.posts, "", 3)
+			assert.Equal(t, tt.labels, labels)
+			assert.Equal(t, tt.postIDs, postIDs)
+			assert.Len(t, authorDIDs, len(postIDs))
+		})
+	}
+}
+
+func TestNewDuplicateImageLabels(t *testing.T) {
+	start := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+	post := func(cid string, id string, author string, minutes int) *DuplicateImagePost {
+		return &DuplicateImagePost{CID: cid, PostID: id, AuthorDID: author, CreatedAt: start.Add(time.Duration(minutes) * time.Minute)}
+	}
+
+	tests := []struct {
+		name    string
+		posts   []*DuplicateImagePost
+		labels  []string
+		postIDs []string
+	}{
+		{
+			name: "only the new image's posts are labeled",
+			posts: []*DuplicateImagePost{
+				post("old", "a", "did:alice", 0),
+				post("old", "b", "did:alice", 1),
+				post("new", "c", "did:carol", 2),
+			},
+			labels:  []string{RepostedImageLabel},
+			postIDs: []string{"c"},
+		},
+		{
+			name: "an older post hashed late makes later posts reposts",
+			posts: []*DuplicateImagePost{
+				post("new", "a", "did:alice", 0),
+				post("old", "b", "did:bob", 1),
+				post("old", "c", "did:bob", 2),
+			},
+			labels:  []string{RepostedImageLabel, RepostedImageLabel},
+			postIDs: []string{"b", "c"},
+		},
+		{
+			name: "going viral labels every post",
+			posts: []*DuplicateImagePost{
+				post("old", "a", "did:alice", 0),
+				post("old", "b", "did:bob", 1),
+				post("new", "c", "did:carol", 2),
+				post("new", "d", "did:carol", 3),
+			},
+			labels:  []string{RepostedImageLabel, RepostedImageLabel, ViralImageLabel, ViralImageLabel, ViralImageLabel, ViralImageLabel},
+			postIDs: []string{"c", "d", "a", "b", "c", "d"},
+		},
+		{
+			name: "already viral only labels the new posts",
+			posts: []*DuplicateImagePost{
+				post("old", "a", "did:alice", 0),
+				post("old", "b", "did:bob", 1),
+				post("old", "c", "did:carol", 2),
+				post("new", "d", "did:dave", 3),
+			},
+			labels:  []string{RepostedImageLabel, ViralImageLabel},
+			postIDs: []string{"d", "d"},
+		},
+		{
+			name: "a post with the new and an old image is labeled once",
+			posts: []*DuplicateImagePost{
+				post("old", "a", "did:alice", 0),
+				post("new", "b", "did:bob", 1),
+				post("old", "b", "did:bob", 1),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labels, postIDs, authorDIDs := newDuplicateImageLabels(tt.posts, "new", 3)
+			assert.Equal(t, tt.labels, labels)
+			assert.Equal(t, tt.postIDs, postIDs)
+			assert.Len(t, authorDIDs, len(postIDs))
+		})
+	}
+}
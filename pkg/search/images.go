@@ -223,6 +223,60 @@ func (pr *PostRegistry) GetImagesForPost(ctx context.Context, postID string) ([]
 	return retImages, nil
 }
 
+// GetOldestUnprocessedImageInsertedAt returns when the oldest post with an image that hasn't been through CV was inserted
+func (pr *PostRegistry) GetOldestUnprocessedImageInsertedAt(ctx context.Context) (time.Time, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetOldestUnprocessedImageInsertedAt")
+	defer span.End()
+
+	insertedAt, err := pr.queries.GetOldestUnprocessedImageInsertedAt(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return time.Time{}, NotFoundError{fmt.Errorf("no unprocessed images found")}
+		}
+		return time.Time{}, fmt.Errorf("failed to get oldest unprocessed image: %w", err)
+	}
+
+	return insertedAt.Time, nil
+}
+
+// GetImagesForPosts returns the images of a batch of posts
+func (pr *PostRegistry) GetImagesForPosts(ctx context.Context, postIDs []string) ([]*Image, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetImagesForPosts")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("post_count", len(postIDs)))
+
+	images, err := pr.queries.GetImagesForPosts(ctx, postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get images for posts: %w", err)
+	}
+
+	retImages := make([]*Image, len(images))
+	for i, image := range images {
+		retImages[i] = &Image{
+			CID:          image.Cid,
+			PostID:       image.PostID,
+			AuthorDID:    image.AuthorDid,
+			AltText:      ptrFromNullString(image.AltText),
+			MimeType:     image.MimeType,
+			FullsizeURL:  image.FullsizeUrl,
+			ThumbnailURL: image.ThumbnailUrl,
+			CreatedAt:    image.CreatedAt,
+			CVCompleted:  image.CvCompleted,
+		}
+		if image.CvRunAt.Valid {
+			retImages[i].CVRunAt = &images[i].CvRunAt.Time
+		}
+		if image.CvClasses.Valid {
+			retImages[i].CVClasses = &images[i].CvClasses.RawMessage
+		}
+	}
+
+	return retImages, nil
+}
+
 func (pr *PostRegistry) GetUnprocessedImages(ctx context.Context, limit int32) ([]*Image, error) {
 	tracer := otel.Tracer("post-registry")
 	ctx, span := tracer.Start(ctx, "PostRegistry:GetUnprocessedImages")
@@ -62,6 +62,18 @@ func (pr *PostRegistry) AddOneLabelPerPost(ctx context.Context, labels []string,
 	return err
 }
 
+// DeletePostLabelsInsertedSince removes a label from the posts inserted at or after since
+func (pr *PostRegistry) DeletePostLabelsInsertedSince(ctx context.Context, label string, since time.Time) error {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:DeletePostLabelsInsertedSince")
+	defer span.End()
+
+	return pr.queries.DeletePostLabelsInsertedSince(ctx, search_queries.DeletePostLabelsInsertedSinceParams{
+		Label:      label,
+		InsertedAt: since,
+	})
+}
+
 func (pr *PostRegistry) CreateLabel(ctx context.Context, labelAlias string, labelName string) (*Label, error) {
 	tracer := otel.Tracer("post-registry")
 	ctx, span := tracer.Start(ctx, "PostRegistry:CreateLabel")
@@ -516,6 +528,49 @@ func (pr *PostRegistry) GetPostsPageForPostLabelChronological(
 	return retPosts, nil
 }
 
+func (pr *PostRegistry) GetPostsPageForPostLabelsChronological(
+	ctx context.Context,
+	postLabels []string,
+	limit int32,
+	cursor time.Time,
+) ([]*Post, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetPostsPageForPostLabelsChronological")
+	defer span.End()
+
+	posts, err := pr.queries.GetPostsPageWithAnyPostLabelChronological(ctx, search_queries.GetPostsPageWithAnyPostLabelChronologicalParams{
+		Labels: postLabels,
+		Limit:  limit,
+		Cursor: cursor,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, NotFoundError{fmt.Errorf("posts not found")}
+		}
+		return nil, err
+	}
+
+	retPosts := make([]*Post, len(posts))
+	for i, p := range posts {
+		retPosts[i] = &Post{
+			ID:                 p.ID,
+			Text:               p.Text,
+			ParentPostID:       ptrFromNullString(p.ParentPostID),
+			RootPostID:         ptrFromNullString(p.RootPostID),
+			AuthorDID:          p.AuthorDid,
+			CreatedAt:          p.CreatedAt,
+			HasEmbeddedMedia:   p.HasEmbeddedMedia,
+			ParentRelationship: ptrFromNullString(p.ParentRelationship),
+			Sentiment:          ptrFromNullString(p.Sentiment),
+		}
+		if p.SentimentConfidence.Valid {
+			retPosts[i].SentimentConfidence = &posts[i].SentimentConfidence.Float64
+		}
+	}
+
+	return retPosts, nil
+}
+
 func (pr *PostRegistry) GetUniquePostLabels(ctx context.Context) ([]string, error) {
 	tracer := otel.Tracer("post-registry")
 	ctx, span := tracer.Start(ctx, "PostRegistry:GetUniquePostLabels")
@@ -0,0 +1,198 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search/search_queries"
+	"go.opentelemetry.io/otel"
+)
+
+// ModListPurpose is the purpose of lists meant for moderation (muting or blocking their members)
+const ModListPurpose = "app.bsky.graph.defs#modlist"
+
+type List struct {
+	URI         string    `json:"uri"`
+	CreatorDID  string    `json:"creator_did"`
+	Name        string    `json:"name"`
+	Purpose     string    `json:"purpose"`
+	Description *string   `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type ListItem struct {
+	URI        string    `json:"uri"`
+	CreatorDID string    `json:"creator_did"`
+	ListURI    string    `json:"list_uri"`
+	SubjectDID string    `json:"subject_did"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ListMember struct {
+	DID       string    `json:"did"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func listFromRow(row search_queries.List) *List {
+	return &List{
+		URI:         row.Uri,
+		CreatorDID:  row.CreatorDid,
+		Name:        row.Name,
+		Purpose:     row.Purpose,
+		Description: ptrFromNullString(row.Description),
+		CreatedAt:   row.CreatedAt,
+	}
+}
+
+func (pr *PostRegistry) UpsertList(ctx context.Context, list *List) error {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:UpsertList")
+	defer span.End()
+
+	err := pr.queries.UpsertList(ctx, search_queries.UpsertListParams{
+		Uri:         list.URI,
+		CreatorDid:  list.CreatorDID,
+		Name:        list.Name,
+		Purpose:     list.Purpose,
+		Description: nullStringFromPtr(list.Description),
+		CreatedAt:   list.CreatedAt,
+	})
+	return err
+}
+
+func (pr *PostRegistry) DeleteList(ctx context.Context, uri string) error {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:DeleteList")
+	defer span.End()
+
+	tx, err := pr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := pr.queries.WithTx(tx)
+
+	// The list's items go with it so subscribers stop muting its subjects
+	err = qtx.DeleteListItemsForList(ctx, uri)
+	if err != nil {
+		return fmt.Errorf("error deleting list items: %w", err)
+	}
+
+	err = qtx.DeleteList(ctx, uri)
+	if err != nil {
+		return fmt.Errorf("error deleting list: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (pr *PostRegistry) AddListItem(ctx context.Context, item *ListItem) error {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:AddListItem")
+	defer span.End()
+
+	err := pr.queries.AddListItem(ctx, search_queries.AddListItemParams{
+		Uri:        item.URI,
+		CreatorDid: item.CreatorDID,
+		ListUri:    item.ListURI,
+		SubjectDid: item.SubjectDID,
+		CreatedAt:  item.CreatedAt,
+	})
+	return err
+}
+
+func (pr *PostRegistry) DeleteListItem(ctx context.Context, uri string) error {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:DeleteListItem")
+	defer span.End()
+
+	return pr.queries.DeleteListItem(ctx, uri)
+}
+
+func (pr *PostRegistry) GetList(ctx context.Context, uri string) (*List, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetList")
+	defer span.End()
+
+	list, err := pr.queries.GetList(ctx, uri)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, NotFoundError{fmt.Errorf("list not found")}
+		}
+		return nil, err
+	}
+
+	return listFromRow(list), nil
+}
+
+func (pr *PostRegistry) GetListMembers(ctx context.Context, listURI string, limit int32, offset int32) ([]*ListMember, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetListMembers")
+	defer span.End()
+
+	members, err := pr.queries.GetListMembers(ctx, search_queries.GetListMembersParams{
+		ListUri: listURI,
+		Limit:   limit,
+		Offset:  offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get list members: %w", err)
+	}
+
+	retMembers := make([]*ListMember, len(members))
+	for i, member := range members {
+		retMembers[i] = &ListMember{
+			DID:       member.SubjectDid,
+			CreatedAt: member.CreatedAt,
+		}
+	}
+
+	return retMembers, nil
+}
+
+func (pr *PostRegistry) GetListsForAuthor(ctx context.Context, did string, limit int32, offset int32) ([]*List, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetListsForAuthor")
+	defer span.End()
+
+	lists, err := pr.queries.GetListsForAuthor(ctx, search_queries.GetListsForAuthorParams{
+		SubjectDid: did,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lists for author: %w", err)
+	}
+
+	retLists := make([]*List, len(lists))
+	for i, list := range lists {
+		retLists[i] = listFromRow(list)
+	}
+
+	return retLists, nil
+}
+
+// GetListedSubjects returns the subset of the given DIDs that are members of any of the given lists
+func (pr *PostRegistry) GetListedSubjects(ctx context.Context, listURIs []string, dids []string) (map[string]struct{}, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetListedSubjects")
+	defer span.End()
+
+	listed, err := pr.queries.GetListedSubjects(ctx, search_queries.GetListedSubjectsParams{
+		ListUris:    listURIs,
+		SubjectDids: dids,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get listed subjects: %w", err)
+	}
+
+	listedSet := make(map[string]struct{}, len(listed))
+	for _, did := range listed {
+		listedSet[did] = struct{}{}
+	}
+
+	return listedSet, nil
+}
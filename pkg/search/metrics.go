@@ -0,0 +1,31 @@
+package search
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var batchWriterFlushedRowsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "search_batch_writer_flushed_rows_total",
+	Help: "The total number of rows written by the batch writer by table",
+}, []string{"table"})
+
+var batchWriterFailedRowsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "search_batch_writer_failed_rows_total",
+	Help: "The total number of rows dropped by the batch writer after a failed write by table",
+}, []string{"table"})
+
+var batchWriterPendingGauge = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "search_batch_writer_pending_rows",
+	Help: "The number of writes buffered in the batch writer waiting to be flushed",
+})
+
+var batchWriterBlockedWritesCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "search_batch_writer_blocked_writes_total",
+	Help: "The total number of times a write waited for room in a full batch writer",
+})
+
+var batchWriterFlushDurationHistogram = promauto.NewHistogram(prometheus.HistogramOpts{
+	Name: "search_batch_writer_flush_duration_seconds",
+	Help: "Histogram of the time (in seconds) each batch writer flush takes",
+})
@@ -0,0 +1,174 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search/search_queries"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type PostMention struct {
+	PostID       string    `json:"post_id"`
+	AuthorDID    string    `json:"author_did"`
+	MentionedDID string    `json:"mentioned_did"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type PostLink struct {
+	PostID    string    `json:"post_id"`
+	AuthorDID string    `json:"author_did"`
+	URL       string    `json:"url"`
+	Domain    string    `json:"domain"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PostTag is a tag from a post's tag facets, it's stored as written on the record
+type PostTag struct {
+	PostID    string    `json:"post_id"`
+	AuthorDID string    `json:"author_did"`
+	Tag       string    `json:"tag"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type LinkDomain struct {
+	Domain      string `json:"domain"`
+	LinkCount   int64  `json:"link_count"`
+	AuthorCount int64  `json:"author_count"`
+}
+
+type LinkingPost struct {
+	PostID    string    `json:"post_id"`
+	AuthorDID string    `json:"author_did"`
+	URL       string    `json:"url"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DomainFromURL returns the lowercased host of an http(s) URL without a leading "www."
+func DomainFromURL(rawURL string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", false
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", false
+	}
+
+	domain := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if domain == "" {
+		return "", false
+	}
+
+	return domain, true
+}
+
+// GetTagsForPosts returns the tags of a batch of posts keyed by post ID
+func (pr *PostRegistry) GetTagsForPosts(ctx context.Context, postIDs []string) (map[string][]string, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetTagsForPosts")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("post_count", len(postIDs)))
+
+	tags, err := pr.queries.GetTagsForPosts(ctx, postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags for posts: %w", err)
+	}
+
+	retTags := map[string][]string{}
+	for _, tag := range tags {
+		retTags[tag.PostID] = append(retTags[tag.PostID], tag.Tag)
+	}
+
+	return retTags, nil
+}
+
+// GetTopLinkDomains returns the domains linked to most often since the given time
+func (pr *PostRegistry) GetTopLinkDomains(ctx context.Context, since time.Time, limit int32) ([]*LinkDomain, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetTopLinkDomains")
+	defer span.End()
+
+	domains, err := pr.queries.GetTopLinkDomains(ctx, search_queries.GetTopLinkDomainsParams{
+		Since: since,
+		Limit: limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top link domains: %w", err)
+	}
+
+	retDomains := make([]*LinkDomain, len(domains))
+	for i, domain := range domains {
+		retDomains[i] = &LinkDomain{
+			Domain:      domain.Domain,
+			LinkCount:   domain.LinkCount,
+			AuthorCount: domain.AuthorCount,
+		}
+	}
+
+	return retDomains, nil
+}
+
+// GetPostsLinkingToURL returns a page of the posts that link to a URL, newest first
+func (pr *PostRegistry) GetPostsLinkingToURL(ctx context.Context, linkURL string, limit int32, offset int32) ([]*LinkingPost, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetPostsLinkingToURL")
+	defer span.End()
+
+	posts, err := pr.queries.GetPostsLinkingToURL(ctx, search_queries.GetPostsLinkingToURLParams{
+		Url:    linkURL,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get posts linking to URL: %w", err)
+	}
+
+	retPosts := make([]*LinkingPost, len(posts))
+	for i, post := range posts {
+		retPosts[i] = &LinkingPost{
+			PostID:    post.PostID,
+			AuthorDID: post.AuthorDid,
+			URL:       post.Url,
+			Text:      post.Text,
+			CreatedAt: post.CreatedAt,
+		}
+	}
+
+	return retPosts, nil
+}
+
+// GetPostsLinkingToDomain returns a page of the posts that link to a domain, newest first
+func (pr *PostRegistry) GetPostsLinkingToDomain(ctx context.Context, domain string, limit int32, offset int32) ([]*LinkingPost, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetPostsLinkingToDomain")
+	defer span.End()
+
+	posts, err := pr.queries.GetPostsLinkingToDomain(ctx, search_queries.GetPostsLinkingToDomainParams{
+		Domain: domain,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get posts linking to domain: %w", err)
+	}
+
+	retPosts := make([]*LinkingPost, len(posts))
+	for i, post := range posts {
+		retPosts[i] = &LinkingPost{
+			PostID:    post.PostID,
+			AuthorDID: post.AuthorDid,
+			URL:       post.Url,
+			Text:      post.Text,
+			CreatedAt: post.CreatedAt,
+		}
+	}
+
+	return retPosts, nil
+}
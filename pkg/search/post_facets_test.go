@@ -0,0 +1,32 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainFromURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		domain string
+		ok     bool
+	}{
+		{"https", "https://example.com/a/b?c=d", "example.com", true},
+		{"http with port", "http://example.com:8080/", "example.com", true},
+		{"www is stripped", "https://www.Example.COM/page", "example.com", true},
+		{"subdomains are kept", "https://news.example.co.uk", "news.example.co.uk", true},
+		{"mailto", "mailto:someone@example.com", "", false},
+		{"no scheme", "example.com/page", "", false},
+		{"no host", "https:///page", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			domain, ok := DomainFromURL(tt.url)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.domain, domain)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search/search_queries"
+	"go.opentelemetry.io/otel"
+)
+
+// LanguageLabelPrefix prefixes the post labels applied by language detection, i.e. "lang:en"
+const LanguageLabelPrefix = "lang:"
+
+// MinDetectedLangConfidence is the confidence a detected language needs before it's trusted over the declared languages
+const MinDetectedLangConfidence = 0.5
+
+// PostLanguage holds the languages a post declares in its record and the language detected from its text
+// Languages are lowercase ISO 639-1 codes
+type PostLanguage struct {
+	PostID             string    `json:"post_id"`
+	AuthorDID          string    `json:"author_did"`
+	DeclaredLangs      []string  `json:"declared_langs"`
+	DetectedLang       *string   `json:"detected_lang"`
+	DetectedConfidence *float64  `json:"detected_confidence"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// LanguageLabel returns the post label for a language
+func LanguageLabel(lang string) string {
+	return LanguageLabelPrefix + lang
+}
+
+// Matches returns true if the post is written in one of the given languages
+// A confident detected language is trusted over the declared languages since most clients declare
+// the user's default language on every post, posts in an unknown language always match
+func (pl *PostLanguage) Matches(langs []string) bool {
+	if pl == nil || len(langs) == 0 {
+		return true
+	}
+
+	if pl.DetectedLang != nil && pl.DetectedConfidence != nil && *pl.DetectedConfidence >= MinDetectedLangConfidence {
+		return containsLang(langs, *pl.DetectedLang)
+	}
+
+	if len(pl.DeclaredLangs) == 0 {
+		return true
+	}
+
+	for _, lang := range pl.DeclaredLangs {
+		if containsLang(langs, lang) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsLang(langs []string, lang string) bool {
+	for _, l := range langs {
+		if strings.EqualFold(l, lang) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetDetectedLanguages stores the detected language of a batch of posts
+// A nil DetectedLang clears the detection for that post
+func (pr *PostRegistry) SetDetectedLanguages(ctx context.Context, langs []*PostLanguage) error {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:SetDetectedLanguages")
+	defer span.End()
+
+	params := search_queries.SetPostDetectedLangsParams{
+		PostIds:             make([]string, len(langs)),
+		AuthorDids:          make([]string, len(langs)),
+		DetectedLangs:       make([]string, len(langs)),
+		DetectedConfidences: make([]float64, len(langs)),
+		CreatedAts:          make([]time.Time, len(langs)),
+	}
+
+	for i, lang := range langs {
+		params.PostIds[i] = lang.PostID
+		params.AuthorDids[i] = lang.AuthorDID
+		params.CreatedAts[i] = lang.CreatedAt
+
+		if lang.DetectedLang != nil {
+			params.DetectedLangs[i] = *lang.DetectedLang
+		}
+		if lang.DetectedConfidence != nil {
+			params.DetectedConfidences[i] = *lang.DetectedConfidence
+		}
+	}
+
+	err := pr.queries.SetPostDetectedLangs(ctx, params)
+	if err != nil {
+		return fmt.Errorf("failed to set detected languages: %w", err)
+	}
+
+	return nil
+}
+
+// GetPostLanguages returns the languages of the given posts keyed by post ID
+// Posts without any language information are left out
+func (pr *PostRegistry) GetPostLanguages(ctx context.Context, postIDs []string) (map[string]*PostLanguage, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetPostLanguages")
+	defer span.End()
+
+	rows, err := pr.queries.GetPostLanguages(ctx, postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post languages: %w", err)
+	}
+
+	langs := make(map[string]*PostLanguage, len(rows))
+	for _, row := range rows {
+		lang := &PostLanguage{
+			PostID:        row.PostID,
+			AuthorDID:     row.AuthorDid,
+			DeclaredLangs: row.DeclaredLangs,
+			DetectedLang:  ptrFromNullString(row.DetectedLang),
+			CreatedAt:     row.CreatedAt,
+		}
+		if row.DetectedConfidence.Valid {
+			confidence := row.DetectedConfidence.Float64
+			lang.DetectedConfidence = &confidence
+		}
+		langs[row.PostID] = lang
+	}
+
+	return langs, nil
+}
@@ -0,0 +1,34 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostLanguageMatches(t *testing.T) {
+	en := "en"
+	high, low := 0.9, 0.2
+
+	tests := []struct {
+		name  string
+		lang  *PostLanguage
+		langs []string
+		match bool
+	}{
+		{"no filter", &PostLanguage{DetectedLang: &en, DetectedConfidence: &high}, nil, true},
+		{"unknown post", nil, []string{"ja"}, true},
+		{"nothing detected or declared", &PostLanguage{}, []string{"ja"}, true},
+		{"detected match", &PostLanguage{DetectedLang: &en, DetectedConfidence: &high}, []string{"ja", "en"}, true},
+		{"detected wins over declared", &PostLanguage{DetectedLang: &en, DetectedConfidence: &high, DeclaredLangs: []string{"ja"}}, []string{"ja"}, false},
+		{"unconfident detection falls back to declared", &PostLanguage{DetectedLang: &en, DetectedConfidence: &low, DeclaredLangs: []string{"ja"}}, []string{"ja"}, true},
+		{"declared match", &PostLanguage{DeclaredLangs: []string{"pt", "es"}}, []string{"es"}, true},
+		{"declared mismatch", &PostLanguage{DeclaredLangs: []string{"pt"}}, []string{"en"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.match, tt.lang.Matches(tt.langs))
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search/search_queries"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// PostSearchQuery is a full-text post search, empty filters are ignored
+type PostSearchQuery struct {
+	Query     string
+	AuthorDID string
+	Label     string
+	ClusterID *int32
+	Since     *time.Time
+	Until     *time.Time
+	Lang      string
+	Limit     int32
+	Offset    int32
+}
+
+// IndexPostSearchVectors makes a batch of posts searchable by SearchPosts
+func (pr *PostRegistry) IndexPostSearchVectors(ctx context.Context, postIDs []string) error {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:IndexPostSearchVectors")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("posts.count", len(postIDs)))
+
+	return pr.queries.IndexPostSearchVectors(ctx, postIDs)
+}
+
+// SearchPosts returns a page of the posts matching a full-text query, best matches first
+// Posts that haven't had their search vectors indexed yet are still matched by trigram similarity
+func (pr *PostRegistry) SearchPosts(ctx context.Context, query PostSearchQuery) ([]*Post, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:SearchPosts")
+	defer span.End()
+
+	params := search_queries.SearchPostsParams{
+		Query:                 query.Query,
+		AuthorDid:             sql.NullString{String: query.AuthorDID, Valid: query.AuthorDID != ""},
+		Label:                 sql.NullString{String: query.Label, Valid: query.Label != ""},
+		Lang:                  sql.NullString{String: query.Lang, Valid: query.Lang != ""},
+		MinDetectedConfidence: MinDetectedLangConfidence,
+		Limit:                 query.Limit,
+		Offset:                query.Offset,
+	}
+	if query.ClusterID != nil {
+		params.ClusterID = sql.NullInt32{Int32: *query.ClusterID, Valid: true}
+	}
+	if query.Since != nil {
+		params.Since = sql.NullTime{Time: *query.Since, Valid: true}
+	}
+	if query.Until != nil {
+		params.Until = sql.NullTime{Time: *query.Until, Valid: true}
+	}
+
+	rows, err := pr.queries.SearchPosts(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search posts: %w", err)
+	}
+
+	posts := make([]*Post, len(rows))
+	for i, row := range rows {
+		posts[i] = &Post{
+			ID:                 row.ID,
+			Text:               row.Text,
+			ParentPostID:       ptrFromNullString(row.ParentPostID),
+			RootPostID:         ptrFromNullString(row.RootPostID),
+			AuthorDID:          row.AuthorDid,
+			AuthorHandle:       &rows[i].Handle,
+			CreatedAt:          row.CreatedAt,
+			HasEmbeddedMedia:   row.HasEmbeddedMedia,
+			ParentRelationship: ptrFromNullString(row.ParentRelationship),
+			Sentiment:          ptrFromNullString(row.Sentiment),
+			SearchRank:         &rows[i].Rank,
+		}
+		if row.SentimentConfidence.Valid {
+			posts[i].SentimentConfidence = &rows[i].SentimentConfidence.Float64
+		}
+	}
+
+	span.SetAttributes(attribute.Int("results.count", len(posts)))
+
+	return posts, nil
+}
+
+// GetClustersForAuthors returns the cluster IDs of the given authors, authors without a cluster are left out
+func (pr *PostRegistry) GetClustersForAuthors(ctx context.Context, authorDIDs []string) (map[string]int32, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetClustersForAuthors")
+	defer span.End()
+
+	authorClusters, err := pr.queries.GetClustersForAuthors(ctx, authorDIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clusters for authors: %w", err)
+	}
+
+	clusters := make(map[string]int32, len(authorClusters))
+	for _, authorCluster := range authorClusters {
+		clusters[authorCluster.AuthorDid] = authorCluster.ClusterID
+	}
+
+	return clusters, nil
+}
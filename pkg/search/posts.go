@@ -39,8 +39,10 @@ type Post struct {
 	SentimentConfidence *float64   `json:"sentiment_confidence"`
 	Images              []*Image   `json:"images,omitempty"`
 	Hotness             *float64   `json:"hotness,omitempty"`
+	SearchRank          *float64   `json:"search_rank,omitempty"`
 	Labels              []string   `json:"labels,omitempty"`
 	IndexedAt           *time.Time `json:"indexed_at,omitempty"`
+	InsertedAt          *time.Time `json:"inserted_at,omitempty"`
 }
 
 type PostView struct {
@@ -204,6 +206,24 @@ func (pr *PostRegistry) SetIndexedAtTimestamp(ctx context.Context, postIDs []str
 	return err
 }
 
+// BackfillPostsInsertedAt stamps posts from before inserted_at existed with when they were created, one batch at a time in ID order
+// It returns the cursor for the next batch, which is empty once every post has been visited
+func (pr *PostRegistry) BackfillPostsInsertedAt(ctx context.Context, cursorPostID string, limit int32) (string, error) {
+	tracer := otel.Tracer("PostRegistry")
+	ctx, span := tracer.Start(ctx, "BackfillPostsInsertedAt")
+	defer span.End()
+
+	nextCursor, err := pr.queries.BackfillPostsInsertedAt(ctx, search_queries.BackfillPostsInsertedAtParams{
+		CursorPostID: cursorPostID,
+		Limit:        limit,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to backfill posts inserted_at: %w", err)
+	}
+
+	return nextCursor, nil
+}
+
 func (pr *PostRegistry) GetPostWithAuthorHandle(ctx context.Context, postID string) (*Post, error) {
 	tracer := otel.Tracer("PostRegistry")
 	ctx, span := tracer.Start(ctx, "GetPostWithAuthorHandle")
@@ -0,0 +1,107 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search/search_queries"
+	"go.opentelemetry.io/otel"
+)
+
+type AuthorProfile struct {
+	DID         string    `json:"did"`
+	DisplayName *string   `json:"display_name"`
+	Description *string   `json:"description"`
+	AvatarCID   *string   `json:"avatar_cid"`
+	BannerCID   *string   `json:"banner_cid"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func nullStringFromPtr(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+func ptrFromNullString(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
+
+func authorProfileFromRow(row search_queries.AuthorProfile) *AuthorProfile {
+	return &AuthorProfile{
+		DID:         row.Did,
+		DisplayName: ptrFromNullString(row.DisplayName),
+		Description: ptrFromNullString(row.Description),
+		AvatarCID:   ptrFromNullString(row.AvatarCid),
+		BannerCID:   ptrFromNullString(row.BannerCid),
+		UpdatedAt:   row.UpdatedAt,
+	}
+}
+
+func (pr *PostRegistry) UpsertAuthorProfile(ctx context.Context, profile *AuthorProfile) error {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:UpsertAuthorProfile")
+	defer span.End()
+
+	err := pr.queries.UpsertAuthorProfile(ctx, search_queries.UpsertAuthorProfileParams{
+		Did:         profile.DID,
+		DisplayName: nullStringFromPtr(profile.DisplayName),
+		Description: nullStringFromPtr(profile.Description),
+		AvatarCid:   nullStringFromPtr(profile.AvatarCID),
+		BannerCid:   nullStringFromPtr(profile.BannerCID),
+		UpdatedAt:   profile.UpdatedAt,
+	})
+	return err
+}
+
+func (pr *PostRegistry) DeleteAuthorProfile(ctx context.Context, did string) error {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:DeleteAuthorProfile")
+	defer span.End()
+
+	return pr.queries.DeleteAuthorProfile(ctx, did)
+}
+
+func (pr *PostRegistry) GetAuthorProfile(ctx context.Context, did string) (*AuthorProfile, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetAuthorProfile")
+	defer span.End()
+
+	profile, err := pr.queries.GetAuthorProfile(ctx, did)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, NotFoundError{fmt.Errorf("author profile not found")}
+		}
+		return nil, err
+	}
+
+	return authorProfileFromRow(profile), nil
+}
+
+// GetAuthorProfilePage returns up to limit profiles with DIDs after the cursor DID
+func (pr *PostRegistry) GetAuthorProfilePage(ctx context.Context, cursor string, limit int32) ([]*AuthorProfile, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetAuthorProfilePage")
+	defer span.End()
+
+	profiles, err := pr.queries.GetAuthorProfilePage(ctx, search_queries.GetAuthorProfilePageParams{
+		Did:   cursor,
+		Limit: limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get author profile page: %w", err)
+	}
+
+	retProfiles := make([]*AuthorProfile, len(profiles))
+	for i, profile := range profiles {
+		retProfiles[i] = authorProfileFromRow(profile)
+	}
+
+	return retProfiles, nil
+}
@@ -0,0 +1,5 @@
+-- name: GetClustersForAuthors :many
+SELECT author_did,
+    cluster_id
+FROM author_clusters
+WHERE author_did = ANY(sqlc.arg('author_dids')::text []);
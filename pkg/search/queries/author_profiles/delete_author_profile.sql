@@ -0,0 +1,2 @@
+-- name: DeleteAuthorProfile :exec
+DELETE FROM author_profiles WHERE did = $1;
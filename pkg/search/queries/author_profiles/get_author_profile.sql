@@ -0,0 +1,4 @@
+-- name: GetAuthorProfile :one
+SELECT *
+FROM author_profiles
+WHERE did = $1;
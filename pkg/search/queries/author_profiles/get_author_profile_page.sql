@@ -0,0 +1,7 @@
+-- name: GetAuthorProfilePage :many
+-- GetAuthorProfilePage returns a page of profiles ordered by DID for exports.
+SELECT *
+FROM author_profiles
+WHERE did > $1
+ORDER BY did ASC
+LIMIT $2;
@@ -0,0 +1,18 @@
+-- name: UpsertAuthorProfile :exec
+-- UpsertAuthorProfile stores an author's profile, a profile from an older event never overwrites a newer one.
+INSERT INTO author_profiles (
+        did,
+        display_name,
+        description,
+        avatar_cid,
+        banner_cid,
+        updated_at
+    )
+VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (did) DO
+UPDATE
+SET display_name = EXCLUDED.display_name,
+    description = EXCLUDED.description,
+    avatar_cid = EXCLUDED.avatar_cid,
+    banner_cid = EXCLUDED.banner_cid,
+    updated_at = EXCLUDED.updated_at
+WHERE author_profiles.updated_at <= EXCLUDED.updated_at;
@@ -0,0 +1,2 @@
+-- name: AddAuthorTombstone :exec
+INSERT INTO author_tombstones (did, tombstoned_at) VALUES ($1, $2) ON CONFLICT (did) DO NOTHING;
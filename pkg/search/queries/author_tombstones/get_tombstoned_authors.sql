@@ -0,0 +1,5 @@
+-- name: GetTombstonedAuthors :many
+-- GetTombstonedAuthors returns the DIDs in the given list that have been tombstoned.
+SELECT did
+FROM author_tombstones
+WHERE did = ANY(sqlc.arg('dids')::text []);
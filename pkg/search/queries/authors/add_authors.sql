@@ -0,0 +1,11 @@
+-- name: AddAuthors :exec
+-- AddAuthors upserts a batch of authors, DIDs must be unique within the batch.
+INSERT INTO authors (did, handle)
+SELECT did,
+    handle
+FROM unnest(
+        sqlc.arg('dids')::text [],
+        sqlc.arg('handles')::text []
+    ) AS a (did, handle) ON CONFLICT (did) DO
+UPDATE
+SET handle = EXCLUDED.handle;
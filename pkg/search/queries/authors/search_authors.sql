@@ -0,0 +1,36 @@
+-- name: SearchAuthors :many
+-- SearchAuthors returns authors whose handle or display name starts with or is similar to the query.
+-- The prefix is a LIKE pattern built from the query.
+WITH matches AS (
+    -- Each table is matched on its own so the trigram indexes on handle and display_name can be used
+    SELECT did
+    FROM authors
+    WHERE handle ILIKE sqlc.arg('prefix')::text
+        OR handle % sqlc.arg('query')::text
+    UNION
+    SELECT did
+    FROM author_profiles
+    WHERE display_name ILIKE sqlc.arg('prefix')::text
+        OR display_name % sqlc.arg('query')::text
+)
+SELECT a.did,
+    a.handle,
+    p.display_name,
+    COALESCE(
+        a.handle ILIKE sqlc.arg('prefix')::text
+        OR p.display_name ILIKE sqlc.arg('prefix')::text,
+        FALSE
+    )::bool AS prefix_match,
+    GREATEST(
+        similarity(a.handle, sqlc.arg('query')::text),
+        COALESCE(
+            similarity(p.display_name, sqlc.arg('query')::text),
+            0
+        )
+    )::float8 AS similarity
+FROM matches m
+    JOIN authors a ON a.did = m.did
+    LEFT JOIN author_profiles p ON p.did = a.did
+ORDER BY prefix_match DESC,
+    similarity DESC
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,4 @@
+-- name: UpdateAuthorHandle :exec
+UPDATE authors
+SET handle = $2
+WHERE did = $1;
@@ -0,0 +1,11 @@
+-- name: GetClassifierProgress :one
+SELECT classifier,
+    cursor_inserted_at,
+    cursor_post_id,
+    processed_count,
+    labeled_count,
+    failed_count,
+    last_error,
+    updated_at
+FROM classifier_progress
+WHERE classifier = $1;
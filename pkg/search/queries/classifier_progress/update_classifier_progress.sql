@@ -0,0 +1,30 @@
+-- name: UpdateClassifierProgress :exec
+-- UpdateClassifierProgress moves a classifier's cursor and adds to its counts.
+INSERT INTO classifier_progress (
+        classifier,
+        cursor_inserted_at,
+        cursor_post_id,
+        processed_count,
+        labeled_count,
+        failed_count,
+        last_error,
+        updated_at
+    )
+VALUES (
+        sqlc.arg('classifier'),
+        sqlc.arg('cursor_inserted_at'),
+        sqlc.arg('cursor_post_id'),
+        sqlc.arg('processed'),
+        sqlc.arg('labeled'),
+        sqlc.arg('failed'),
+        sqlc.narg('last_error'),
+        NOW()
+    ) ON CONFLICT (classifier) DO
+UPDATE
+SET cursor_inserted_at = EXCLUDED.cursor_inserted_at,
+    cursor_post_id = EXCLUDED.cursor_post_id,
+    processed_count = classifier_progress.processed_count + EXCLUDED.processed_count,
+    labeled_count = classifier_progress.labeled_count + EXCLUDED.labeled_count,
+    failed_count = classifier_progress.failed_count + EXCLUDED.failed_count,
+    last_error = COALESCE(EXCLUDED.last_error, classifier_progress.last_error),
+    updated_at = EXCLUDED.updated_at;
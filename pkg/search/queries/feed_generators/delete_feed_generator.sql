@@ -0,0 +1,2 @@
+-- name: DeleteFeedGenerator :exec
+DELETE FROM feed_generators WHERE uri = $1;
@@ -0,0 +1,15 @@
+-- name: GetFeedGenerator :one
+SELECT uri,
+    creator_did,
+    rkey,
+    display_name,
+    description,
+    service_did,
+    like_count,
+    healthy,
+    health_error,
+    last_checked_at,
+    created_at,
+    updated_at
+FROM feed_generators
+WHERE uri = $1;
@@ -0,0 +1,19 @@
+-- name: GetFeedGeneratorsToCheck :many
+-- GetFeedGeneratorsToCheck returns the feed generators that have gone the longest without a health check.
+SELECT uri,
+    creator_did,
+    rkey,
+    display_name,
+    description,
+    service_did,
+    like_count,
+    healthy,
+    health_error,
+    last_checked_at,
+    created_at,
+    updated_at
+FROM feed_generators
+WHERE last_checked_at IS NULL
+    OR last_checked_at < sqlc.arg('checked_before')::timestamptz
+ORDER BY last_checked_at ASC NULLS FIRST
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,14 @@
+-- name: IncrementFeedGeneratorLikeCount :exec
+-- IncrementFeedGeneratorLikeCount counts a like of a feed generator, likes of feed generators that haven't been indexed yet are kept for when they are.
+WITH counted AS (
+    INSERT INTO feed_generator_likes (uri, like_count)
+    VALUES ($1, 1) ON CONFLICT (uri) DO
+    UPDATE
+    SET like_count = feed_generator_likes.like_count + 1
+    RETURNING uri,
+        like_count
+)
+UPDATE feed_generators fg
+SET like_count = counted.like_count
+FROM counted
+WHERE fg.uri = counted.uri;
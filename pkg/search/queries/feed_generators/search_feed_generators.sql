@@ -0,0 +1,22 @@
+-- name: SearchFeedGenerators :many
+-- SearchFeedGenerators returns a page of feed generators whose name or description match the query.
+-- An empty query matches every feed generator, the most liked feed generators are returned first.
+SELECT uri,
+    creator_did,
+    rkey,
+    display_name,
+    description,
+    service_did,
+    like_count,
+    healthy,
+    health_error,
+    last_checked_at,
+    created_at,
+    updated_at
+FROM feed_generators
+WHERE sqlc.arg('query')::text = ''
+    OR display_name ILIKE '%' || sqlc.arg('query')::text || '%'
+    OR description ILIKE '%' || sqlc.arg('query')::text || '%'
+ORDER BY like_count DESC,
+    uri ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
@@ -0,0 +1,6 @@
+-- name: UpdateFeedGeneratorHealth :exec
+UPDATE feed_generators
+SET healthy = $2,
+    health_error = $3,
+    last_checked_at = $4
+WHERE uri = $1;
@@ -0,0 +1,35 @@
+-- name: UpsertFeedGenerator :exec
+INSERT INTO feed_generators (
+        uri,
+        creator_did,
+        rkey,
+        display_name,
+        description,
+        service_did,
+        like_count,
+        created_at,
+        updated_at
+    )
+VALUES (
+        $1,
+        $2,
+        $3,
+        $4,
+        $5,
+        $6,
+        COALESCE(
+            (
+                SELECT like_count
+                FROM feed_generator_likes
+                WHERE uri = $1
+            ),
+            0
+        ),
+        $7,
+        $8
+    ) ON CONFLICT (uri) DO
+UPDATE
+SET display_name = EXCLUDED.display_name,
+    description = EXCLUDED.description,
+    service_did = EXCLUDED.service_did,
+    updated_at = EXCLUDED.updated_at;
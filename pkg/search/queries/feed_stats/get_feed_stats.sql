@@ -0,0 +1,6 @@
+-- name: GetFeedStats :many
+SELECT *
+FROM feed_stats_daily
+WHERE feed_name = sqlc.arg('feed_name')
+ORDER BY date DESC
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,25 @@
+-- name: UpsertFeedStats :exec
+INSERT INTO feed_stats_daily (
+        feed_name,
+        date,
+        requests,
+        sessions,
+        posts_served,
+        unique_posts_served,
+        unique_users,
+        returning_users,
+        liked_posts,
+        total_likes,
+        updated_at
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW()) ON CONFLICT (feed_name, date) DO
+UPDATE
+SET requests = EXCLUDED.requests,
+    sessions = EXCLUDED.sessions,
+    posts_served = EXCLUDED.posts_served,
+    unique_posts_served = EXCLUDED.unique_posts_served,
+    unique_users = EXCLUDED.unique_users,
+    returning_users = EXCLUDED.returning_users,
+    liked_posts = EXCLUDED.liked_posts,
+    total_likes = EXCLUDED.total_likes,
+    updated_at = NOW();
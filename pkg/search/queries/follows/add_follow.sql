@@ -0,0 +1,2 @@
+-- name: AddFollow :execrows
+INSERT INTO follows (actor_did, rkey, target_did, created_at) VALUES ($1, $2, $3, $4) ON CONFLICT (actor_did, rkey) DO NOTHING;
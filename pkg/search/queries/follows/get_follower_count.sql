@@ -0,0 +1,4 @@
+-- name: GetFollowerCount :one
+SELECT COUNT(*) AS count
+FROM follows
+WHERE target_did = $1;
@@ -0,0 +1,4 @@
+-- name: GetFollowingCount :one
+SELECT COUNT(*) AS count
+FROM follows
+WHERE actor_did = $1;
@@ -0,0 +1,10 @@
+-- name: GetMutualFollows :many
+-- GetMutualFollows returns a page of DIDs that follow and are followed by the actor.
+SELECT DISTINCT f.target_did
+FROM follows f
+    JOIN follows r ON r.actor_did = f.target_did
+    AND r.target_did = f.actor_did
+WHERE f.actor_did = $1
+ORDER BY f.target_did ASC
+LIMIT $2
+OFFSET $3;
@@ -0,0 +1,3 @@
+-- name: RemoveFollow :one
+-- RemoveFollow deletes a follow by its record key and returns the unfollowed DID.
+DELETE FROM follows WHERE actor_did = $1 AND rkey = $2 RETURNING target_did;
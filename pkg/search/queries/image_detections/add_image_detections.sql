@@ -0,0 +1,58 @@
+-- name: AddImageDetections :exec
+-- AddImageDetections upserts a batch of object detections, detections are keyed by their index within the image.
+INSERT INTO image_detections (
+        image_cid,
+        post_id,
+        author_did,
+        detection_index,
+        label,
+        confidence,
+        box_x_min,
+        box_y_min,
+        box_x_max,
+        box_y_max,
+        created_at
+    )
+SELECT image_cid,
+    post_id,
+    author_did,
+    detection_index,
+    label,
+    confidence,
+    box_x_min,
+    box_y_min,
+    box_x_max,
+    box_y_max,
+    created_at
+FROM unnest(
+        sqlc.arg('image_cids')::text [],
+        sqlc.arg('post_ids')::text [],
+        sqlc.arg('author_dids')::text [],
+        sqlc.arg('detection_indexes')::int [],
+        sqlc.arg('labels')::text [],
+        sqlc.arg('confidences')::float8 [],
+        sqlc.arg('box_x_mins')::float8 [],
+        sqlc.arg('box_y_mins')::float8 [],
+        sqlc.arg('box_x_maxes')::float8 [],
+        sqlc.arg('box_y_maxes')::float8 [],
+        sqlc.arg('created_ats')::timestamptz []
+    ) AS d (
+        image_cid,
+        post_id,
+        author_did,
+        detection_index,
+        label,
+        confidence,
+        box_x_min,
+        box_y_min,
+        box_x_max,
+        box_y_max,
+        created_at
+    ) ON CONFLICT (image_cid, post_id, detection_index) DO
+UPDATE
+SET label = EXCLUDED.label,
+    confidence = EXCLUDED.confidence,
+    box_x_min = EXCLUDED.box_x_min,
+    box_y_min = EXCLUDED.box_y_min,
+    box_x_max = EXCLUDED.box_x_max,
+    box_y_max = EXCLUDED.box_y_max;
@@ -0,0 +1,6 @@
+-- name: GetDetectionsForImage :many
+SELECT *
+FROM image_detections
+WHERE image_cid = sqlc.arg('image_cid')
+ORDER BY post_id,
+    detection_index;
@@ -0,0 +1,20 @@
+-- name: GetImagesByDetectionLabel :many
+-- GetImagesByDetectionLabel returns a page of the images an object was detected in with at least the given confidence, newest first.
+SELECT i.cid,
+    i.post_id,
+    i.author_did,
+    i.alt_text,
+    i.fullsize_url,
+    i.thumbnail_url,
+    i.created_at,
+    MAX(d.confidence)::float8 AS confidence,
+    COUNT(*) AS detection_count
+FROM image_detections d
+    JOIN images i ON i.cid = d.image_cid
+    AND i.post_id = d.post_id
+WHERE d.label = sqlc.arg('label')
+    AND d.confidence >= sqlc.arg('min_confidence')::float8
+GROUP BY i.cid,
+    i.post_id
+ORDER BY i.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
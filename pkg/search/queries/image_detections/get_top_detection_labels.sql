@@ -0,0 +1,11 @@
+-- name: GetTopDetectionLabels :many
+-- GetTopDetectionLabels counts the images each object was detected in since the given time.
+SELECT label,
+    COUNT(DISTINCT (image_cid, post_id)) AS image_count,
+    AVG(confidence)::float8 AS avg_confidence
+FROM image_detections
+WHERE created_at >= sqlc.arg('since')::timestamptz
+    AND confidence >= sqlc.arg('min_confidence')::float8
+GROUP BY label
+ORDER BY image_count DESC
+LIMIT sqlc.arg('limit');
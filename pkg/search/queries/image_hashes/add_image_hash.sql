@@ -0,0 +1,12 @@
+-- name: AddImageHash :exec
+INSERT INTO image_hashes (cid, phash, dhash, hashed_at)
+VALUES (
+        sqlc.arg('cid'),
+        sqlc.narg('phash'),
+        sqlc.narg('dhash'),
+        sqlc.arg('hashed_at')
+    ) ON CONFLICT (cid) DO
+UPDATE
+SET phash = EXCLUDED.phash,
+    dhash = EXCLUDED.dhash,
+    hashed_at = EXCLUDED.hashed_at;
@@ -0,0 +1,11 @@
+-- name: GetImageHash :one
+SELECT cid,
+    phash,
+    dhash,
+    phash_band_0,
+    phash_band_1,
+    phash_band_2,
+    phash_band_3,
+    hashed_at
+FROM image_hashes
+WHERE cid = $1;
@@ -0,0 +1,33 @@
+-- name: GetPostsWithSimilarImages :many
+-- GetPostsWithSimilarImages returns the posts since the given time with an image within max_distance bits of an image, including the image itself.
+WITH target AS (
+    SELECT phash,
+        phash_band_0,
+        phash_band_1,
+        phash_band_2,
+        phash_band_3
+    FROM image_hashes
+    WHERE cid = sqlc.arg('cid')
+        AND phash IS NOT NULL
+),
+matches AS (
+    SELECT h.cid
+    FROM image_hashes h,
+        target t
+    WHERE (
+            h.phash_band_0 = t.phash_band_0
+            OR h.phash_band_1 = t.phash_band_1
+            OR h.phash_band_2 = t.phash_band_2
+            OR h.phash_band_3 = t.phash_band_3
+        )
+        AND length(replace((h.phash # t.phash)::bit(64)::text, '0', '')) <= sqlc.arg('max_distance')::int
+)
+SELECT DISTINCT i.cid,
+    i.post_id,
+    i.author_did,
+    i.created_at
+FROM images i
+    JOIN matches m ON i.cid = m.cid
+WHERE i.created_at >= sqlc.arg('since')::timestamptz
+ORDER BY i.created_at,
+    i.post_id;
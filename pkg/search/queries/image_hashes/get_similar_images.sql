@@ -0,0 +1,39 @@
+-- name: GetSimilarImages :many
+-- GetSimilarImages returns the images whose pHash is within max_distance bits of an image's, closest first.
+WITH target AS (
+    SELECT cid,
+        phash,
+        phash_band_0,
+        phash_band_1,
+        phash_band_2,
+        phash_band_3
+    FROM image_hashes
+    WHERE cid = sqlc.arg('cid')
+        AND phash IS NOT NULL
+),
+candidates AS (
+    SELECT h.cid,
+        length(replace((h.phash # t.phash)::bit(64)::text, '0', ''))::int AS distance
+    FROM image_hashes h,
+        target t
+    WHERE h.cid <> t.cid
+        AND (
+            h.phash_band_0 = t.phash_band_0
+            OR h.phash_band_1 = t.phash_band_1
+            OR h.phash_band_2 = t.phash_band_2
+            OR h.phash_band_3 = t.phash_band_3
+        )
+)
+SELECT c.cid,
+    c.distance,
+    COUNT(i.post_id) AS post_count,
+    MIN(i.created_at)::timestamptz AS first_posted_at,
+    MIN(i.thumbnail_url)::text AS thumbnail_url
+FROM candidates c
+    JOIN images i ON i.cid = c.cid
+WHERE c.distance <= sqlc.arg('max_distance')::int
+GROUP BY c.cid,
+    c.distance
+ORDER BY c.distance,
+    first_posted_at
+LIMIT sqlc.arg('limit');
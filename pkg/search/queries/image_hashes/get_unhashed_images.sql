@@ -0,0 +1,15 @@
+-- name: GetUnhashedImages :many
+-- GetUnhashedImages returns the newest images whose thumbnails haven't been hashed yet.
+SELECT i.cid,
+    i.post_id,
+    i.author_did,
+    i.thumbnail_url,
+    i.created_at
+FROM images i
+WHERE NOT EXISTS (
+        SELECT 1
+        FROM image_hashes h
+        WHERE h.cid = i.cid
+    )
+ORDER BY i.created_at DESC
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,45 @@
+-- name: AddImages :exec
+-- AddImages inserts a batch of images that haven't been through CV yet, images that already exist are skipped.
+INSERT INTO images (
+        cid,
+        post_id,
+        author_did,
+        alt_text,
+        mime_type,
+        fullsize_url,
+        thumbnail_url,
+        created_at,
+        cv_completed
+    )
+SELECT cid,
+    post_id,
+    author_did,
+    CASE
+        WHEN has_alt_text THEN alt_text
+    END,
+    mime_type,
+    fullsize_url,
+    thumbnail_url,
+    created_at,
+    FALSE
+FROM unnest(
+        sqlc.arg('cids')::text [],
+        sqlc.arg('post_ids')::text [],
+        sqlc.arg('author_dids')::text [],
+        sqlc.arg('alt_texts')::text [],
+        sqlc.arg('has_alt_texts')::boolean [],
+        sqlc.arg('mime_types')::text [],
+        sqlc.arg('fullsize_urls')::text [],
+        sqlc.arg('thumbnail_urls')::text [],
+        sqlc.arg('created_ats')::timestamptz []
+    ) AS i (
+        cid,
+        post_id,
+        author_did,
+        alt_text,
+        has_alt_text,
+        mime_type,
+        fullsize_url,
+        thumbnail_url,
+        created_at
+    ) ON CONFLICT (cid, post_id) DO NOTHING;
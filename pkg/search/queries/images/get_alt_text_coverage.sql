@@ -0,0 +1,8 @@
+-- name: GetAltTextCoverage :one
+-- GetAltTextCoverage counts the images that have alt text across every author.
+SELECT COUNT(*) AS image_count,
+    COUNT(*) FILTER (
+        WHERE TRIM(COALESCE(alt_text, '')) <> ''
+    ) AS alt_text_count,
+    COUNT(DISTINCT author_did) AS author_count
+FROM images;
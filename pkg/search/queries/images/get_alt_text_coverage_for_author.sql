@@ -0,0 +1,8 @@
+-- name: GetAltTextCoverageForAuthor :one
+-- GetAltTextCoverageForAuthor counts the images an author has posted and how many of them have alt text.
+SELECT COUNT(*) AS image_count,
+    COUNT(*) FILTER (
+        WHERE TRIM(COALESCE(alt_text, '')) <> ''
+    ) AS alt_text_count
+FROM images
+WHERE author_did = sqlc.arg('author_did');
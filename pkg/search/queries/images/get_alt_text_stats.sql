@@ -0,0 +1,58 @@
+-- name: GetAltTextStats :many
+-- GetAltTextStats counts the images that have alt text across every author, for each cluster, and for the authors who post the most images in one scan of images.
+-- Rows are tagged by kind: a single 'global' row, one 'cluster' row per cluster, and an 'author' row for each top image poster.
+WITH author_images AS (
+    SELECT author_did,
+        COUNT(*) AS image_count,
+        COUNT(*) FILTER (
+            WHERE TRIM(COALESCE(alt_text, '')) <> ''
+        ) AS alt_text_count
+    FROM images
+    GROUP BY author_did
+)
+SELECT 'global'::text AS kind,
+    NULL::int AS cluster_id,
+    NULL::text AS lookup_alias,
+    NULL::text AS name,
+    NULL::text AS author_did,
+    NULL::text AS handle,
+    COALESCE(SUM(image_count), 0)::bigint AS image_count,
+    COALESCE(SUM(alt_text_count), 0)::bigint AS alt_text_count,
+    COUNT(*) AS author_count
+FROM author_images
+UNION ALL
+SELECT 'cluster'::text AS kind,
+    c.id AS cluster_id,
+    c.lookup_alias,
+    c.name,
+    NULL::text AS author_did,
+    NULL::text AS handle,
+    SUM(ai.image_count)::bigint AS image_count,
+    SUM(ai.alt_text_count)::bigint AS alt_text_count,
+    COUNT(*) AS author_count
+FROM author_images ai
+    JOIN author_clusters ac ON ai.author_did = ac.author_did
+    JOIN clusters c ON ac.cluster_id = c.id
+GROUP BY c.id,
+    c.lookup_alias,
+    c.name
+UNION ALL
+SELECT 'author'::text AS kind,
+    NULL::int AS cluster_id,
+    NULL::text AS lookup_alias,
+    NULL::text AS name,
+    top.author_did,
+    top.handle,
+    top.image_count,
+    top.alt_text_count,
+    1::bigint AS author_count
+FROM (
+        SELECT ai.author_did,
+            a.handle,
+            ai.image_count,
+            ai.alt_text_count
+        FROM author_images ai
+            JOIN authors a ON ai.author_did = a.did
+        ORDER BY ai.image_count DESC
+        LIMIT sqlc.arg('limit')
+    ) top;
@@ -0,0 +1,5 @@
+-- name: GetImagesForPosts :many
+-- GetImagesForPosts returns the images of a batch of posts.
+SELECT cid, post_id, author_did, alt_text, mime_type, fullsize_url, thumbnail_url, created_at, cv_completed, cv_run_at, cv_classes
+FROM images
+WHERE post_id = ANY(sqlc.arg('post_ids')::text []);
@@ -0,0 +1,9 @@
+-- name: GetOldestUnprocessedImageInsertedAt :one
+-- GetOldestUnprocessedImageInsertedAt returns when the oldest post with an image that hasn't been through CV was inserted.
+SELECT p.inserted_at
+FROM images i
+    JOIN posts p ON i.post_id = p.id
+WHERE i.cv_completed = FALSE
+    AND p.inserted_at IS NOT NULL
+ORDER BY p.inserted_at ASC
+LIMIT 1;
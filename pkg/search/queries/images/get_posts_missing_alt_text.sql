@@ -0,0 +1,19 @@
+-- name: GetPostsMissingAltText :many
+-- GetPostsMissingAltText returns a page of the posts with images that have no alt text, newest first.
+-- Pages are keyed on (created_at, post_id) so posts that share a timestamp are neither skipped nor repeated.
+SELECT post_id,
+    author_did,
+    created_at,
+    COUNT(*) AS image_count
+FROM images
+WHERE TRIM(COALESCE(alt_text, '')) = ''
+    AND (created_at, post_id) < (
+        sqlc.arg('cursor_created_at')::timestamptz,
+        sqlc.arg('cursor_post_id')::text
+    )
+GROUP BY post_id,
+    author_did,
+    created_at
+ORDER BY created_at DESC,
+    post_id DESC
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,13 @@
+-- name: AddLikesToPosts :exec
+-- AddLikesToPosts adds a batch of like counts to posts, post IDs must be unique within the batch.
+INSERT INTO post_likes (post_id, author_did, like_count)
+SELECT post_id,
+    author_did,
+    like_count
+FROM unnest(
+        sqlc.arg('post_ids')::text [],
+        sqlc.arg('author_dids')::text [],
+        sqlc.arg('like_counts')::bigint []
+    ) AS l (post_id, author_did, like_count) ON CONFLICT (post_id) DO
+UPDATE
+SET like_count = post_likes.like_count + EXCLUDED.like_count;
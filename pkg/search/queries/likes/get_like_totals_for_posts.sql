@@ -0,0 +1,7 @@
+-- name: GetLikeTotalsForPosts :one
+SELECT COUNT(*) FILTER (
+        WHERE like_count > 0
+    ) AS liked_posts,
+    COALESCE(SUM(like_count), 0)::bigint AS total_likes
+FROM post_likes
+WHERE post_id = ANY(sqlc.arg('post_ids')::text []);
@@ -0,0 +1,2 @@
+-- name: AddListItem :exec
+INSERT INTO list_items (uri, creator_did, list_uri, subject_did, created_at) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (uri) DO NOTHING;
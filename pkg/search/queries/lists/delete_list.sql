@@ -0,0 +1,2 @@
+-- name: DeleteList :exec
+DELETE FROM lists WHERE uri = $1;
@@ -0,0 +1,2 @@
+-- name: DeleteListItem :exec
+DELETE FROM list_items WHERE uri = $1;
@@ -0,0 +1,2 @@
+-- name: DeleteListItemsForList :exec
+DELETE FROM list_items WHERE list_uri = $1;
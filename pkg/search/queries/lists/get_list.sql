@@ -0,0 +1,4 @@
+-- name: GetList :one
+SELECT uri, creator_did, name, purpose, description, created_at
+FROM lists
+WHERE uri = $1;
@@ -0,0 +1,12 @@
+-- name: GetListMembers :many
+-- GetListMembers returns a page of the subjects on a list.
+-- Only items created by the list's owner count.
+-- The members are ordered by the created_at timestamp ascending.
+SELECT li.subject_did, li.created_at
+FROM list_items li
+    JOIN lists l ON li.list_uri = l.uri
+    AND li.creator_did = l.creator_did
+WHERE li.list_uri = $1
+ORDER BY li.created_at ASC
+LIMIT $2
+OFFSET $3;
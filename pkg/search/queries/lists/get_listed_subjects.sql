@@ -0,0 +1,9 @@
+-- name: GetListedSubjects :many
+-- GetListedSubjects returns the subjects that are members of any of the given lists.
+-- Only items created by the list's owner count.
+SELECT DISTINCT li.subject_did
+FROM list_items li
+    JOIN lists l ON li.list_uri = l.uri
+    AND li.creator_did = l.creator_did
+WHERE li.list_uri = ANY(sqlc.arg('list_uris')::text [])
+    AND li.subject_did = ANY(sqlc.arg('subject_dids')::text []);
@@ -0,0 +1,9 @@
+-- name: GetListsForAuthor :many
+-- GetListsForAuthor returns a page of the lists an author has been added to.
+SELECT l.uri, l.creator_did, l.name, l.purpose, l.description, l.created_at
+FROM list_items li
+    JOIN lists l ON l.uri = li.list_uri
+WHERE li.subject_did = $1
+ORDER BY li.created_at DESC
+LIMIT $2
+OFFSET $3;
@@ -0,0 +1,14 @@
+-- name: UpsertList :exec
+INSERT INTO lists (
+        uri,
+        creator_did,
+        name,
+        purpose,
+        description,
+        created_at
+    )
+VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (uri) DO
+UPDATE
+SET name = EXCLUDED.name,
+    purpose = EXCLUDED.purpose,
+    description = EXCLUDED.description;
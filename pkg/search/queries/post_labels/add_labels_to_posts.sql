@@ -1,6 +1,17 @@
 -- name: AddLabelsToPosts :exec
-INSERT INTO post_labels (post_id, author_did, label)
-SELECT post_id,
-    author_did,
-    x.label
-FROM jsonb_to_recordset($1::jsonb) AS x(post_id text, author_did text, label text) ON CONFLICT (post_id, label) DO NOTHING;
+-- AddLabelsToPosts adds labels to posts, posts that get a new label are marked unindexed so the search index picks it up.
+WITH added AS (
+    INSERT INTO post_labels (post_id, author_did, label)
+    SELECT post_id,
+        author_did,
+        x.label
+    FROM jsonb_to_recordset($1::jsonb) AS x(post_id text, author_did text, label text) ON CONFLICT (post_id, label) DO NOTHING
+    RETURNING post_id
+)
+UPDATE posts
+SET indexed_at = NULL
+WHERE id IN (
+        SELECT post_id
+        FROM added
+    )
+    AND indexed_at IS NOT NULL;
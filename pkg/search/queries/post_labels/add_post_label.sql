@@ -1,3 +1,14 @@
 -- name: AddPostLabel :exec
-INSERT INTO post_labels (post_id, author_did, label)
-VALUES ($1, $2, $3) ON CONFLICT (post_id, label) DO NOTHING;
+-- AddPostLabel adds a label to a post, the post is marked unindexed if the label is new so the search index picks it up.
+WITH added AS (
+    INSERT INTO post_labels (post_id, author_did, label)
+    VALUES ($1, $2, $3) ON CONFLICT (post_id, label) DO NOTHING
+    RETURNING post_id
+)
+UPDATE posts
+SET indexed_at = NULL
+WHERE id IN (
+        SELECT post_id
+        FROM added
+    )
+    AND indexed_at IS NOT NULL;
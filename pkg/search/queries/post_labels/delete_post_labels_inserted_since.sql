@@ -0,0 +1,16 @@
+-- name: DeletePostLabelsInsertedSince :exec
+-- DeletePostLabelsInsertedSince removes a label from the posts inserted at or after the time, those posts are marked unindexed so the search index drops it.
+WITH deleted AS (
+    DELETE FROM post_labels pl USING posts p
+    WHERE pl.post_id = p.id
+        AND pl.label = $1
+        AND p.inserted_at >= $2::timestamptz
+    RETURNING pl.post_id
+)
+UPDATE posts
+SET indexed_at = NULL
+WHERE id IN (
+        SELECT post_id
+        FROM deleted
+    )
+    AND indexed_at IS NOT NULL;
@@ -0,0 +1,22 @@
+-- name: GetPostsPageWithAnyPostLabelChronological :many
+-- GetPostsPageWithAnyPostLabelChronological pages through posts with any of the labels, newest first.
+SELECT p.id,
+    p.text,
+    p.parent_post_id,
+    p.root_post_id,
+    p.author_did,
+    p.created_at,
+    p.has_embedded_media,
+    p.parent_relationship,
+    p.sentiment,
+    p.sentiment_confidence
+FROM posts p
+WHERE EXISTS (
+        SELECT 1
+        FROM post_labels l
+        WHERE l.post_id = p.id
+            AND l.label = ANY(sqlc.arg('labels')::text [])
+    )
+    AND p.created_at < sqlc.arg('cursor')::timestamptz
+ORDER BY p.created_at DESC
+LIMIT sqlc.arg('limit');
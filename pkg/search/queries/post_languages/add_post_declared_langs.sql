@@ -0,0 +1,17 @@
+-- name: AddPostDeclaredLangs :exec
+-- AddPostDeclaredLangs upserts the languages declared on a batch of post records.
+-- Each post's languages are sent as one comma-separated string since Postgres can't unnest ragged arrays.
+INSERT INTO post_languages (post_id, author_did, declared_langs, created_at)
+SELECT post_id,
+    author_did,
+    string_to_array(NULLIF(declared_langs, ''), ','),
+    created_at
+FROM unnest(
+        sqlc.arg('post_ids')::text [],
+        sqlc.arg('author_dids')::text [],
+        sqlc.arg('declared_langs')::text [],
+        sqlc.arg('created_ats')::timestamptz []
+    ) AS l (post_id, author_did, declared_langs, created_at)
+WHERE declared_langs <> '' ON CONFLICT (post_id) DO
+UPDATE
+SET declared_langs = EXCLUDED.declared_langs;
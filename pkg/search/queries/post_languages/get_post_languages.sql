@@ -0,0 +1,4 @@
+-- name: GetPostLanguages :many
+SELECT *
+FROM post_languages
+WHERE post_id = ANY(sqlc.arg('post_ids')::text []);
@@ -0,0 +1,31 @@
+-- name: SetPostDetectedLangs :exec
+-- SetPostDetectedLangs upserts the detected language of a batch of posts.
+-- An empty language is stored as NULL for posts where detection wasn't confident.
+INSERT INTO post_languages (
+        post_id,
+        author_did,
+        detected_lang,
+        detected_confidence,
+        created_at
+    )
+SELECT post_id,
+    author_did,
+    NULLIF(detected_lang, ''),
+    detected_confidence,
+    created_at
+FROM unnest(
+        sqlc.arg('post_ids')::text [],
+        sqlc.arg('author_dids')::text [],
+        sqlc.arg('detected_langs')::text [],
+        sqlc.arg('detected_confidences')::float8 [],
+        sqlc.arg('created_ats')::timestamptz []
+    ) AS l (
+        post_id,
+        author_did,
+        detected_lang,
+        detected_confidence,
+        created_at
+    ) ON CONFLICT (post_id) DO
+UPDATE
+SET detected_lang = EXCLUDED.detected_lang,
+    detected_confidence = EXCLUDED.detected_confidence;
@@ -0,0 +1,15 @@
+-- name: AddPostLinks :exec
+-- AddPostLinks inserts a batch of links, links that already exist are skipped.
+INSERT INTO post_links (post_id, author_did, url, domain, created_at)
+SELECT post_id,
+    author_did,
+    url,
+    domain,
+    created_at
+FROM unnest(
+        sqlc.arg('post_ids')::text [],
+        sqlc.arg('author_dids')::text [],
+        sqlc.arg('urls')::text [],
+        sqlc.arg('domains')::text [],
+        sqlc.arg('created_ats')::timestamptz []
+    ) AS l (post_id, author_did, url, domain, created_at) ON CONFLICT (post_id, url) DO NOTHING;
@@ -0,0 +1,12 @@
+-- name: GetPostsLinkingToURL :many
+-- GetPostsLinkingToURL returns a page of the posts that link to a URL, newest first.
+SELECT l.post_id,
+    l.author_did,
+    l.url,
+    l.created_at,
+    p.text
+FROM post_links l
+    JOIN posts p ON p.id = l.post_id
+WHERE l.url = sqlc.arg('url')
+ORDER BY l.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
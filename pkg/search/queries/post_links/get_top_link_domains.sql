@@ -0,0 +1,10 @@
+-- name: GetTopLinkDomains :many
+-- GetTopLinkDomains returns the most linked domains since the given time.
+SELECT domain,
+    COUNT(*) AS link_count,
+    COUNT(DISTINCT author_did) AS author_count
+FROM post_links
+WHERE created_at > sqlc.arg('since')::timestamptz
+GROUP BY domain
+ORDER BY link_count DESC
+LIMIT sqlc.arg('limit');
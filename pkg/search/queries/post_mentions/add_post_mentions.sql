@@ -0,0 +1,13 @@
+-- name: AddPostMentions :exec
+-- AddPostMentions inserts a batch of mentions, mentions that already exist are skipped.
+INSERT INTO post_mentions (post_id, author_did, mentioned_did, created_at)
+SELECT post_id,
+    author_did,
+    mentioned_did,
+    created_at
+FROM unnest(
+        sqlc.arg('post_ids')::text [],
+        sqlc.arg('author_dids')::text [],
+        sqlc.arg('mentioned_dids')::text [],
+        sqlc.arg('created_ats')::timestamptz []
+    ) AS m (post_id, author_did, mentioned_did, created_at) ON CONFLICT (post_id, mentioned_did) DO NOTHING;
@@ -0,0 +1,9 @@
+-- name: IndexPostSearchVectors :exec
+-- IndexPostSearchVectors builds the full-text search vectors of a batch of posts.
+INSERT INTO post_search_vectors (post_id, search_vector)
+SELECT id,
+    to_tsvector('english', text)
+FROM posts
+WHERE id = ANY(sqlc.arg('post_ids')::text []) ON CONFLICT (post_id) DO
+UPDATE
+SET search_vector = EXCLUDED.search_vector;
@@ -0,0 +1,84 @@
+-- name: SearchPosts :many
+-- SearchPosts matches posts against the full-text search vectors or, for posts that
+-- haven't been indexed yet and for typos, trigram word similarity.
+-- Every filter is optional and skipped when NULL.
+WITH candidates AS (
+    SELECT v.post_id
+    FROM post_search_vectors v
+    WHERE v.search_vector @@ websearch_to_tsquery('english', sqlc.arg('query')::text)
+    UNION
+    SELECT p.id
+    FROM posts p
+    WHERE sqlc.arg('query')::text <% p.text
+)
+SELECT p.id,
+    p.text,
+    p.parent_post_id,
+    p.root_post_id,
+    p.author_did,
+    a.handle,
+    p.created_at,
+    p.has_embedded_media,
+    p.parent_relationship,
+    p.sentiment,
+    p.sentiment_confidence,
+    (
+        COALESCE(
+            ts_rank(
+                v.search_vector,
+                websearch_to_tsquery('english', sqlc.arg('query')::text)
+            ),
+            0
+        ) + word_similarity(sqlc.arg('query')::text, p.text)
+    )::float8 AS rank
+FROM candidates c
+    JOIN posts p ON p.id = c.post_id
+    JOIN authors a ON a.did = p.author_did
+    LEFT JOIN post_search_vectors v ON v.post_id = p.id
+WHERE (
+        sqlc.narg('author_did')::text IS NULL
+        OR p.author_did = sqlc.narg('author_did')
+    )
+    AND (
+        sqlc.narg('label')::text IS NULL
+        OR EXISTS (
+            SELECT 1
+            FROM post_labels l
+            WHERE l.post_id = p.id
+                AND l.author_did = p.author_did
+                AND l.label = sqlc.narg('label')
+        )
+    )
+    AND (
+        sqlc.narg('cluster_id')::int IS NULL
+        OR EXISTS (
+            SELECT 1
+            FROM author_clusters ac
+            WHERE ac.author_did = p.author_did
+                AND ac.cluster_id = sqlc.narg('cluster_id')
+        )
+    )
+    AND (
+        sqlc.narg('since')::timestamptz IS NULL
+        OR p.created_at >= sqlc.narg('since')
+    )
+    AND (
+        sqlc.narg('until')::timestamptz IS NULL
+        OR p.created_at < sqlc.narg('until')
+    )
+    AND (
+        sqlc.narg('lang')::text IS NULL
+        OR EXISTS (
+            SELECT 1
+            FROM post_languages pl
+            WHERE pl.post_id = p.id
+                AND CASE
+                    -- A confident detection is trusted over the declared languages, like PostLanguage.Matches
+                    WHEN COALESCE(pl.detected_confidence, 0) >= sqlc.arg('min_detected_confidence')::float8 THEN pl.detected_lang = sqlc.narg('lang')
+                    ELSE sqlc.narg('lang') = ANY(pl.declared_langs)
+                END
+        )
+    )
+ORDER BY rank DESC,
+    p.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
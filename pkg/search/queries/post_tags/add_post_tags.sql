@@ -0,0 +1,13 @@
+-- name: AddPostTags :exec
+-- AddPostTags inserts a batch of tags, tags that already exist are skipped.
+INSERT INTO post_tags (post_id, author_did, tag, created_at)
+SELECT post_id,
+    author_did,
+    tag,
+    created_at
+FROM unnest(
+        sqlc.arg('post_ids')::text [],
+        sqlc.arg('author_dids')::text [],
+        sqlc.arg('tags')::text [],
+        sqlc.arg('created_ats')::timestamptz []
+    ) AS t (post_id, author_did, tag, created_at) ON CONFLICT (post_id, tag) DO NOTHING;
@@ -0,0 +1,5 @@
+-- name: GetTagsForPosts :many
+-- GetTagsForPosts returns the tags of a batch of posts.
+SELECT post_id, author_did, tag, created_at
+FROM post_tags
+WHERE post_id = ANY(sqlc.arg('post_ids')::text []);
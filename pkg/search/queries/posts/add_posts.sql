@@ -0,0 +1,48 @@
+-- name: AddPosts :exec
+-- AddPosts inserts a batch of posts, posts that already exist are skipped.
+-- Empty strings in the nullable columns are stored as NULL.
+INSERT INTO posts (
+        id,
+        text,
+        parent_post_id,
+        root_post_id,
+        author_did,
+        created_at,
+        has_embedded_media,
+        parent_relationship,
+        sentiment,
+        sentiment_confidence
+    )
+SELECT id,
+    text,
+    NULLIF(parent_post_id, ''),
+    NULLIF(root_post_id, ''),
+    author_did,
+    created_at,
+    has_embedded_media,
+    NULLIF(parent_relationship, ''),
+    NULLIF(sentiment, ''),
+    NULLIF(sentiment_confidence, '')::float
+FROM unnest(
+        sqlc.arg('ids')::text [],
+        sqlc.arg('texts')::text [],
+        sqlc.arg('parent_post_ids')::text [],
+        sqlc.arg('root_post_ids')::text [],
+        sqlc.arg('author_dids')::text [],
+        sqlc.arg('created_ats')::timestamptz [],
+        sqlc.arg('has_embedded_medias')::boolean [],
+        sqlc.arg('parent_relationships')::text [],
+        sqlc.arg('sentiments')::text [],
+        sqlc.arg('sentiment_confidences')::text []
+    ) AS p (
+        id,
+        text,
+        parent_post_id,
+        root_post_id,
+        author_did,
+        created_at,
+        has_embedded_media,
+        parent_relationship,
+        sentiment,
+        sentiment_confidence
+    ) ON CONFLICT (id) DO NOTHING;
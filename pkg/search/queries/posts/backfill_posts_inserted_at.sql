@@ -0,0 +1,18 @@
+-- name: BackfillPostsInsertedAt :one
+-- BackfillPostsInsertedAt stamps the posts in the batch after the cursor that have no inserted_at with when they were created.
+-- It returns the ID of the last post in the batch, or an empty string once every post has been visited.
+WITH batch AS (
+    SELECT id
+    FROM posts
+    WHERE id > sqlc.arg('cursor_post_id')::text
+    ORDER BY id
+    LIMIT sqlc.arg('limit')
+), stamped AS (
+    UPDATE posts p
+    SET inserted_at = LEAST(p.created_at, NOW())
+    FROM batch
+    WHERE p.id = batch.id
+        AND p.inserted_at IS NULL
+)
+SELECT COALESCE(MAX(id), '')::text AS last_post_id
+FROM batch;
@@ -0,0 +1,23 @@
+-- name: GetPostsAfterCursor :many
+-- GetPostsAfterCursor pages through posts in the order they were inserted, posts inserted after inserted_before are left for a later page.
+SELECT id,
+    text,
+    parent_post_id,
+    root_post_id,
+    author_did,
+    created_at,
+    has_embedded_media,
+    parent_relationship,
+    sentiment,
+    sentiment_confidence,
+    indexed_at,
+    inserted_at
+FROM posts
+WHERE (inserted_at, id) > (
+        sqlc.arg('cursor_inserted_at')::timestamptz,
+        sqlc.arg('cursor_post_id')::text
+    )
+    AND inserted_at < sqlc.arg('inserted_before')::timestamptz
+ORDER BY inserted_at,
+    id
+LIMIT sqlc.arg('limit');
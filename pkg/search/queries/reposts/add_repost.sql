@@ -0,0 +1,2 @@
+-- name: AddRepost :execrows
+INSERT INTO post_reposts (actor_did, rkey, post_id, author_did, created_at) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (actor_did, rkey) DO NOTHING;
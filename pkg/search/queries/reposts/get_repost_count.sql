@@ -0,0 +1,4 @@
+-- name: GetRepostCount :one
+SELECT COUNT(*) AS count
+FROM post_reposts
+WHERE post_id = $1;
@@ -0,0 +1,9 @@
+-- name: GetRepostersForPost :many
+-- GetRepostersForPost returns a page of reposts for a given post.
+-- The reposts are ordered by the created_at timestamp ascending.
+SELECT actor_did, created_at
+FROM post_reposts
+WHERE post_id = $1
+ORDER BY created_at ASC
+LIMIT $2
+OFFSET $3;
@@ -0,0 +1,3 @@
+-- name: RemoveRepost :one
+-- RemoveRepost deletes a repost by its record key and returns the reposted post.
+DELETE FROM post_reposts WHERE actor_did = $1 AND rkey = $2 RETURNING post_id, author_did;
@@ -0,0 +1,105 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search/search_queries"
+	"go.opentelemetry.io/otel"
+)
+
+type Repost struct {
+	ActorDID  string    `json:"actor_did"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RemovedRepost is the post a deleted repost pointed at
+type RemovedRepost struct {
+	PostID    string  `json:"post_id"`
+	AuthorDID *string `json:"author_did"`
+}
+
+// AddRepost records a repost of a post and returns false if the repost record was already indexed
+func (pr *PostRegistry) AddRepost(ctx context.Context, actorDID, rkey, postID, authorDID string, createdAt time.Time) (bool, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:AddRepost")
+	defer span.End()
+
+	inserted, err := pr.queries.AddRepost(ctx, search_queries.AddRepostParams{
+		ActorDid:  actorDID,
+		Rkey:      rkey,
+		PostID:    postID,
+		AuthorDid: sql.NullString{String: authorDID, Valid: authorDID != ""},
+		CreatedAt: createdAt,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return inserted > 0, nil
+}
+
+// RemoveRepost deletes the repost record with the given rkey and returns the post that was reposted
+func (pr *PostRegistry) RemoveRepost(ctx context.Context, actorDID, rkey string) (*RemovedRepost, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:RemoveRepost")
+	defer span.End()
+
+	row, err := pr.queries.RemoveRepost(ctx, search_queries.RemoveRepostParams{
+		ActorDid: actorDID,
+		Rkey:     rkey,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, NotFoundError{fmt.Errorf("repost not found")}
+		}
+		return nil, err
+	}
+
+	removed := &RemovedRepost{PostID: row.PostID}
+	if row.AuthorDid.Valid {
+		removed.AuthorDID = &row.AuthorDid.String
+	}
+
+	return removed, nil
+}
+
+func (pr *PostRegistry) GetRepostCount(ctx context.Context, postID string) (int64, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetRepostCount")
+	defer span.End()
+
+	count, err := pr.queries.GetRepostCount(ctx, postID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get repost count: %w", err)
+	}
+
+	return count, nil
+}
+
+func (pr *PostRegistry) GetRepostersForPost(ctx context.Context, postID string, limit int32, offset int32) ([]*Repost, error) {
+	tracer := otel.Tracer("post-registry")
+	ctx, span := tracer.Start(ctx, "PostRegistry:GetRepostersForPost")
+	defer span.End()
+
+	reposts, err := pr.queries.GetRepostersForPost(ctx, search_queries.GetRepostersForPostParams{
+		PostID: postID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reposters for post: %w", err)
+	}
+
+	retReposts := make([]*Repost, len(reposts))
+	for i, repost := range reposts {
+		retReposts[i] = &Repost{
+			ActorDID:  repost.ActorDid,
+			CreatedAt: repost.CreatedAt,
+		}
+	}
+
+	return retReposts, nil
+}
@@ -0,0 +1,14 @@
+CREATE TABLE feed_stats_daily (
+    feed_name TEXT NOT NULL,
+    date DATE NOT NULL,
+    requests BIGINT NOT NULL,
+    sessions BIGINT NOT NULL,
+    posts_served BIGINT NOT NULL,
+    unique_posts_served BIGINT NOT NULL,
+    unique_users BIGINT NOT NULL,
+    returning_users BIGINT NOT NULL,
+    liked_posts BIGINT NOT NULL,
+    total_likes BIGINT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (feed_name, date)
+);
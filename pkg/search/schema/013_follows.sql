@@ -0,0 +1,9 @@
+CREATE TABLE follows (
+    actor_did TEXT NOT NULL,
+    rkey TEXT NOT NULL,
+    target_did TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (actor_did, rkey)
+);
+CREATE INDEX follows_target_did_idx ON follows (target_did);
+CREATE INDEX follows_actor_did_target_did_idx ON follows (actor_did, target_did);
@@ -0,0 +1,8 @@
+CREATE TABLE author_profiles (
+    did TEXT PRIMARY KEY,
+    display_name TEXT,
+    description TEXT,
+    avatar_cid TEXT,
+    banner_cid TEXT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
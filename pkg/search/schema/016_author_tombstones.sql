@@ -0,0 +1,4 @@
+CREATE TABLE author_tombstones (
+    did TEXT PRIMARY KEY,
+    tombstoned_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,18 @@
+CREATE TABLE lists (
+    uri TEXT PRIMARY KEY,
+    creator_did TEXT NOT NULL,
+    name TEXT NOT NULL,
+    purpose TEXT NOT NULL,
+    description TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX lists_creator_did_idx ON lists (creator_did);
+CREATE TABLE list_items (
+    uri TEXT PRIMARY KEY,
+    creator_did TEXT NOT NULL,
+    list_uri TEXT NOT NULL,
+    subject_did TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX list_items_list_uri_subject_did_idx ON list_items (list_uri, subject_did);
+CREATE INDEX list_items_subject_did_idx ON list_items (subject_did);
@@ -0,0 +1,17 @@
+CREATE TABLE feed_generators (
+    uri TEXT PRIMARY KEY,
+    creator_did TEXT NOT NULL,
+    rkey TEXT NOT NULL,
+    display_name TEXT NOT NULL,
+    description TEXT,
+    service_did TEXT NOT NULL,
+    like_count BIGINT NOT NULL DEFAULT 0,
+    healthy BOOLEAN,
+    health_error TEXT,
+    last_checked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX feed_generators_creator_did_idx ON feed_generators (creator_did);
+CREATE INDEX feed_generators_like_count_idx ON feed_generators (like_count DESC);
+CREATE INDEX feed_generators_last_checked_at_idx ON feed_generators (last_checked_at ASC NULLS FIRST);
@@ -0,0 +1,19 @@
+CREATE TABLE post_mentions (
+    post_id TEXT NOT NULL,
+    author_did TEXT NOT NULL,
+    mentioned_did TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (post_id, mentioned_did)
+);
+CREATE INDEX post_mentions_mentioned_did_created_at_idx ON post_mentions (mentioned_did, created_at DESC);
+CREATE TABLE post_links (
+    post_id TEXT NOT NULL,
+    author_did TEXT NOT NULL,
+    url TEXT NOT NULL,
+    domain TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (post_id, url)
+);
+CREATE INDEX post_links_url_created_at_idx ON post_links (url, created_at DESC);
+CREATE INDEX post_links_domain_created_at_idx ON post_links (domain, created_at DESC);
+CREATE INDEX post_links_created_at_idx ON post_links (created_at);
@@ -0,0 +1,7 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE TABLE post_search_vectors (
+    post_id TEXT PRIMARY KEY,
+    search_vector TSVECTOR NOT NULL,
+    FOREIGN KEY (post_id) REFERENCES posts(id)
+);
+CREATE INDEX post_search_vectors_search_vector_idx ON post_search_vectors USING GIN (search_vector);
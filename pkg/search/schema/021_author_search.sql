@@ -0,0 +1,2 @@
+CREATE INDEX authors_handle_trgm_idx ON authors USING GIN (handle gin_trgm_ops);
+CREATE INDEX author_profiles_display_name_trgm_idx ON author_profiles USING GIN (display_name gin_trgm_ops);
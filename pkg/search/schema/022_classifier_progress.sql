@@ -0,0 +1,11 @@
+CREATE TABLE classifier_progress (
+    classifier TEXT PRIMARY KEY,
+    cursor_created_at TIMESTAMPTZ NOT NULL,
+    cursor_post_id TEXT NOT NULL,
+    processed_count BIGINT NOT NULL DEFAULT 0,
+    labeled_count BIGINT NOT NULL DEFAULT 0,
+    failed_count BIGINT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX posts_created_at_id_idx ON posts (created_at, id);
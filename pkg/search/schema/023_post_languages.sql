@@ -0,0 +1,10 @@
+CREATE TABLE post_languages (
+    post_id TEXT PRIMARY KEY,
+    author_did TEXT NOT NULL,
+    declared_langs TEXT [] NOT NULL DEFAULT '{}',
+    detected_lang TEXT,
+    detected_confidence FLOAT,
+    created_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX post_languages_detected_lang_created_at_idx ON post_languages (detected_lang, created_at DESC);
+CREATE INDEX post_languages_declared_langs_idx ON post_languages USING GIN (declared_langs);
@@ -0,0 +1,49 @@
+CREATE TABLE image_detections (
+    image_cid TEXT NOT NULL,
+    post_id TEXT NOT NULL,
+    author_did TEXT NOT NULL,
+    detection_index INT NOT NULL,
+    label TEXT NOT NULL,
+    confidence FLOAT NOT NULL,
+    box_x_min FLOAT NOT NULL,
+    box_y_min FLOAT NOT NULL,
+    box_x_max FLOAT NOT NULL,
+    box_y_max FLOAT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (image_cid, post_id, detection_index),
+    FOREIGN KEY (image_cid, post_id) REFERENCES images (cid, post_id)
+);
+CREATE INDEX image_detections_label_confidence_idx ON image_detections (label, confidence DESC);
+CREATE INDEX image_detections_label_created_at_idx ON image_detections (label, created_at DESC);
+CREATE INDEX image_detections_created_at_idx ON image_detections (created_at);
+-- Backfill detections from the raw CV results of images that have already been processed
+INSERT INTO image_detections (
+        image_cid,
+        post_id,
+        author_did,
+        detection_index,
+        label,
+        confidence,
+        box_x_min,
+        box_y_min,
+        box_x_max,
+        box_y_max,
+        created_at
+    )
+SELECT i.cid,
+    i.post_id,
+    i.author_did,
+    (d.ordinality - 1)::int,
+    d.value->>'label',
+    (d.value->>'confidence')::float,
+    (d.value->'box'->>0)::float,
+    (d.value->'box'->>1)::float,
+    (d.value->'box'->>2)::float,
+    (d.value->'box'->>3)::float,
+    i.created_at
+FROM images i,
+    jsonb_array_elements(i.cv_classes) WITH ORDINALITY AS d(value, ordinality)
+WHERE i.cv_completed
+    AND jsonb_typeof(i.cv_classes) = 'array'
+    AND jsonb_typeof(d.value->'box') = 'array'
+    AND jsonb_array_length(d.value->'box') = 4;
@@ -0,0 +1,3 @@
+CREATE INDEX images_author_did_idx ON images (author_did);
+CREATE INDEX images_missing_alt_text_created_at_idx ON images (created_at DESC)
+WHERE TRIM(COALESCE(alt_text, '')) = '';
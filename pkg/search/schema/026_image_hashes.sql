@@ -0,0 +1,18 @@
+-- Perceptual hashes of image thumbnails, hashes are NULL when the thumbnail couldn't be fetched or decoded
+-- The pHash is split into four 16 bit bands so near-duplicates can be found by index,
+-- two hashes within 3 bits of each other always share at least one band
+CREATE TABLE image_hashes (
+    cid TEXT PRIMARY KEY,
+    phash BIGINT,
+    dhash BIGINT,
+    phash_band_0 INT GENERATED ALWAYS AS (((phash >> 48) & 65535)::int) STORED,
+    phash_band_1 INT GENERATED ALWAYS AS (((phash >> 32) & 65535)::int) STORED,
+    phash_band_2 INT GENERATED ALWAYS AS (((phash >> 16) & 65535)::int) STORED,
+    phash_band_3 INT GENERATED ALWAYS AS ((phash & 65535)::int) STORED,
+    hashed_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX image_hashes_phash_band_0_idx ON image_hashes (phash_band_0);
+CREATE INDEX image_hashes_phash_band_1_idx ON image_hashes (phash_band_1);
+CREATE INDEX image_hashes_phash_band_2_idx ON image_hashes (phash_band_2);
+CREATE INDEX image_hashes_phash_band_3_idx ON image_hashes (phash_band_3);
+CREATE INDEX images_created_at_idx ON images (created_at DESC);
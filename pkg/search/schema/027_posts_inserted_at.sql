@@ -0,0 +1,14 @@
+-- inserted_at is set by the database when a post is written so classifiers can page through posts in the order they arrived,
+-- created_at comes from the client and can be backdated or arrive late
+-- The column is added without a default and the default set separately so the posts table isn't rewritten,
+-- existing posts are left NULL until the indexer backfills them in batches (BACKFILL_POSTS_INSERTED_AT=true)
+ALTER TABLE posts
+ADD COLUMN inserted_at TIMESTAMPTZ;
+ALTER TABLE posts
+ALTER COLUMN inserted_at
+SET DEFAULT NOW();
+CREATE INDEX posts_inserted_at_id_idx ON posts (inserted_at, id);
+DROP INDEX posts_created_at_id_idx;
+-- Classifier cursors are carried over as is since posts were created shortly before they were inserted
+ALTER TABLE classifier_progress
+    RENAME COLUMN cursor_created_at TO cursor_inserted_at;
@@ -0,0 +1,11 @@
+-- Likes of feed generators are counted here whether or not the feed generator has been indexed yet
+-- so likes that arrive before the generator record aren't lost, feed_generators.like_count is kept in sync from it
+CREATE TABLE feed_generator_likes (
+    uri TEXT PRIMARY KEY,
+    like_count BIGINT NOT NULL DEFAULT 0
+);
+INSERT INTO feed_generator_likes (uri, like_count)
+SELECT uri,
+    like_count
+FROM feed_generators
+WHERE like_count > 0;
@@ -0,0 +1,3 @@
+-- The trigram index on post text is built concurrently so writes to posts aren't blocked while it builds,
+-- CREATE INDEX CONCURRENTLY can't run inside a transaction so it's kept in its own migration
+CREATE INDEX CONCURRENTLY IF NOT EXISTS posts_text_trgm_idx ON posts USING GIN (text gin_trgm_ops);
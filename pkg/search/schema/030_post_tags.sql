@@ -0,0 +1,9 @@
+-- Tags from the tag facets of posts, a tag facet doesn't have to cover a "#tag" in the post's text
+CREATE TABLE post_tags (
+    post_id TEXT NOT NULL,
+    author_did TEXT NOT NULL,
+    tag TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (post_id, tag)
+);
+CREATE INDEX post_tags_tag_created_at_idx ON post_tags (tag, created_at DESC);
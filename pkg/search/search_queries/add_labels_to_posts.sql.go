@@ -9,13 +9,24 @@ import (
 )
 
 const addLabelsToPosts = `-- name: AddLabelsToPosts :exec
-INSERT INTO post_labels (post_id, author_did, label)
-SELECT post_id,
-    author_did,
-    x.label
-FROM jsonb_to_recordset($1::jsonb) AS x(post_id text, author_did text, label text) ON CONFLICT (post_id, label) DO NOTHING
+WITH added AS (
+    INSERT INTO post_labels (post_id, author_did, label)
+    SELECT post_id,
+        author_did,
+        x.label
+    FROM jsonb_to_recordset($1::jsonb) AS x(post_id text, author_did text, label text) ON CONFLICT (post_id, label) DO NOTHING
+    RETURNING post_id
+)
+UPDATE posts
+SET indexed_at = NULL
+WHERE id IN (
+        SELECT post_id
+        FROM added
+    )
+    AND indexed_at IS NOT NULL
 `
 
+// AddLabelsToPosts adds labels to posts, posts that get a new label are marked unindexed so the search index picks it up.
 func (q *Queries) AddLabelsToPosts(ctx context.Context, dollar_1 json.RawMessage) error {
 	_, err := q.exec(ctx, q.addLabelsToPostsStmt, addLabelsToPosts, dollar_1)
 	return err
@@ -8,8 +8,18 @@ import (
 )
 
 const addPostLabel = `-- name: AddPostLabel :exec
-INSERT INTO post_labels (post_id, author_did, label)
-VALUES ($1, $2, $3) ON CONFLICT (post_id, label) DO NOTHING
+WITH added AS (
+    INSERT INTO post_labels (post_id, author_did, label)
+    VALUES ($1, $2, $3) ON CONFLICT (post_id, label) DO NOTHING
+    RETURNING post_id
+)
+UPDATE posts
+SET indexed_at = NULL
+WHERE id IN (
+        SELECT post_id
+        FROM added
+    )
+    AND indexed_at IS NOT NULL
 `
 
 type AddPostLabelParams struct {
@@ -18,6 +28,7 @@ type AddPostLabelParams struct {
 	Label     string `json:"label"`
 }
 
+// AddPostLabel adds a label to a post, the post is marked unindexed if the label is new so the search index picks it up.
 func (q *Queries) AddPostLabel(ctx context.Context, arg AddPostLabelParams) error {
 	_, err := q.exec(ctx, q.addPostLabelStmt, addPostLabel, arg.PostID, arg.AuthorDid, arg.Label)
 	return err
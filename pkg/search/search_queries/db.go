@@ -31,12 +31,30 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.addAuthorToClusterStmt, err = db.PrepareContext(ctx, addAuthorToCluster); err != nil {
 		return nil, fmt.Errorf("error preparing query AddAuthorToCluster: %w", err)
 	}
+	if q.addAuthorTombstoneStmt, err = db.PrepareContext(ctx, addAuthorTombstone); err != nil {
+		return nil, fmt.Errorf("error preparing query AddAuthorTombstone: %w", err)
+	}
+	if q.addAuthorsStmt, err = db.PrepareContext(ctx, addAuthors); err != nil {
+		return nil, fmt.Errorf("error preparing query AddAuthors: %w", err)
+	}
 	if q.addClusterStmt, err = db.PrepareContext(ctx, addCluster); err != nil {
 		return nil, fmt.Errorf("error preparing query AddCluster: %w", err)
 	}
+	if q.addFollowStmt, err = db.PrepareContext(ctx, addFollow); err != nil {
+		return nil, fmt.Errorf("error preparing query AddFollow: %w", err)
+	}
 	if q.addImageStmt, err = db.PrepareContext(ctx, addImage); err != nil {
 		return nil, fmt.Errorf("error preparing query AddImage: %w", err)
 	}
+	if q.addImageDetectionsStmt, err = db.PrepareContext(ctx, addImageDetections); err != nil {
+		return nil, fmt.Errorf("error preparing query AddImageDetections: %w", err)
+	}
+	if q.addImageHashStmt, err = db.PrepareContext(ctx, addImageHash); err != nil {
+		return nil, fmt.Errorf("error preparing query AddImageHash: %w", err)
+	}
+	if q.addImagesStmt, err = db.PrepareContext(ctx, addImages); err != nil {
+		return nil, fmt.Errorf("error preparing query AddImages: %w", err)
+	}
 	if q.addLabelStmt, err = db.PrepareContext(ctx, addLabel); err != nil {
 		return nil, fmt.Errorf("error preparing query AddLabel: %w", err)
 	}
@@ -46,15 +64,60 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.addLikeToPostStmt, err = db.PrepareContext(ctx, addLikeToPost); err != nil {
 		return nil, fmt.Errorf("error preparing query AddLikeToPost: %w", err)
 	}
+	if q.addLikesToPostsStmt, err = db.PrepareContext(ctx, addLikesToPosts); err != nil {
+		return nil, fmt.Errorf("error preparing query AddLikesToPosts: %w", err)
+	}
+	if q.addListItemStmt, err = db.PrepareContext(ctx, addListItem); err != nil {
+		return nil, fmt.Errorf("error preparing query AddListItem: %w", err)
+	}
 	if q.addPostStmt, err = db.PrepareContext(ctx, addPost); err != nil {
 		return nil, fmt.Errorf("error preparing query AddPost: %w", err)
 	}
+	if q.addPostDeclaredLangsStmt, err = db.PrepareContext(ctx, addPostDeclaredLangs); err != nil {
+		return nil, fmt.Errorf("error preparing query AddPostDeclaredLangs: %w", err)
+	}
 	if q.addPostLabelStmt, err = db.PrepareContext(ctx, addPostLabel); err != nil {
 		return nil, fmt.Errorf("error preparing query AddPostLabel: %w", err)
 	}
+	if q.addPostLinksStmt, err = db.PrepareContext(ctx, addPostLinks); err != nil {
+		return nil, fmt.Errorf("error preparing query AddPostLinks: %w", err)
+	}
+	if q.addPostMentionsStmt, err = db.PrepareContext(ctx, addPostMentions); err != nil {
+		return nil, fmt.Errorf("error preparing query AddPostMentions: %w", err)
+	}
+	if q.addPostTagsStmt, err = db.PrepareContext(ctx, addPostTags); err != nil {
+		return nil, fmt.Errorf("error preparing query AddPostTags: %w", err)
+	}
+	if q.addPostsStmt, err = db.PrepareContext(ctx, addPosts); err != nil {
+		return nil, fmt.Errorf("error preparing query AddPosts: %w", err)
+	}
+	if q.addRepostStmt, err = db.PrepareContext(ctx, addRepost); err != nil {
+		return nil, fmt.Errorf("error preparing query AddRepost: %w", err)
+	}
 	if q.assignLabelToAuthorStmt, err = db.PrepareContext(ctx, assignLabelToAuthor); err != nil {
 		return nil, fmt.Errorf("error preparing query AssignLabelToAuthor: %w", err)
 	}
+	if q.backfillPostsInsertedAtStmt, err = db.PrepareContext(ctx, backfillPostsInsertedAt); err != nil {
+		return nil, fmt.Errorf("error preparing query BackfillPostsInsertedAt: %w", err)
+	}
+	if q.deleteAuthorProfileStmt, err = db.PrepareContext(ctx, deleteAuthorProfile); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteAuthorProfile: %w", err)
+	}
+	if q.deleteFeedGeneratorStmt, err = db.PrepareContext(ctx, deleteFeedGenerator); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteFeedGenerator: %w", err)
+	}
+	if q.deleteListStmt, err = db.PrepareContext(ctx, deleteList); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteList: %w", err)
+	}
+	if q.deleteListItemStmt, err = db.PrepareContext(ctx, deleteListItem); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteListItem: %w", err)
+	}
+	if q.deleteListItemsForListStmt, err = db.PrepareContext(ctx, deleteListItemsForList); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteListItemsForList: %w", err)
+	}
+	if q.deletePostLabelsInsertedSinceStmt, err = db.PrepareContext(ctx, deletePostLabelsInsertedSince); err != nil {
+		return nil, fmt.Errorf("error preparing query DeletePostLabelsInsertedSince: %w", err)
+	}
 	if q.getAllLabelsStmt, err = db.PrepareContext(ctx, getAllLabels); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAllLabels: %w", err)
 	}
@@ -64,12 +127,27 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getAllUniquePostLabelsStmt, err = db.PrepareContext(ctx, getAllUniquePostLabels); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAllUniquePostLabels: %w", err)
 	}
+	if q.getAltTextCoverageStmt, err = db.PrepareContext(ctx, getAltTextCoverage); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAltTextCoverage: %w", err)
+	}
+	if q.getAltTextCoverageForAuthorStmt, err = db.PrepareContext(ctx, getAltTextCoverageForAuthor); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAltTextCoverageForAuthor: %w", err)
+	}
+	if q.getAltTextStatsStmt, err = db.PrepareContext(ctx, getAltTextStats); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAltTextStats: %w", err)
+	}
 	if q.getAuthorStmt, err = db.PrepareContext(ctx, getAuthor); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAuthor: %w", err)
 	}
 	if q.getAuthorBlockStmt, err = db.PrepareContext(ctx, getAuthorBlock); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAuthorBlock: %w", err)
 	}
+	if q.getAuthorProfileStmt, err = db.PrepareContext(ctx, getAuthorProfile); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAuthorProfile: %w", err)
+	}
+	if q.getAuthorProfilePageStmt, err = db.PrepareContext(ctx, getAuthorProfilePage); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAuthorProfilePage: %w", err)
+	}
 	if q.getAuthorStatsStmt, err = db.PrepareContext(ctx, getAuthorStats); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAuthorStats: %w", err)
 	}
@@ -85,18 +163,51 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getBlocksForTargetStmt, err = db.PrepareContext(ctx, getBlocksForTarget); err != nil {
 		return nil, fmt.Errorf("error preparing query GetBlocksForTarget: %w", err)
 	}
+	if q.getClassifierProgressStmt, err = db.PrepareContext(ctx, getClassifierProgress); err != nil {
+		return nil, fmt.Errorf("error preparing query GetClassifierProgress: %w", err)
+	}
 	if q.getClustersStmt, err = db.PrepareContext(ctx, getClusters); err != nil {
 		return nil, fmt.Errorf("error preparing query GetClusters: %w", err)
 	}
+	if q.getClustersForAuthorsStmt, err = db.PrepareContext(ctx, getClustersForAuthors); err != nil {
+		return nil, fmt.Errorf("error preparing query GetClustersForAuthors: %w", err)
+	}
+	if q.getDetectionsForImageStmt, err = db.PrepareContext(ctx, getDetectionsForImage); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDetectionsForImage: %w", err)
+	}
+	if q.getFeedGeneratorStmt, err = db.PrepareContext(ctx, getFeedGenerator); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFeedGenerator: %w", err)
+	}
+	if q.getFeedGeneratorsToCheckStmt, err = db.PrepareContext(ctx, getFeedGeneratorsToCheck); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFeedGeneratorsToCheck: %w", err)
+	}
+	if q.getFeedStatsStmt, err = db.PrepareContext(ctx, getFeedStats); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFeedStats: %w", err)
+	}
+	if q.getFollowerCountStmt, err = db.PrepareContext(ctx, getFollowerCount); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFollowerCount: %w", err)
+	}
+	if q.getFollowingCountStmt, err = db.PrepareContext(ctx, getFollowingCount); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFollowingCount: %w", err)
+	}
 	if q.getImageStmt, err = db.PrepareContext(ctx, getImage); err != nil {
 		return nil, fmt.Errorf("error preparing query GetImage: %w", err)
 	}
+	if q.getImageHashStmt, err = db.PrepareContext(ctx, getImageHash); err != nil {
+		return nil, fmt.Errorf("error preparing query GetImageHash: %w", err)
+	}
+	if q.getImagesByDetectionLabelStmt, err = db.PrepareContext(ctx, getImagesByDetectionLabel); err != nil {
+		return nil, fmt.Errorf("error preparing query GetImagesByDetectionLabel: %w", err)
+	}
 	if q.getImagesForAuthorDIDStmt, err = db.PrepareContext(ctx, getImagesForAuthorDID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetImagesForAuthorDID: %w", err)
 	}
 	if q.getImagesForPostStmt, err = db.PrepareContext(ctx, getImagesForPost); err != nil {
 		return nil, fmt.Errorf("error preparing query GetImagesForPost: %w", err)
 	}
+	if q.getImagesForPostsStmt, err = db.PrepareContext(ctx, getImagesForPosts); err != nil {
+		return nil, fmt.Errorf("error preparing query GetImagesForPosts: %w", err)
+	}
 	if q.getLabelByAliasStmt, err = db.PrepareContext(ctx, getLabelByAlias); err != nil {
 		return nil, fmt.Errorf("error preparing query GetLabelByAlias: %w", err)
 	}
@@ -106,21 +217,45 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getLabelsForAuthorStmt, err = db.PrepareContext(ctx, getLabelsForAuthor); err != nil {
 		return nil, fmt.Errorf("error preparing query GetLabelsForAuthor: %w", err)
 	}
+	if q.getLikeTotalsForPostsStmt, err = db.PrepareContext(ctx, getLikeTotalsForPosts); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLikeTotalsForPosts: %w", err)
+	}
+	if q.getListStmt, err = db.PrepareContext(ctx, getList); err != nil {
+		return nil, fmt.Errorf("error preparing query GetList: %w", err)
+	}
+	if q.getListMembersStmt, err = db.PrepareContext(ctx, getListMembers); err != nil {
+		return nil, fmt.Errorf("error preparing query GetListMembers: %w", err)
+	}
+	if q.getListedSubjectsStmt, err = db.PrepareContext(ctx, getListedSubjects); err != nil {
+		return nil, fmt.Errorf("error preparing query GetListedSubjects: %w", err)
+	}
+	if q.getListsForAuthorStmt, err = db.PrepareContext(ctx, getListsForAuthor); err != nil {
+		return nil, fmt.Errorf("error preparing query GetListsForAuthor: %w", err)
+	}
 	if q.getMembersOfAuthorLabelStmt, err = db.PrepareContext(ctx, getMembersOfAuthorLabel); err != nil {
 		return nil, fmt.Errorf("error preparing query GetMembersOfAuthorLabel: %w", err)
 	}
 	if q.getMembersOfClusterStmt, err = db.PrepareContext(ctx, getMembersOfCluster); err != nil {
 		return nil, fmt.Errorf("error preparing query GetMembersOfCluster: %w", err)
 	}
+	if q.getMutualFollowsStmt, err = db.PrepareContext(ctx, getMutualFollows); err != nil {
+		return nil, fmt.Errorf("error preparing query GetMutualFollows: %w", err)
+	}
 	if q.getOldestPresentParentStmt, err = db.PrepareContext(ctx, getOldestPresentParent); err != nil {
 		return nil, fmt.Errorf("error preparing query GetOldestPresentParent: %w", err)
 	}
+	if q.getOldestUnprocessedImageInsertedAtStmt, err = db.PrepareContext(ctx, getOldestUnprocessedImageInsertedAt); err != nil {
+		return nil, fmt.Errorf("error preparing query GetOldestUnprocessedImageInsertedAt: %w", err)
+	}
 	if q.getOptedOutAuthorsStmt, err = db.PrepareContext(ctx, getOptedOutAuthors); err != nil {
 		return nil, fmt.Errorf("error preparing query GetOptedOutAuthors: %w", err)
 	}
 	if q.getPostStmt, err = db.PrepareContext(ctx, getPost); err != nil {
 		return nil, fmt.Errorf("error preparing query GetPost: %w", err)
 	}
+	if q.getPostLanguagesStmt, err = db.PrepareContext(ctx, getPostLanguages); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPostLanguages: %w", err)
+	}
 	if q.getPostPageStmt, err = db.PrepareContext(ctx, getPostPage); err != nil {
 		return nil, fmt.Errorf("error preparing query GetPostPage: %w", err)
 	}
@@ -130,6 +265,18 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getPostWithAuthorHandleStmt, err = db.PrepareContext(ctx, getPostWithAuthorHandle); err != nil {
 		return nil, fmt.Errorf("error preparing query GetPostWithAuthorHandle: %w", err)
 	}
+	if q.getPostsAfterCursorStmt, err = db.PrepareContext(ctx, getPostsAfterCursor); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPostsAfterCursor: %w", err)
+	}
+	if q.getPostsLinkingToDomainStmt, err = db.PrepareContext(ctx, getPostsLinkingToDomain); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPostsLinkingToDomain: %w", err)
+	}
+	if q.getPostsLinkingToURLStmt, err = db.PrepareContext(ctx, getPostsLinkingToURL); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPostsLinkingToURL: %w", err)
+	}
+	if q.getPostsMissingAltTextStmt, err = db.PrepareContext(ctx, getPostsMissingAltText); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPostsMissingAltText: %w", err)
+	}
 	if q.getPostsPageByAuthorLabelAliasStmt, err = db.PrepareContext(ctx, getPostsPageByAuthorLabelAlias); err != nil {
 		return nil, fmt.Errorf("error preparing query GetPostsPageByAuthorLabelAlias: %w", err)
 	}
@@ -145,6 +292,9 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getPostsPageWithAnyPostLabelStmt, err = db.PrepareContext(ctx, getPostsPageWithAnyPostLabel); err != nil {
 		return nil, fmt.Errorf("error preparing query GetPostsPageWithAnyPostLabel: %w", err)
 	}
+	if q.getPostsPageWithAnyPostLabelChronologicalStmt, err = db.PrepareContext(ctx, getPostsPageWithAnyPostLabelChronological); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPostsPageWithAnyPostLabelChronological: %w", err)
+	}
 	if q.getPostsPageWithAnyPostLabelSortedByHotnessStmt, err = db.PrepareContext(ctx, getPostsPageWithAnyPostLabelSortedByHotness); err != nil {
 		return nil, fmt.Errorf("error preparing query GetPostsPageWithAnyPostLabelSortedByHotness: %w", err)
 	}
@@ -157,24 +307,75 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getPostsPageWithPostLabelSortedByHotnessStmt, err = db.PrepareContext(ctx, getPostsPageWithPostLabelSortedByHotness); err != nil {
 		return nil, fmt.Errorf("error preparing query GetPostsPageWithPostLabelSortedByHotness: %w", err)
 	}
+	if q.getPostsWithSimilarImagesStmt, err = db.PrepareContext(ctx, getPostsWithSimilarImages); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPostsWithSimilarImages: %w", err)
+	}
+	if q.getRepostCountStmt, err = db.PrepareContext(ctx, getRepostCount); err != nil {
+		return nil, fmt.Errorf("error preparing query GetRepostCount: %w", err)
+	}
+	if q.getRepostersForPostStmt, err = db.PrepareContext(ctx, getRepostersForPost); err != nil {
+		return nil, fmt.Errorf("error preparing query GetRepostersForPost: %w", err)
+	}
+	if q.getSimilarImagesStmt, err = db.PrepareContext(ctx, getSimilarImages); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSimilarImages: %w", err)
+	}
+	if q.getTagsForPostsStmt, err = db.PrepareContext(ctx, getTagsForPosts); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTagsForPosts: %w", err)
+	}
 	if q.getThreadViewStmt, err = db.PrepareContext(ctx, getThreadView); err != nil {
 		return nil, fmt.Errorf("error preparing query GetThreadView: %w", err)
 	}
+	if q.getTombstonedAuthorsStmt, err = db.PrepareContext(ctx, getTombstonedAuthors); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTombstonedAuthors: %w", err)
+	}
+	if q.getTopDetectionLabelsStmt, err = db.PrepareContext(ctx, getTopDetectionLabels); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTopDetectionLabels: %w", err)
+	}
+	if q.getTopLinkDomainsStmt, err = db.PrepareContext(ctx, getTopLinkDomains); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTopLinkDomains: %w", err)
+	}
 	if q.getTopPostersStmt, err = db.PrepareContext(ctx, getTopPosters); err != nil {
 		return nil, fmt.Errorf("error preparing query GetTopPosters: %w", err)
 	}
+	if q.getUnhashedImagesStmt, err = db.PrepareContext(ctx, getUnhashedImages); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUnhashedImages: %w", err)
+	}
 	if q.getUnindexedPostPageStmt, err = db.PrepareContext(ctx, getUnindexedPostPage); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUnindexedPostPage: %w", err)
 	}
 	if q.getUnprocessedImagesStmt, err = db.PrepareContext(ctx, getUnprocessedImages); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUnprocessedImages: %w", err)
 	}
+	if q.incrementFeedGeneratorLikeCountStmt, err = db.PrepareContext(ctx, incrementFeedGeneratorLikeCount); err != nil {
+		return nil, fmt.Errorf("error preparing query IncrementFeedGeneratorLikeCount: %w", err)
+	}
+	if q.indexPostSearchVectorsStmt, err = db.PrepareContext(ctx, indexPostSearchVectors); err != nil {
+		return nil, fmt.Errorf("error preparing query IndexPostSearchVectors: %w", err)
+	}
 	if q.removeAuthorBlockStmt, err = db.PrepareContext(ctx, removeAuthorBlock); err != nil {
 		return nil, fmt.Errorf("error preparing query RemoveAuthorBlock: %w", err)
 	}
+	if q.removeFollowStmt, err = db.PrepareContext(ctx, removeFollow); err != nil {
+		return nil, fmt.Errorf("error preparing query RemoveFollow: %w", err)
+	}
 	if q.removeLikeFromPostStmt, err = db.PrepareContext(ctx, removeLikeFromPost); err != nil {
 		return nil, fmt.Errorf("error preparing query RemoveLikeFromPost: %w", err)
 	}
+	if q.removeRepostStmt, err = db.PrepareContext(ctx, removeRepost); err != nil {
+		return nil, fmt.Errorf("error preparing query RemoveRepost: %w", err)
+	}
+	if q.searchAuthorsStmt, err = db.PrepareContext(ctx, searchAuthors); err != nil {
+		return nil, fmt.Errorf("error preparing query SearchAuthors: %w", err)
+	}
+	if q.searchFeedGeneratorsStmt, err = db.PrepareContext(ctx, searchFeedGenerators); err != nil {
+		return nil, fmt.Errorf("error preparing query SearchFeedGenerators: %w", err)
+	}
+	if q.searchPostsStmt, err = db.PrepareContext(ctx, searchPosts); err != nil {
+		return nil, fmt.Errorf("error preparing query SearchPosts: %w", err)
+	}
+	if q.setPostDetectedLangsStmt, err = db.PrepareContext(ctx, setPostDetectedLangs); err != nil {
+		return nil, fmt.Errorf("error preparing query SetPostDetectedLangs: %w", err)
+	}
 	if q.setPostIndexedTimestampStmt, err = db.PrepareContext(ctx, setPostIndexedTimestamp); err != nil {
 		return nil, fmt.Errorf("error preparing query SetPostIndexedTimestamp: %w", err)
 	}
@@ -184,12 +385,33 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.unassignLabelFromAuthorStmt, err = db.PrepareContext(ctx, unassignLabelFromAuthor); err != nil {
 		return nil, fmt.Errorf("error preparing query UnassignLabelFromAuthor: %w", err)
 	}
+	if q.updateAuthorHandleStmt, err = db.PrepareContext(ctx, updateAuthorHandle); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateAuthorHandle: %w", err)
+	}
 	if q.updateAuthorOptOutStmt, err = db.PrepareContext(ctx, updateAuthorOptOut); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateAuthorOptOut: %w", err)
 	}
+	if q.updateClassifierProgressStmt, err = db.PrepareContext(ctx, updateClassifierProgress); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateClassifierProgress: %w", err)
+	}
+	if q.updateFeedGeneratorHealthStmt, err = db.PrepareContext(ctx, updateFeedGeneratorHealth); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateFeedGeneratorHealth: %w", err)
+	}
 	if q.updateImageStmt, err = db.PrepareContext(ctx, updateImage); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateImage: %w", err)
 	}
+	if q.upsertAuthorProfileStmt, err = db.PrepareContext(ctx, upsertAuthorProfile); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertAuthorProfile: %w", err)
+	}
+	if q.upsertFeedGeneratorStmt, err = db.PrepareContext(ctx, upsertFeedGenerator); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertFeedGenerator: %w", err)
+	}
+	if q.upsertFeedStatsStmt, err = db.PrepareContext(ctx, upsertFeedStats); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertFeedStats: %w", err)
+	}
+	if q.upsertListStmt, err = db.PrepareContext(ctx, upsertList); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertList: %w", err)
+	}
 	return &q, nil
 }
 
@@ -210,16 +432,46 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing addAuthorToClusterStmt: %w", cerr)
 		}
 	}
+	if q.addAuthorTombstoneStmt != nil {
+		if cerr := q.addAuthorTombstoneStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addAuthorTombstoneStmt: %w", cerr)
+		}
+	}
+	if q.addAuthorsStmt != nil {
+		if cerr := q.addAuthorsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addAuthorsStmt: %w", cerr)
+		}
+	}
 	if q.addClusterStmt != nil {
 		if cerr := q.addClusterStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing addClusterStmt: %w", cerr)
 		}
 	}
+	if q.addFollowStmt != nil {
+		if cerr := q.addFollowStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addFollowStmt: %w", cerr)
+		}
+	}
 	if q.addImageStmt != nil {
 		if cerr := q.addImageStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing addImageStmt: %w", cerr)
 		}
 	}
+	if q.addImageDetectionsStmt != nil {
+		if cerr := q.addImageDetectionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addImageDetectionsStmt: %w", cerr)
+		}
+	}
+	if q.addImageHashStmt != nil {
+		if cerr := q.addImageHashStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addImageHashStmt: %w", cerr)
+		}
+	}
+	if q.addImagesStmt != nil {
+		if cerr := q.addImagesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addImagesStmt: %w", cerr)
+		}
+	}
 	if q.addLabelStmt != nil {
 		if cerr := q.addLabelStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing addLabelStmt: %w", cerr)
@@ -235,21 +487,96 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing addLikeToPostStmt: %w", cerr)
 		}
 	}
+	if q.addLikesToPostsStmt != nil {
+		if cerr := q.addLikesToPostsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addLikesToPostsStmt: %w", cerr)
+		}
+	}
+	if q.addListItemStmt != nil {
+		if cerr := q.addListItemStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addListItemStmt: %w", cerr)
+		}
+	}
 	if q.addPostStmt != nil {
 		if cerr := q.addPostStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing addPostStmt: %w", cerr)
 		}
 	}
+	if q.addPostDeclaredLangsStmt != nil {
+		if cerr := q.addPostDeclaredLangsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addPostDeclaredLangsStmt: %w", cerr)
+		}
+	}
 	if q.addPostLabelStmt != nil {
 		if cerr := q.addPostLabelStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing addPostLabelStmt: %w", cerr)
 		}
 	}
+	if q.addPostLinksStmt != nil {
+		if cerr := q.addPostLinksStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addPostLinksStmt: %w", cerr)
+		}
+	}
+	if q.addPostMentionsStmt != nil {
+		if cerr := q.addPostMentionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addPostMentionsStmt: %w", cerr)
+		}
+	}
+	if q.addPostTagsStmt != nil {
+		if cerr := q.addPostTagsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addPostTagsStmt: %w", cerr)
+		}
+	}
+	if q.addPostsStmt != nil {
+		if cerr := q.addPostsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addPostsStmt: %w", cerr)
+		}
+	}
+	if q.addRepostStmt != nil {
+		if cerr := q.addRepostStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addRepostStmt: %w", cerr)
+		}
+	}
 	if q.assignLabelToAuthorStmt != nil {
 		if cerr := q.assignLabelToAuthorStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing assignLabelToAuthorStmt: %w", cerr)
 		}
 	}
+	if q.backfillPostsInsertedAtStmt != nil {
+		if cerr := q.backfillPostsInsertedAtStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing backfillPostsInsertedAtStmt: %w", cerr)
+		}
+	}
+	if q.deleteAuthorProfileStmt != nil {
+		if cerr := q.deleteAuthorProfileStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteAuthorProfileStmt: %w", cerr)
+		}
+	}
+	if q.deleteFeedGeneratorStmt != nil {
+		if cerr := q.deleteFeedGeneratorStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteFeedGeneratorStmt: %w", cerr)
+		}
+	}
+	if q.deleteListStmt != nil {
+		if cerr := q.deleteListStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteListStmt: %w", cerr)
+		}
+	}
+	if q.deleteListItemStmt != nil {
+		if cerr := q.deleteListItemStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteListItemStmt: %w", cerr)
+		}
+	}
+	if q.deleteListItemsForListStmt != nil {
+		if cerr := q.deleteListItemsForListStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteListItemsForListStmt: %w", cerr)
+		}
+	}
+	if q.deletePostLabelsInsertedSinceStmt != nil {
+		if cerr := q.deletePostLabelsInsertedSinceStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deletePostLabelsInsertedSinceStmt: %w", cerr)
+		}
+	}
 	if q.getAllLabelsStmt != nil {
 		if cerr := q.getAllLabelsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getAllLabelsStmt: %w", cerr)
@@ -265,6 +592,21 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getAllUniquePostLabelsStmt: %w", cerr)
 		}
 	}
+	if q.getAltTextCoverageStmt != nil {
+		if cerr := q.getAltTextCoverageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAltTextCoverageStmt: %w", cerr)
+		}
+	}
+	if q.getAltTextCoverageForAuthorStmt != nil {
+		if cerr := q.getAltTextCoverageForAuthorStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAltTextCoverageForAuthorStmt: %w", cerr)
+		}
+	}
+	if q.getAltTextStatsStmt != nil {
+		if cerr := q.getAltTextStatsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAltTextStatsStmt: %w", cerr)
+		}
+	}
 	if q.getAuthorStmt != nil {
 		if cerr := q.getAuthorStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getAuthorStmt: %w", cerr)
@@ -275,6 +617,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getAuthorBlockStmt: %w", cerr)
 		}
 	}
+	if q.getAuthorProfileStmt != nil {
+		if cerr := q.getAuthorProfileStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAuthorProfileStmt: %w", cerr)
+		}
+	}
+	if q.getAuthorProfilePageStmt != nil {
+		if cerr := q.getAuthorProfilePageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAuthorProfilePageStmt: %w", cerr)
+		}
+	}
 	if q.getAuthorStatsStmt != nil {
 		if cerr := q.getAuthorStatsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getAuthorStatsStmt: %w", cerr)
@@ -300,16 +652,66 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getBlocksForTargetStmt: %w", cerr)
 		}
 	}
+	if q.getClassifierProgressStmt != nil {
+		if cerr := q.getClassifierProgressStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getClassifierProgressStmt: %w", cerr)
+		}
+	}
 	if q.getClustersStmt != nil {
 		if cerr := q.getClustersStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getClustersStmt: %w", cerr)
 		}
 	}
+	if q.getClustersForAuthorsStmt != nil {
+		if cerr := q.getClustersForAuthorsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getClustersForAuthorsStmt: %w", cerr)
+		}
+	}
+	if q.getDetectionsForImageStmt != nil {
+		if cerr := q.getDetectionsForImageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDetectionsForImageStmt: %w", cerr)
+		}
+	}
+	if q.getFeedGeneratorStmt != nil {
+		if cerr := q.getFeedGeneratorStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFeedGeneratorStmt: %w", cerr)
+		}
+	}
+	if q.getFeedGeneratorsToCheckStmt != nil {
+		if cerr := q.getFeedGeneratorsToCheckStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFeedGeneratorsToCheckStmt: %w", cerr)
+		}
+	}
+	if q.getFeedStatsStmt != nil {
+		if cerr := q.getFeedStatsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFeedStatsStmt: %w", cerr)
+		}
+	}
+	if q.getFollowerCountStmt != nil {
+		if cerr := q.getFollowerCountStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFollowerCountStmt: %w", cerr)
+		}
+	}
+	if q.getFollowingCountStmt != nil {
+		if cerr := q.getFollowingCountStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFollowingCountStmt: %w", cerr)
+		}
+	}
 	if q.getImageStmt != nil {
 		if cerr := q.getImageStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getImageStmt: %w", cerr)
 		}
 	}
+	if q.getImageHashStmt != nil {
+		if cerr := q.getImageHashStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getImageHashStmt: %w", cerr)
+		}
+	}
+	if q.getImagesByDetectionLabelStmt != nil {
+		if cerr := q.getImagesByDetectionLabelStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getImagesByDetectionLabelStmt: %w", cerr)
+		}
+	}
 	if q.getImagesForAuthorDIDStmt != nil {
 		if cerr := q.getImagesForAuthorDIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getImagesForAuthorDIDStmt: %w", cerr)
@@ -320,6 +722,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getImagesForPostStmt: %w", cerr)
 		}
 	}
+	if q.getImagesForPostsStmt != nil {
+		if cerr := q.getImagesForPostsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getImagesForPostsStmt: %w", cerr)
+		}
+	}
 	if q.getLabelByAliasStmt != nil {
 		if cerr := q.getLabelByAliasStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getLabelByAliasStmt: %w", cerr)
@@ -335,6 +742,31 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getLabelsForAuthorStmt: %w", cerr)
 		}
 	}
+	if q.getLikeTotalsForPostsStmt != nil {
+		if cerr := q.getLikeTotalsForPostsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLikeTotalsForPostsStmt: %w", cerr)
+		}
+	}
+	if q.getListStmt != nil {
+		if cerr := q.getListStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getListStmt: %w", cerr)
+		}
+	}
+	if q.getListMembersStmt != nil {
+		if cerr := q.getListMembersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getListMembersStmt: %w", cerr)
+		}
+	}
+	if q.getListedSubjectsStmt != nil {
+		if cerr := q.getListedSubjectsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getListedSubjectsStmt: %w", cerr)
+		}
+	}
+	if q.getListsForAuthorStmt != nil {
+		if cerr := q.getListsForAuthorStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getListsForAuthorStmt: %w", cerr)
+		}
+	}
 	if q.getMembersOfAuthorLabelStmt != nil {
 		if cerr := q.getMembersOfAuthorLabelStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getMembersOfAuthorLabelStmt: %w", cerr)
@@ -345,11 +777,21 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getMembersOfClusterStmt: %w", cerr)
 		}
 	}
+	if q.getMutualFollowsStmt != nil {
+		if cerr := q.getMutualFollowsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getMutualFollowsStmt: %w", cerr)
+		}
+	}
 	if q.getOldestPresentParentStmt != nil {
 		if cerr := q.getOldestPresentParentStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getOldestPresentParentStmt: %w", cerr)
 		}
 	}
+	if q.getOldestUnprocessedImageInsertedAtStmt != nil {
+		if cerr := q.getOldestUnprocessedImageInsertedAtStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getOldestUnprocessedImageInsertedAtStmt: %w", cerr)
+		}
+	}
 	if q.getOptedOutAuthorsStmt != nil {
 		if cerr := q.getOptedOutAuthorsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getOptedOutAuthorsStmt: %w", cerr)
@@ -360,6 +802,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getPostStmt: %w", cerr)
 		}
 	}
+	if q.getPostLanguagesStmt != nil {
+		if cerr := q.getPostLanguagesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPostLanguagesStmt: %w", cerr)
+		}
+	}
 	if q.getPostPageStmt != nil {
 		if cerr := q.getPostPageStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getPostPageStmt: %w", cerr)
@@ -375,6 +822,26 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getPostWithAuthorHandleStmt: %w", cerr)
 		}
 	}
+	if q.getPostsAfterCursorStmt != nil {
+		if cerr := q.getPostsAfterCursorStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPostsAfterCursorStmt: %w", cerr)
+		}
+	}
+	if q.getPostsLinkingToDomainStmt != nil {
+		if cerr := q.getPostsLinkingToDomainStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPostsLinkingToDomainStmt: %w", cerr)
+		}
+	}
+	if q.getPostsLinkingToURLStmt != nil {
+		if cerr := q.getPostsLinkingToURLStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPostsLinkingToURLStmt: %w", cerr)
+		}
+	}
+	if q.getPostsMissingAltTextStmt != nil {
+		if cerr := q.getPostsMissingAltTextStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPostsMissingAltTextStmt: %w", cerr)
+		}
+	}
 	if q.getPostsPageByAuthorLabelAliasStmt != nil {
 		if cerr := q.getPostsPageByAuthorLabelAliasStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getPostsPageByAuthorLabelAliasStmt: %w", cerr)
@@ -400,6 +867,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getPostsPageWithAnyPostLabelStmt: %w", cerr)
 		}
 	}
+	if q.getPostsPageWithAnyPostLabelChronologicalStmt != nil {
+		if cerr := q.getPostsPageWithAnyPostLabelChronologicalStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPostsPageWithAnyPostLabelChronologicalStmt: %w", cerr)
+		}
+	}
 	if q.getPostsPageWithAnyPostLabelSortedByHotnessStmt != nil {
 		if cerr := q.getPostsPageWithAnyPostLabelSortedByHotnessStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getPostsPageWithAnyPostLabelSortedByHotnessStmt: %w", cerr)
@@ -420,16 +892,61 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getPostsPageWithPostLabelSortedByHotnessStmt: %w", cerr)
 		}
 	}
+	if q.getPostsWithSimilarImagesStmt != nil {
+		if cerr := q.getPostsWithSimilarImagesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPostsWithSimilarImagesStmt: %w", cerr)
+		}
+	}
+	if q.getRepostCountStmt != nil {
+		if cerr := q.getRepostCountStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getRepostCountStmt: %w", cerr)
+		}
+	}
+	if q.getRepostersForPostStmt != nil {
+		if cerr := q.getRepostersForPostStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getRepostersForPostStmt: %w", cerr)
+		}
+	}
+	if q.getSimilarImagesStmt != nil {
+		if cerr := q.getSimilarImagesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSimilarImagesStmt: %w", cerr)
+		}
+	}
+	if q.getTagsForPostsStmt != nil {
+		if cerr := q.getTagsForPostsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTagsForPostsStmt: %w", cerr)
+		}
+	}
 	if q.getThreadViewStmt != nil {
 		if cerr := q.getThreadViewStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getThreadViewStmt: %w", cerr)
 		}
 	}
+	if q.getTombstonedAuthorsStmt != nil {
+		if cerr := q.getTombstonedAuthorsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTombstonedAuthorsStmt: %w", cerr)
+		}
+	}
+	if q.getTopDetectionLabelsStmt != nil {
+		if cerr := q.getTopDetectionLabelsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTopDetectionLabelsStmt: %w", cerr)
+		}
+	}
+	if q.getTopLinkDomainsStmt != nil {
+		if cerr := q.getTopLinkDomainsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTopLinkDomainsStmt: %w", cerr)
+		}
+	}
 	if q.getTopPostersStmt != nil {
 		if cerr := q.getTopPostersStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getTopPostersStmt: %w", cerr)
 		}
 	}
+	if q.getUnhashedImagesStmt != nil {
+		if cerr := q.getUnhashedImagesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUnhashedImagesStmt: %w", cerr)
+		}
+	}
 	if q.getUnindexedPostPageStmt != nil {
 		if cerr := q.getUnindexedPostPageStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getUnindexedPostPageStmt: %w", cerr)
@@ -440,16 +957,56 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getUnprocessedImagesStmt: %w", cerr)
 		}
 	}
+	if q.incrementFeedGeneratorLikeCountStmt != nil {
+		if cerr := q.incrementFeedGeneratorLikeCountStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing incrementFeedGeneratorLikeCountStmt: %w", cerr)
+		}
+	}
+	if q.indexPostSearchVectorsStmt != nil {
+		if cerr := q.indexPostSearchVectorsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing indexPostSearchVectorsStmt: %w", cerr)
+		}
+	}
 	if q.removeAuthorBlockStmt != nil {
 		if cerr := q.removeAuthorBlockStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing removeAuthorBlockStmt: %w", cerr)
 		}
 	}
+	if q.removeFollowStmt != nil {
+		if cerr := q.removeFollowStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing removeFollowStmt: %w", cerr)
+		}
+	}
 	if q.removeLikeFromPostStmt != nil {
 		if cerr := q.removeLikeFromPostStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing removeLikeFromPostStmt: %w", cerr)
 		}
 	}
+	if q.removeRepostStmt != nil {
+		if cerr := q.removeRepostStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing removeRepostStmt: %w", cerr)
+		}
+	}
+	if q.searchAuthorsStmt != nil {
+		if cerr := q.searchAuthorsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing searchAuthorsStmt: %w", cerr)
+		}
+	}
+	if q.searchFeedGeneratorsStmt != nil {
+		if cerr := q.searchFeedGeneratorsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing searchFeedGeneratorsStmt: %w", cerr)
+		}
+	}
+	if q.searchPostsStmt != nil {
+		if cerr := q.searchPostsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing searchPostsStmt: %w", cerr)
+		}
+	}
+	if q.setPostDetectedLangsStmt != nil {
+		if cerr := q.setPostDetectedLangsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setPostDetectedLangsStmt: %w", cerr)
+		}
+	}
 	if q.setPostIndexedTimestampStmt != nil {
 		if cerr := q.setPostIndexedTimestampStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing setPostIndexedTimestampStmt: %w", cerr)
@@ -465,16 +1022,51 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing unassignLabelFromAuthorStmt: %w", cerr)
 		}
 	}
+	if q.updateAuthorHandleStmt != nil {
+		if cerr := q.updateAuthorHandleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateAuthorHandleStmt: %w", cerr)
+		}
+	}
 	if q.updateAuthorOptOutStmt != nil {
 		if cerr := q.updateAuthorOptOutStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateAuthorOptOutStmt: %w", cerr)
 		}
 	}
+	if q.updateClassifierProgressStmt != nil {
+		if cerr := q.updateClassifierProgressStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateClassifierProgressStmt: %w", cerr)
+		}
+	}
+	if q.updateFeedGeneratorHealthStmt != nil {
+		if cerr := q.updateFeedGeneratorHealthStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateFeedGeneratorHealthStmt: %w", cerr)
+		}
+	}
 	if q.updateImageStmt != nil {
 		if cerr := q.updateImageStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateImageStmt: %w", cerr)
 		}
 	}
+	if q.upsertAuthorProfileStmt != nil {
+		if cerr := q.upsertAuthorProfileStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertAuthorProfileStmt: %w", cerr)
+		}
+	}
+	if q.upsertFeedGeneratorStmt != nil {
+		if cerr := q.upsertFeedGeneratorStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertFeedGeneratorStmt: %w", cerr)
+		}
+	}
+	if q.upsertFeedStatsStmt != nil {
+		if cerr := q.upsertFeedStatsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertFeedStatsStmt: %w", cerr)
+		}
+	}
+	if q.upsertListStmt != nil {
+		if cerr := q.upsertListStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertListStmt: %w", cerr)
+		}
+	}
 	return err
 }
 
@@ -517,120 +1109,268 @@ type Queries struct {
 	addAuthorStmt                                   *sql.Stmt
 	addAuthorBlockStmt                              *sql.Stmt
 	addAuthorToClusterStmt                          *sql.Stmt
+	addAuthorTombstoneStmt                          *sql.Stmt
+	addAuthorsStmt                                  *sql.Stmt
 	addClusterStmt                                  *sql.Stmt
+	addFollowStmt                                   *sql.Stmt
 	addImageStmt                                    *sql.Stmt
+	addImageDetectionsStmt                          *sql.Stmt
+	addImageHashStmt                                *sql.Stmt
+	addImagesStmt                                   *sql.Stmt
 	addLabelStmt                                    *sql.Stmt
 	addLabelsToPostsStmt                            *sql.Stmt
 	addLikeToPostStmt                               *sql.Stmt
+	addLikesToPostsStmt                             *sql.Stmt
+	addListItemStmt                                 *sql.Stmt
 	addPostStmt                                     *sql.Stmt
+	addPostDeclaredLangsStmt                        *sql.Stmt
 	addPostLabelStmt                                *sql.Stmt
+	addPostLinksStmt                                *sql.Stmt
+	addPostMentionsStmt                             *sql.Stmt
+	addPostTagsStmt                                 *sql.Stmt
+	addPostsStmt                                    *sql.Stmt
+	addRepostStmt                                   *sql.Stmt
 	assignLabelToAuthorStmt                         *sql.Stmt
+	backfillPostsInsertedAtStmt                     *sql.Stmt
+	deleteAuthorProfileStmt                         *sql.Stmt
+	deleteFeedGeneratorStmt                         *sql.Stmt
+	deleteListStmt                                  *sql.Stmt
+	deleteListItemStmt                              *sql.Stmt
+	deleteListItemsForListStmt                      *sql.Stmt
+	deletePostLabelsInsertedSinceStmt               *sql.Stmt
 	getAllLabelsStmt                                *sql.Stmt
 	getAllTimeBangersStmt                           *sql.Stmt
 	getAllUniquePostLabelsStmt                      *sql.Stmt
+	getAltTextCoverageStmt                          *sql.Stmt
+	getAltTextCoverageForAuthorStmt                 *sql.Stmt
+	getAltTextStatsStmt                             *sql.Stmt
 	getAuthorStmt                                   *sql.Stmt
 	getAuthorBlockStmt                              *sql.Stmt
+	getAuthorProfileStmt                            *sql.Stmt
+	getAuthorProfilePageStmt                        *sql.Stmt
 	getAuthorStatsStmt                              *sql.Stmt
 	getAuthorsByHandleStmt                          *sql.Stmt
 	getBangersForAuthorStmt                         *sql.Stmt
 	getBlockedByCountForTargetStmt                  *sql.Stmt
 	getBlocksForTargetStmt                          *sql.Stmt
+	getClassifierProgressStmt                       *sql.Stmt
 	getClustersStmt                                 *sql.Stmt
+	getClustersForAuthorsStmt                       *sql.Stmt
+	getDetectionsForImageStmt                       *sql.Stmt
+	getFeedGeneratorStmt                            *sql.Stmt
+	getFeedGeneratorsToCheckStmt                    *sql.Stmt
+	getFeedStatsStmt                                *sql.Stmt
+	getFollowerCountStmt                            *sql.Stmt
+	getFollowingCountStmt                           *sql.Stmt
 	getImageStmt                                    *sql.Stmt
+	getImageHashStmt                                *sql.Stmt
+	getImagesByDetectionLabelStmt                   *sql.Stmt
 	getImagesForAuthorDIDStmt                       *sql.Stmt
 	getImagesForPostStmt                            *sql.Stmt
+	getImagesForPostsStmt                           *sql.Stmt
 	getLabelByAliasStmt                             *sql.Stmt
 	getLabelsStmt                                   *sql.Stmt
 	getLabelsForAuthorStmt                          *sql.Stmt
+	getLikeTotalsForPostsStmt                       *sql.Stmt
+	getListStmt                                     *sql.Stmt
+	getListMembersStmt                              *sql.Stmt
+	getListedSubjectsStmt                           *sql.Stmt
+	getListsForAuthorStmt                           *sql.Stmt
 	getMembersOfAuthorLabelStmt                     *sql.Stmt
 	getMembersOfClusterStmt                         *sql.Stmt
+	getMutualFollowsStmt                            *sql.Stmt
 	getOldestPresentParentStmt                      *sql.Stmt
+	getOldestUnprocessedImageInsertedAtStmt         *sql.Stmt
 	getOptedOutAuthorsStmt                          *sql.Stmt
 	getPostStmt                                     *sql.Stmt
+	getPostLanguagesStmt                            *sql.Stmt
 	getPostPageStmt                                 *sql.Stmt
 	getPostPageCursorStmt                           *sql.Stmt
 	getPostWithAuthorHandleStmt                     *sql.Stmt
+	getPostsAfterCursorStmt                         *sql.Stmt
+	getPostsLinkingToDomainStmt                     *sql.Stmt
+	getPostsLinkingToURLStmt                        *sql.Stmt
+	getPostsMissingAltTextStmt                      *sql.Stmt
 	getPostsPageByAuthorLabelAliasStmt              *sql.Stmt
 	getPostsPageByAuthorLabelAliasFromViewStmt      *sql.Stmt
 	getPostsPageByClusterAliasStmt                  *sql.Stmt
 	getPostsPageByClusterAliasFromViewStmt          *sql.Stmt
 	getPostsPageWithAnyPostLabelStmt                *sql.Stmt
+	getPostsPageWithAnyPostLabelChronologicalStmt   *sql.Stmt
 	getPostsPageWithAnyPostLabelSortedByHotnessStmt *sql.Stmt
 	getPostsPageWithPostLabelStmt                   *sql.Stmt
 	getPostsPageWithPostLabelChronologicalStmt      *sql.Stmt
 	getPostsPageWithPostLabelSortedByHotnessStmt    *sql.Stmt
+	getPostsWithSimilarImagesStmt                   *sql.Stmt
+	getRepostCountStmt                              *sql.Stmt
+	getRepostersForPostStmt                         *sql.Stmt
+	getSimilarImagesStmt                            *sql.Stmt
+	getTagsForPostsStmt                             *sql.Stmt
 	getThreadViewStmt                               *sql.Stmt
+	getTombstonedAuthorsStmt                        *sql.Stmt
+	getTopDetectionLabelsStmt                       *sql.Stmt
+	getTopLinkDomainsStmt                           *sql.Stmt
 	getTopPostersStmt                               *sql.Stmt
+	getUnhashedImagesStmt                           *sql.Stmt
 	getUnindexedPostPageStmt                        *sql.Stmt
 	getUnprocessedImagesStmt                        *sql.Stmt
+	incrementFeedGeneratorLikeCountStmt             *sql.Stmt
+	indexPostSearchVectorsStmt                      *sql.Stmt
 	removeAuthorBlockStmt                           *sql.Stmt
+	removeFollowStmt                                *sql.Stmt
 	removeLikeFromPostStmt                          *sql.Stmt
+	removeRepostStmt                                *sql.Stmt
+	searchAuthorsStmt                               *sql.Stmt
+	searchFeedGeneratorsStmt                        *sql.Stmt
+	searchPostsStmt                                 *sql.Stmt
+	setPostDetectedLangsStmt                        *sql.Stmt
 	setPostIndexedTimestampStmt                     *sql.Stmt
 	setPostSentimentStmt                            *sql.Stmt
 	unassignLabelFromAuthorStmt                     *sql.Stmt
+	updateAuthorHandleStmt                          *sql.Stmt
 	updateAuthorOptOutStmt                          *sql.Stmt
+	updateClassifierProgressStmt                    *sql.Stmt
+	updateFeedGeneratorHealthStmt                   *sql.Stmt
 	updateImageStmt                                 *sql.Stmt
+	upsertAuthorProfileStmt                         *sql.Stmt
+	upsertFeedGeneratorStmt                         *sql.Stmt
+	upsertFeedStatsStmt                             *sql.Stmt
+	upsertListStmt                                  *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
-		db:                                 tx,
-		tx:                                 tx,
-		addAuthorStmt:                      q.addAuthorStmt,
-		addAuthorBlockStmt:                 q.addAuthorBlockStmt,
-		addAuthorToClusterStmt:             q.addAuthorToClusterStmt,
-		addClusterStmt:                     q.addClusterStmt,
-		addImageStmt:                       q.addImageStmt,
-		addLabelStmt:                       q.addLabelStmt,
-		addLabelsToPostsStmt:               q.addLabelsToPostsStmt,
-		addLikeToPostStmt:                  q.addLikeToPostStmt,
-		addPostStmt:                        q.addPostStmt,
-		addPostLabelStmt:                   q.addPostLabelStmt,
-		assignLabelToAuthorStmt:            q.assignLabelToAuthorStmt,
-		getAllLabelsStmt:                   q.getAllLabelsStmt,
-		getAllTimeBangersStmt:              q.getAllTimeBangersStmt,
-		getAllUniquePostLabelsStmt:         q.getAllUniquePostLabelsStmt,
-		getAuthorStmt:                      q.getAuthorStmt,
-		getAuthorBlockStmt:                 q.getAuthorBlockStmt,
-		getAuthorStatsStmt:                 q.getAuthorStatsStmt,
-		getAuthorsByHandleStmt:             q.getAuthorsByHandleStmt,
-		getBangersForAuthorStmt:            q.getBangersForAuthorStmt,
-		getBlockedByCountForTargetStmt:     q.getBlockedByCountForTargetStmt,
-		getBlocksForTargetStmt:             q.getBlocksForTargetStmt,
-		getClustersStmt:                    q.getClustersStmt,
-		getImageStmt:                       q.getImageStmt,
-		getImagesForAuthorDIDStmt:          q.getImagesForAuthorDIDStmt,
-		getImagesForPostStmt:               q.getImagesForPostStmt,
-		getLabelByAliasStmt:                q.getLabelByAliasStmt,
-		getLabelsStmt:                      q.getLabelsStmt,
-		getLabelsForAuthorStmt:             q.getLabelsForAuthorStmt,
-		getMembersOfAuthorLabelStmt:        q.getMembersOfAuthorLabelStmt,
-		getMembersOfClusterStmt:            q.getMembersOfClusterStmt,
-		getOldestPresentParentStmt:         q.getOldestPresentParentStmt,
-		getOptedOutAuthorsStmt:             q.getOptedOutAuthorsStmt,
-		getPostStmt:                        q.getPostStmt,
-		getPostPageStmt:                    q.getPostPageStmt,
-		getPostPageCursorStmt:              q.getPostPageCursorStmt,
-		getPostWithAuthorHandleStmt:        q.getPostWithAuthorHandleStmt,
-		getPostsPageByAuthorLabelAliasStmt: q.getPostsPageByAuthorLabelAliasStmt,
+		db:                                              tx,
+		tx:                                              tx,
+		addAuthorStmt:                                   q.addAuthorStmt,
+		addAuthorBlockStmt:                              q.addAuthorBlockStmt,
+		addAuthorToClusterStmt:                          q.addAuthorToClusterStmt,
+		addAuthorTombstoneStmt:                          q.addAuthorTombstoneStmt,
+		addAuthorsStmt:                                  q.addAuthorsStmt,
+		addClusterStmt:                                  q.addClusterStmt,
+		addFollowStmt:                                   q.addFollowStmt,
+		addImageStmt:                                    q.addImageStmt,
+		addImageDetectionsStmt:                          q.addImageDetectionsStmt,
+		addImageHashStmt:                                q.addImageHashStmt,
+		addImagesStmt:                                   q.addImagesStmt,
+		addLabelStmt:                                    q.addLabelStmt,
+		addLabelsToPostsStmt:                            q.addLabelsToPostsStmt,
+		addLikeToPostStmt:                               q.addLikeToPostStmt,
+		addLikesToPostsStmt:                             q.addLikesToPostsStmt,
+		addListItemStmt:                                 q.addListItemStmt,
+		addPostStmt:                                     q.addPostStmt,
+		addPostDeclaredLangsStmt:                        q.addPostDeclaredLangsStmt,
+		addPostLabelStmt:                                q.addPostLabelStmt,
+		addPostLinksStmt:                                q.addPostLinksStmt,
+		addPostMentionsStmt:                             q.addPostMentionsStmt,
+		addPostTagsStmt:                                 q.addPostTagsStmt,
+		addPostsStmt:                                    q.addPostsStmt,
+		addRepostStmt:                                   q.addRepostStmt,
+		assignLabelToAuthorStmt:                         q.assignLabelToAuthorStmt,
+		backfillPostsInsertedAtStmt:                     q.backfillPostsInsertedAtStmt,
+		deleteAuthorProfileStmt:                         q.deleteAuthorProfileStmt,
+		deleteFeedGeneratorStmt:                         q.deleteFeedGeneratorStmt,
+		deleteListStmt:                                  q.deleteListStmt,
+		deleteListItemStmt:                              q.deleteListItemStmt,
+		deleteListItemsForListStmt:                      q.deleteListItemsForListStmt,
+		deletePostLabelsInsertedSinceStmt:               q.deletePostLabelsInsertedSinceStmt,
+		getAllLabelsStmt:                                q.getAllLabelsStmt,
+		getAllTimeBangersStmt:                           q.getAllTimeBangersStmt,
+		getAllUniquePostLabelsStmt:                      q.getAllUniquePostLabelsStmt,
+		getAltTextCoverageStmt:                          q.getAltTextCoverageStmt,
+		getAltTextCoverageForAuthorStmt:                 q.getAltTextCoverageForAuthorStmt,
+		getAltTextStatsStmt:                             q.getAltTextStatsStmt,
+		getAuthorStmt:                                   q.getAuthorStmt,
+		getAuthorBlockStmt:                              q.getAuthorBlockStmt,
+		getAuthorProfileStmt:                            q.getAuthorProfileStmt,
+		getAuthorProfilePageStmt:                        q.getAuthorProfilePageStmt,
+		getAuthorStatsStmt:                              q.getAuthorStatsStmt,
+		getAuthorsByHandleStmt:                          q.getAuthorsByHandleStmt,
+		getBangersForAuthorStmt:                         q.getBangersForAuthorStmt,
+		getBlockedByCountForTargetStmt:                  q.getBlockedByCountForTargetStmt,
+		getBlocksForTargetStmt:                          q.getBlocksForTargetStmt,
+		getClassifierProgressStmt:                       q.getClassifierProgressStmt,
+		getClustersStmt:                                 q.getClustersStmt,
+		getClustersForAuthorsStmt:                       q.getClustersForAuthorsStmt,
+		getDetectionsForImageStmt:                       q.getDetectionsForImageStmt,
+		getFeedGeneratorStmt:                            q.getFeedGeneratorStmt,
+		getFeedGeneratorsToCheckStmt:                    q.getFeedGeneratorsToCheckStmt,
+		getFeedStatsStmt:                                q.getFeedStatsStmt,
+		getFollowerCountStmt:                            q.getFollowerCountStmt,
+		getFollowingCountStmt:                           q.getFollowingCountStmt,
+		getImageStmt:                                    q.getImageStmt,
+		getImageHashStmt:                                q.getImageHashStmt,
+		getImagesByDetectionLabelStmt:                   q.getImagesByDetectionLabelStmt,
+		getImagesForAuthorDIDStmt:                       q.getImagesForAuthorDIDStmt,
+		getImagesForPostStmt:                            q.getImagesForPostStmt,
+		getImagesForPostsStmt:                           q.getImagesForPostsStmt,
+		getLabelByAliasStmt:                             q.getLabelByAliasStmt,
+		getLabelsStmt:                                   q.getLabelsStmt,
+		getLabelsForAuthorStmt:                          q.getLabelsForAuthorStmt,
+		getLikeTotalsForPostsStmt:                       q.getLikeTotalsForPostsStmt,
+		getListStmt:                                     q.getListStmt,
+		getListMembersStmt:                              q.getListMembersStmt,
+		getListedSubjectsStmt:                           q.getListedSubjectsStmt,
+		getListsForAuthorStmt:                           q.getListsForAuthorStmt,
+		getMembersOfAuthorLabelStmt:                     q.getMembersOfAuthorLabelStmt,
+		getMembersOfClusterStmt:                         q.getMembersOfClusterStmt,
+		getMutualFollowsStmt:                            q.getMutualFollowsStmt,
+		getOldestPresentParentStmt:                      q.getOldestPresentParentStmt,
+		getOldestUnprocessedImageInsertedAtStmt:         q.getOldestUnprocessedImageInsertedAtStmt,
+		getOptedOutAuthorsStmt:                          q.getOptedOutAuthorsStmt,
+		getPostStmt:                                     q.getPostStmt,
+		getPostLanguagesStmt:                            q.getPostLanguagesStmt,
+		getPostPageStmt:                                 q.getPostPageStmt,
+		getPostPageCursorStmt:                           q.getPostPageCursorStmt,
+		getPostWithAuthorHandleStmt:                     q.getPostWithAuthorHandleStmt,
+		getPostsAfterCursorStmt:                         q.getPostsAfterCursorStmt,
+		getPostsLinkingToDomainStmt:                     q.getPostsLinkingToDomainStmt,
+		getPostsLinkingToURLStmt:                        q.getPostsLinkingToURLStmt,
+		getPostsMissingAltTextStmt:                      q.getPostsMissingAltTextStmt,
+		getPostsPageByAuthorLabelAliasStmt:              q.getPostsPageByAuthorLabelAliasStmt,
 		getPostsPageByAuthorLabelAliasFromViewStmt:      q.getPostsPageByAuthorLabelAliasFromViewStmt,
 		getPostsPageByClusterAliasStmt:                  q.getPostsPageByClusterAliasStmt,
 		getPostsPageByClusterAliasFromViewStmt:          q.getPostsPageByClusterAliasFromViewStmt,
 		getPostsPageWithAnyPostLabelStmt:                q.getPostsPageWithAnyPostLabelStmt,
+		getPostsPageWithAnyPostLabelChronologicalStmt:   q.getPostsPageWithAnyPostLabelChronologicalStmt,
 		getPostsPageWithAnyPostLabelSortedByHotnessStmt: q.getPostsPageWithAnyPostLabelSortedByHotnessStmt,
 		getPostsPageWithPostLabelStmt:                   q.getPostsPageWithPostLabelStmt,
 		getPostsPageWithPostLabelChronologicalStmt:      q.getPostsPageWithPostLabelChronologicalStmt,
 		getPostsPageWithPostLabelSortedByHotnessStmt:    q.getPostsPageWithPostLabelSortedByHotnessStmt,
+		getPostsWithSimilarImagesStmt:                   q.getPostsWithSimilarImagesStmt,
+		getRepostCountStmt:                              q.getRepostCountStmt,
+		getRepostersForPostStmt:                         q.getRepostersForPostStmt,
+		getSimilarImagesStmt:                            q.getSimilarImagesStmt,
+		getTagsForPostsStmt:                             q.getTagsForPostsStmt,
 		getThreadViewStmt:                               q.getThreadViewStmt,
+		getTombstonedAuthorsStmt:                        q.getTombstonedAuthorsStmt,
+		getTopDetectionLabelsStmt:                       q.getTopDetectionLabelsStmt,
+		getTopLinkDomainsStmt:                           q.getTopLinkDomainsStmt,
 		getTopPostersStmt:                               q.getTopPostersStmt,
+		getUnhashedImagesStmt:                           q.getUnhashedImagesStmt,
 		getUnindexedPostPageStmt:                        q.getUnindexedPostPageStmt,
 		getUnprocessedImagesStmt:                        q.getUnprocessedImagesStmt,
+		incrementFeedGeneratorLikeCountStmt:             q.incrementFeedGeneratorLikeCountStmt,
+		indexPostSearchVectorsStmt:                      q.indexPostSearchVectorsStmt,
 		removeAuthorBlockStmt:                           q.removeAuthorBlockStmt,
+		removeFollowStmt:                                q.removeFollowStmt,
 		removeLikeFromPostStmt:                          q.removeLikeFromPostStmt,
+		removeRepostStmt:                                q.removeRepostStmt,
+		searchAuthorsStmt:                               q.searchAuthorsStmt,
+		searchFeedGeneratorsStmt:                        q.searchFeedGeneratorsStmt,
+		searchPostsStmt:                                 q.searchPostsStmt,
+		setPostDetectedLangsStmt:                        q.setPostDetectedLangsStmt,
 		setPostIndexedTimestampStmt:                     q.setPostIndexedTimestampStmt,
 		setPostSentimentStmt:                            q.setPostSentimentStmt,
 		unassignLabelFromAuthorStmt:                     q.unassignLabelFromAuthorStmt,
+		updateAuthorHandleStmt:                          q.updateAuthorHandleStmt,
 		updateAuthorOptOutStmt:                          q.updateAuthorOptOutStmt,
+		updateClassifierProgressStmt:                    q.updateClassifierProgressStmt,
+		updateFeedGeneratorHealthStmt:                   q.updateFeedGeneratorHealthStmt,
 		updateImageStmt:                                 q.updateImageStmt,
+		upsertAuthorProfileStmt:                         q.upsertAuthorProfileStmt,
+		upsertFeedGeneratorStmt:                         q.upsertFeedGeneratorStmt,
+		upsertFeedStatsStmt:                             q.upsertFeedStatsStmt,
+		upsertListStmt:                                  q.upsertListStmt,
 	}
 }
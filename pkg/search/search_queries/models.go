@@ -32,12 +32,78 @@ type AuthorLabel struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type AuthorProfile struct {
+	Did         string         `json:"did"`
+	DisplayName sql.NullString `json:"display_name"`
+	Description sql.NullString `json:"description"`
+	AvatarCid   sql.NullString `json:"avatar_cid"`
+	BannerCid   sql.NullString `json:"banner_cid"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+type AuthorTombstone struct {
+	Did          string    `json:"did"`
+	TombstonedAt time.Time `json:"tombstoned_at"`
+}
+
+type ClassifierProgress struct {
+	Classifier       string         `json:"classifier"`
+	CursorInsertedAt time.Time      `json:"cursor_inserted_at"`
+	CursorPostID     string         `json:"cursor_post_id"`
+	ProcessedCount   int64          `json:"processed_count"`
+	LabeledCount     int64          `json:"labeled_count"`
+	FailedCount      int64          `json:"failed_count"`
+	LastError        sql.NullString `json:"last_error"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+}
+
 type Cluster struct {
 	ID          int32  `json:"id"`
 	LookupAlias string `json:"lookup_alias"`
 	Name        string `json:"name"`
 }
 
+type FeedGenerator struct {
+	Uri           string         `json:"uri"`
+	CreatorDid    string         `json:"creator_did"`
+	Rkey          string         `json:"rkey"`
+	DisplayName   string         `json:"display_name"`
+	Description   sql.NullString `json:"description"`
+	ServiceDid    string         `json:"service_did"`
+	LikeCount     int64          `json:"like_count"`
+	Healthy       sql.NullBool   `json:"healthy"`
+	HealthError   sql.NullString `json:"health_error"`
+	LastCheckedAt sql.NullTime   `json:"last_checked_at"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+type FeedGeneratorLike struct {
+	Uri       string `json:"uri"`
+	LikeCount int64  `json:"like_count"`
+}
+
+type FeedStatsDaily struct {
+	FeedName          string    `json:"feed_name"`
+	Date              time.Time `json:"date"`
+	Requests          int64     `json:"requests"`
+	Sessions          int64     `json:"sessions"`
+	PostsServed       int64     `json:"posts_served"`
+	UniquePostsServed int64     `json:"unique_posts_served"`
+	UniqueUsers       int64     `json:"unique_users"`
+	ReturningUsers    int64     `json:"returning_users"`
+	LikedPosts        int64     `json:"liked_posts"`
+	TotalLikes        int64     `json:"total_likes"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+type Follow struct {
+	ActorDid  string    `json:"actor_did"`
+	Rkey      string    `json:"rkey"`
+	TargetDid string    `json:"target_did"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type Image struct {
 	Cid          string                `json:"cid"`
 	PostID       string                `json:"post_id"`
@@ -52,12 +118,54 @@ type Image struct {
 	CvClasses    pqtype.NullRawMessage `json:"cv_classes"`
 }
 
+type ImageDetection struct {
+	ImageCid       string    `json:"image_cid"`
+	PostID         string    `json:"post_id"`
+	AuthorDid      string    `json:"author_did"`
+	DetectionIndex int32     `json:"detection_index"`
+	Label          string    `json:"label"`
+	Confidence     float64   `json:"confidence"`
+	BoxXMin        float64   `json:"box_x_min"`
+	BoxYMin        float64   `json:"box_y_min"`
+	BoxXMax        float64   `json:"box_x_max"`
+	BoxYMax        float64   `json:"box_y_max"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type ImageHash struct {
+	Cid        string        `json:"cid"`
+	Phash      sql.NullInt64 `json:"phash"`
+	Dhash      sql.NullInt64 `json:"dhash"`
+	PhashBand0 sql.NullInt32 `json:"phash_band_0"`
+	PhashBand1 sql.NullInt32 `json:"phash_band_1"`
+	PhashBand2 sql.NullInt32 `json:"phash_band_2"`
+	PhashBand3 sql.NullInt32 `json:"phash_band_3"`
+	HashedAt   time.Time     `json:"hashed_at"`
+}
+
 type Label struct {
 	ID          int64  `json:"id"`
 	LookupAlias string `json:"lookup_alias"`
 	Name        string `json:"name"`
 }
 
+type List struct {
+	Uri         string         `json:"uri"`
+	CreatorDid  string         `json:"creator_did"`
+	Name        string         `json:"name"`
+	Purpose     string         `json:"purpose"`
+	Description sql.NullString `json:"description"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+type ListItem struct {
+	Uri        string    `json:"uri"`
+	CreatorDid string    `json:"creator_did"`
+	ListUri    string    `json:"list_uri"`
+	SubjectDid string    `json:"subject_did"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
 type Post struct {
 	ID                  string          `json:"id"`
 	Text                string          `json:"text"`
@@ -70,6 +178,7 @@ type Post struct {
 	Sentiment           sql.NullString  `json:"sentiment"`
 	SentimentConfidence sql.NullFloat64 `json:"sentiment_confidence"`
 	IndexedAt           sql.NullTime    `json:"indexed_at"`
+	InsertedAt          sql.NullTime    `json:"inserted_at"`
 }
 
 type PostHotness struct {
@@ -95,8 +204,52 @@ type PostLabel struct {
 	Label     string `json:"label"`
 }
 
+type PostLanguage struct {
+	PostID             string          `json:"post_id"`
+	AuthorDid          string          `json:"author_did"`
+	DeclaredLangs      []string        `json:"declared_langs"`
+	DetectedLang       sql.NullString  `json:"detected_lang"`
+	DetectedConfidence sql.NullFloat64 `json:"detected_confidence"`
+	CreatedAt          time.Time       `json:"created_at"`
+}
+
 type PostLike struct {
 	PostID    string         `json:"post_id"`
 	AuthorDid sql.NullString `json:"author_did"`
 	LikeCount int64          `json:"like_count"`
 }
+
+type PostLink struct {
+	PostID    string    `json:"post_id"`
+	AuthorDid string    `json:"author_did"`
+	Url       string    `json:"url"`
+	Domain    string    `json:"domain"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PostMention struct {
+	PostID       string    `json:"post_id"`
+	AuthorDid    string    `json:"author_did"`
+	MentionedDid string    `json:"mentioned_did"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type PostRepost struct {
+	ActorDid  string         `json:"actor_did"`
+	Rkey      string         `json:"rkey"`
+	PostID    string         `json:"post_id"`
+	AuthorDid sql.NullString `json:"author_did"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type PostSearchVector struct {
+	PostID       string      `json:"post_id"`
+	SearchVector interface{} `json:"search_vector"`
+}
+
+type PostTag struct {
+	PostID    string    `json:"post_id"`
+	AuthorDid string    `json:"author_did"`
+	Tag       string    `json:"tag"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,170 @@
+package textsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search"
+	"github.com/meilisearch/meilisearch-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// MeiliSearcher indexes posts in a Meilisearch index and searches them there
+type MeiliSearcher struct {
+	Client       *meilisearch.Client
+	Index        string
+	PostRegistry *search.PostRegistry
+}
+
+// meiliPost is the document indexed for each post, created_at is a unix timestamp so it can be filtered on
+type meiliPost struct {
+	ID           string   `json:"id"`
+	Text         string   `json:"text"`
+	ParentPostID *string  `json:"parent_post_id"`
+	RootPostID   *string  `json:"root_post_id"`
+	AuthorDID    string   `json:"author_did"`
+	CreatedAt    int64    `json:"created_at"`
+	Labels       []string `json:"labels"`
+	ClusterID    *int32   `json:"cluster_id"`
+}
+
+func NewMeiliSearcher(client *meilisearch.Client, index string, postRegistry *search.PostRegistry) *MeiliSearcher {
+	return &MeiliSearcher{
+		Client:       client,
+		Index:        index,
+		PostRegistry: postRegistry,
+	}
+}
+
+// SetupIndex makes the fields searches filter on filterable
+func (ms *MeiliSearcher) SetupIndex(ctx context.Context) error {
+	tracer := otel.Tracer("textsearch")
+	_, span := tracer.Start(ctx, "MeiliSearcher:SetupIndex")
+	defer span.End()
+
+	_, err := ms.Client.Index(ms.Index).UpdateFilterableAttributes(&[]string{"author_did", "labels", "cluster_id", "created_at"})
+	if err != nil {
+		return fmt.Errorf("error setting filterable attributes on index %s: %w", ms.Index, err)
+	}
+	return nil
+}
+
+func (ms *MeiliSearcher) IndexPosts(ctx context.Context, posts []*search.Post) error {
+	tracer := otel.Tracer("textsearch")
+	ctx, span := tracer.Start(ctx, "MeiliSearcher:IndexPosts")
+	defer span.End()
+
+	authorDIDs := make([]string, len(posts))
+	for i, post := range posts {
+		authorDIDs[i] = post.AuthorDID
+	}
+
+	clusters, err := ms.PostRegistry.GetClustersForAuthors(ctx, authorDIDs)
+	if err != nil {
+		return err
+	}
+
+	docs := make([]meiliPost, len(posts))
+	for i, post := range posts {
+		docs[i] = meiliPost{
+			ID:           post.ID,
+			Text:         post.Text,
+			ParentPostID: post.ParentPostID,
+			RootPostID:   post.RootPostID,
+			AuthorDID:    post.AuthorDID,
+			CreatedAt:    post.CreatedAt.Unix(),
+			Labels:       post.Labels,
+		}
+		if clusterID, ok := clusters[post.AuthorDID]; ok {
+			docs[i].ClusterID = &clusterID
+		}
+	}
+
+	task, err := ms.Client.Index(ms.Index).UpdateDocuments(&docs, "id")
+	if err != nil {
+		return fmt.Errorf("error indexing posts: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int64("task.uid", task.TaskUID))
+
+	return nil
+}
+
+func (ms *MeiliSearcher) SearchPosts(ctx context.Context, query search.PostSearchQuery) ([]*search.Post, error) {
+	tracer := otel.Tracer("textsearch")
+	_, span := tracer.Start(ctx, "MeiliSearcher:SearchPosts")
+	defer span.End()
+
+	request := &meilisearch.SearchRequest{
+		Limit:  int64(query.Limit),
+		Offset: int64(query.Offset),
+	}
+	if filter := meiliFilter(query); filter != "" {
+		request.Filter = filter
+		span.SetAttributes(attribute.String("filter", filter))
+	}
+
+	resp, err := ms.Client.Index(ms.Index).Search(query.Query, request)
+	if err != nil {
+		return nil, fmt.Errorf("error searching posts: %w", err)
+	}
+
+	// Hits come back as generic maps, round trip them through JSON to get our documents back
+	hits, err := json.Marshal(resp.Hits)
+	if err != nil {
+		return nil, fmt.Errorf("error reading search hits: %w", err)
+	}
+
+	docs := []meiliPost{}
+	err = json.Unmarshal(hits, &docs)
+	if err != nil {
+		return nil, fmt.Errorf("error reading search hits: %w", err)
+	}
+
+	posts := make([]*search.Post, len(docs))
+	for i, doc := range docs {
+		posts[i] = &search.Post{
+			ID:           doc.ID,
+			Text:         doc.Text,
+			ParentPostID: doc.ParentPostID,
+			RootPostID:   doc.RootPostID,
+			AuthorDID:    doc.AuthorDID,
+			CreatedAt:    time.Unix(doc.CreatedAt, 0).UTC(),
+			Labels:       doc.Labels,
+		}
+	}
+
+	return posts, nil
+}
+
+// meiliFilter builds the Meilisearch filter expression for the query's filters
+func meiliFilter(query search.PostSearchQuery) string {
+	filters := []string{}
+
+	if query.AuthorDID != "" {
+		filters = append(filters, "author_did = "+strconv.Quote(query.AuthorDID))
+	}
+	if query.Label != "" {
+		filters = append(filters, "labels = "+strconv.Quote(query.Label))
+	}
+	if query.ClusterID != nil {
+		filters = append(filters, fmt.Sprintf("cluster_id = %d", *query.ClusterID))
+	}
+	if query.Since != nil {
+		filters = append(filters, fmt.Sprintf("created_at >= %d", query.Since.Unix()))
+	}
+	if query.Until != nil {
+		filters = append(filters, fmt.Sprintf("created_at < %d", query.Until.Unix()))
+	}
+	// Documents don't carry their languages, so match on the language classifier's labels
+	if query.Lang != "" {
+		filters = append(filters, "labels = "+strconv.Quote(search.LanguageLabel(query.Lang)))
+	}
+
+	return strings.Join(filters, " AND ")
+}
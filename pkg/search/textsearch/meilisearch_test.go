@@ -0,0 +1,37 @@
+package textsearch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeiliFilter(t *testing.T) {
+	clusterID := int32(7)
+	since := time.Unix(1690000000, 0)
+	until := time.Unix(1690086400, 0)
+
+	tests := []struct {
+		name   string
+		query  search.PostSearchQuery
+		filter string
+	}{
+		{"no filters", search.PostSearchQuery{Query: "hello"}, ""},
+		{"author", search.PostSearchQuery{AuthorDID: "did:plc:abc"}, `author_did = "did:plc:abc"`},
+		{"label is quoted", search.PostSearchQuery{Label: `a "b"`}, `labels = "a \"b\""`},
+		{"lang is a label", search.PostSearchQuery{Lang: "ja"}, `labels = "lang:ja"`},
+		{
+			"everything",
+			search.PostSearchQuery{AuthorDID: "did:plc:abc", Label: "cv:dog", ClusterID: &clusterID, Since: &since, Until: &until},
+			`author_did = "did:plc:abc" AND labels = "cv:dog" AND cluster_id = 7 AND created_at >= 1690000000 AND created_at < 1690086400`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.filter, meiliFilter(tt.query))
+		})
+	}
+}
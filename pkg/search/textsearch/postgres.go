@@ -0,0 +1,40 @@
+package textsearch
+
+import (
+	"context"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search"
+	"go.opentelemetry.io/otel"
+)
+
+// PostgresSearcher searches posts in the PostRegistry with tsvector full-text search and trigram similarity
+type PostgresSearcher struct {
+	PostRegistry *search.PostRegistry
+}
+
+func NewPostgresSearcher(postRegistry *search.PostRegistry) *PostgresSearcher {
+	return &PostgresSearcher{
+		PostRegistry: postRegistry,
+	}
+}
+
+func (ps *PostgresSearcher) IndexPosts(ctx context.Context, posts []*search.Post) error {
+	tracer := otel.Tracer("textsearch")
+	ctx, span := tracer.Start(ctx, "PostgresSearcher:IndexPosts")
+	defer span.End()
+
+	postIDs := make([]string, len(posts))
+	for i, post := range posts {
+		postIDs[i] = post.ID
+	}
+
+	return ps.PostRegistry.IndexPostSearchVectors(ctx, postIDs)
+}
+
+func (ps *PostgresSearcher) SearchPosts(ctx context.Context, query search.PostSearchQuery) ([]*search.Post, error) {
+	tracer := otel.Tracer("textsearch")
+	ctx, span := tracer.Start(ctx, "PostgresSearcher:SearchPosts")
+	defer span.End()
+
+	return ps.PostRegistry.SearchPosts(ctx, query)
+}
@@ -0,0 +1,58 @@
+package textsearch
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search"
+	"github.com/meilisearch/meilisearch-go"
+)
+
+// Backends that can be passed to New
+const (
+	PostgresBackend    = "postgres"
+	MeilisearchBackend = "meilisearch"
+)
+
+// Searcher indexes posts and runs full-text searches over them
+type Searcher interface {
+	// IndexPosts makes a batch of posts searchable, it's called with each page of unindexed posts
+	// before they're marked as indexed so a failed batch is retried
+	IndexPosts(ctx context.Context, posts []*search.Post) error
+	// SearchPosts returns a page of the posts matching the query, best matches first
+	SearchPosts(ctx context.Context, query search.PostSearchQuery) ([]*search.Post, error)
+}
+
+// IndexSetup is implemented by Searchers whose index needs settings applied before posts are indexed into it
+type IndexSetup interface {
+	SetupIndex(ctx context.Context) error
+}
+
+// SetupIndex applies the searcher's index settings if it has any
+// Only indexers should call it so read-only users of the index, like the search API, don't change its settings
+func SetupIndex(ctx context.Context, searcher Searcher) error {
+	setup, ok := searcher.(IndexSetup)
+	if !ok {
+		return nil
+	}
+	return setup.SetupIndex(ctx)
+}
+
+// New returns the Searcher for a backend, meiliAddress and meiliAPIKey are only used by the Meilisearch backend
+func New(backend string, postRegistry *search.PostRegistry, meiliAddress string, meiliAPIKey string) (Searcher, error) {
+	switch backend {
+	case PostgresBackend, "":
+		return NewPostgresSearcher(postRegistry), nil
+	case MeilisearchBackend:
+		if meiliAddress == "" {
+			return nil, fmt.Errorf("a Meilisearch address is required for the %s backend", MeilisearchBackend)
+		}
+		client := meilisearch.NewClient(meilisearch.ClientConfig{
+			Host:   meiliAddress,
+			APIKey: meiliAPIKey,
+		})
+		return NewMeiliSearcher(client, "posts", postRegistry), nil
+	default:
+		return nil, fmt.Errorf("unknown search backend %q", backend)
+	}
+}
@@ -0,0 +1,22 @@
+package sentiment
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var sentimentPostsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "sentiment_posts_total",
+	Help: "The total number of posts run through sentiment analysis by detected language and result",
+}, []string{"lang", "result"})
+
+var sentimentRequestsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "sentiment_requests_total",
+	Help: "The total number of requests to sentiment services by language, route, and status",
+}, []string{"lang", "route", "status"})
+
+var sentimentRequestDurationHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "sentiment_request_duration_seconds",
+	Help:    "The time it takes a sentiment service to analyze a batch of posts",
+	Buckets: prometheus.ExponentialBuckets(0.01, 2, 15),
+}, []string{"lang", "route"})
@@ -1,19 +1,20 @@
 package sentiment
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"net/http"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/ericvolp12/bsky-experiments/pkg/language"
 	"github.com/ericvolp12/bsky-experiments/pkg/search"
-	"github.com/pemistahl/lingua-go"
-	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"github.com/ericvolp12/bsky-experiments/pkg/sidecar"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -22,10 +23,26 @@ const (
 	NEUTRAL  = "neutral"
 )
 
+// FallbackRoute is the route for posts in languages without a sentiment service of their own
+// It's meant for a multilingual model and is also tried when a language's own service fails
+const FallbackRoute = "*"
+
+// unknownLang is the metric label for posts whose language couldn't be detected confidently
+const unknownLang = "unknown"
+
 type Sentiment struct {
-	SentimentServiceHost string
-	LanguageDetector     lingua.LanguageDetector
-	Client               *http.Client
+	// Routes maps lowercase ISO 639-1 language codes (or FallbackRoute) to sentiment service hosts
+	Routes map[string]string
+	// MinLanguageConfidence is the detection confidence needed to route a post by its language
+	MinLanguageConfidence float64
+	LanguageDetector      *language.Detector
+
+	// clients holds a sidecar client for each sentiment service host
+	clients map[string]*sidecar.Client
+
+	pausedLk sync.Mutex
+	// pausedHosts are the hosts that were down when the last batch failed, the batch is retried once any of them is back
+	pausedHosts []string
 }
 
 type sentimentRequest struct {
@@ -53,101 +70,268 @@ type sentimentResponse struct {
 	Posts []sentimentPost `json:"posts"`
 }
 
-func NewSentiment(sentimentServiceHost string) *Sentiment {
+func NewSentiment(routes map[string]string) *Sentiment {
+	clients := map[string]*sidecar.Client{}
+	for lang, host := range routes {
+		if _, ok := clients[host]; ok {
+			continue
+		}
+		service := "sentiment-" + lang
+		if lang == FallbackRoute {
+			service = "sentiment-fallback"
+		}
+		clients[host] = sidecar.NewClient(service, host, sidecar.Options{
+			Timeout:      time.Minute,
+			MaxBatchSize: 500,
+		})
+	}
 
-	// Build an English langauge detector since our sentiment service only supports English
-	languages := []lingua.Language{
-		lingua.English,
-		lingua.Spanish,
-		lingua.Portuguese,
-		lingua.Korean,
-		lingua.Japanese,
-		lingua.Persian,
+	return &Sentiment{
+		Routes:                routes,
+		MinLanguageConfidence: 0.5,
+		LanguageDetector:      language.NewDetector(),
+		clients:               clients,
 	}
+}
 
-	client := http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
+// WaitUntilAvailable blocks while every service the last failed batch was routed to is down
+func (s *Sentiment) WaitUntilAvailable(ctx context.Context) error {
+	for {
+		s.pausedLk.Lock()
+		hosts := s.pausedHosts
+		s.pausedLk.Unlock()
 
-	detector := lingua.NewLanguageDetectorBuilder().
-		FromLanguages(languages...).
-		Build()
+		if len(hosts) == 0 {
+			return nil
+		}
 
-	return &Sentiment{
-		SentimentServiceHost: sentimentServiceHost,
-		LanguageDetector:     detector,
-		Client:               &client,
+		for _, host := range hosts {
+			if client, ok := s.clients[host]; !ok || client.Available() {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
 	}
 }
 
+// ParseRoutes parses a comma separated list of language to sentiment service routes
+// i.e. "en=http://sentiment-en:8088,*=http://sentiment-multilingual:8088"
+func ParseRoutes(raw string) (map[string]string, error) {
+	routes := map[string]string{}
+
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		lang, host, ok := strings.Cut(part, "=")
+		lang, host = strings.TrimSpace(lang), strings.TrimSpace(host)
+		if !ok || lang == "" || host == "" {
+			return nil, fmt.Errorf("invalid sentiment route %q, expected lang=host", part)
+		}
+
+		if lang != FallbackRoute {
+			lang = language.Normalize(lang)
+		}
+		if _, exists := routes[lang]; exists {
+			return nil, fmt.Errorf("duplicate sentiment route for %q", lang)
+		}
+
+		routes[lang] = strings.TrimSuffix(host, "/")
+	}
+
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("no sentiment routes configured")
+	}
+
+	return routes, nil
+}
+
+// hosts returns the sentiment service hosts to try for a language in order
+func (s *Sentiment) hosts(lang string) []string {
+	hosts := []string{}
+	if host, ok := s.Routes[lang]; ok {
+		hosts = append(hosts, host)
+	}
+	if fallback, ok := s.Routes[FallbackRoute]; ok && (len(hosts) == 0 || hosts[0] != fallback) {
+		hosts = append(hosts, fallback)
+	}
+	return hosts
+}
+
+// GetPostsSentiment sets the sentiment of the posts by routing them to a sentiment service by detected language
+// Posts in languages without a route are left without a sentiment
+// Posts in languages whose services fail are skipped as long as another language succeeded, so one service
+// being down doesn't hold up the rest. If every routed language fails the joined errors are returned
 func (s *Sentiment) GetPostsSentiment(ctx context.Context, posts []*search.Post) ([]*search.Post, error) {
 	tracer := otel.Tracer("bsky-search")
 	ctx, span := tracer.Start(ctx, "Sentiment:GetPostsSentiment")
 	defer span.End()
 
-	englishPosts := make([]*search.Post, 0, len(posts))
-
-	// Return early if all of the posts are not in English
-	for i, post := range posts {
-		confidence := s.LanguageDetector.ComputeLanguageConfidence(post.Text, lingua.English)
-		if confidence > 0.5 {
-			englishPosts = append(englishPosts, posts[i])
+	postsByLang := map[string][]*search.Post{}
+	for _, post := range posts {
+		lang := unknownLang
+		detection, ok := s.LanguageDetector.Detect(post.Text)
+		if ok && detection.Confidence >= s.MinLanguageConfidence {
+			lang = detection.Lang
 		}
+		postsByLang[lang] = append(postsByLang[lang], post)
 	}
 
-	if len(englishPosts) == 0 {
-		return posts, nil
+	langs := make([]string, 0, len(postsByLang))
+	for lang := range postsByLang {
+		langs = append(langs, lang)
 	}
+	sort.Strings(langs)
 
-	url := fmt.Sprintf("%s/analyze_sentiment", s.SentimentServiceHost)
+	routed := 0
+	succeeded := 0
+	failedLangs := []string{}
+	unavailableHosts := []string{}
+	errs := []error{}
 
-	reqBody := sentimentRequest{Posts: englishPosts}
-	jsonReqBody, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal sentiment request body: %w", err)
-	}
+	for _, lang := range langs {
+		langPosts := postsByLang[lang]
 
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonReqBody))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create sentiment request: %w", err)
+		hosts := s.hosts(lang)
+		if len(hosts) == 0 {
+			sentimentPostsCounter.WithLabelValues(lang, "unrouted").Add(float64(len(langPosts)))
+			continue
+		}
+		routed++
+
+		err := s.analyzeWithFallback(ctx, lang, hosts, langPosts)
+		if err != nil {
+			failedLangs = append(failedLangs, lang)
+			errs = append(errs, fmt.Errorf("failed to get sentiment for %q posts: %w", lang, err))
+			if errors.Is(err, sidecar.ErrUnavailable) {
+				unavailableHosts = append(unavailableHosts, hosts...)
+			}
+			continue
+		}
+		succeeded++
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := s.Client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send sentiment request: %w", err)
+	span.SetAttributes(
+		attribute.Int("sentiment.langs", len(langs)),
+		attribute.Int("sentiment.langs.routed", routed),
+		attribute.Int("sentiment.langs.failed", len(errs)),
+	)
+
+	if len(errs) > 0 && succeeded == 0 {
+		// Nothing got through, if a service is down the batch waits for it to come back
+		s.setPausedHosts(unavailableHosts)
+		for _, lang := range failedLangs {
+			sentimentPostsCounter.WithLabelValues(lang, "failed").Add(float64(len(postsByLang[lang])))
+		}
+		err := errors.Join(errs...)
+		span.RecordError(err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var respBody sentimentResponse
-	if err := json.NewDecoder(resp.Body).Decode(&respBody); err != nil {
-		return nil, fmt.Errorf("failed to decode sentiment response body: %w", err)
+	s.setPausedHosts(nil)
+	for i, lang := range failedLangs {
+		sentimentPostsCounter.WithLabelValues(lang, "skipped").Add(float64(len(postsByLang[lang])))
+		log.Printf("skipping sentiment for %d %q posts: %+v\n", len(postsByLang[lang]), lang, errs[i])
 	}
 
-	for i, p := range respBody.Posts {
-		if p.Decision.Sentiment == POSITIVE {
-			sentiment := strings.Clone(search.PositiveSentiment)
-			englishPosts[i].Sentiment = &sentiment
-		} else if p.Decision.Sentiment == NEGATIVE {
-			sentiment := strings.Clone(search.NegativeSentiment)
-			englishPosts[i].Sentiment = &sentiment
-		} else if p.Decision.Sentiment == NEUTRAL {
-			sentiment := strings.Clone(search.NeutralSentiment)
-			englishPosts[i].Sentiment = &sentiment
-		} else {
-			log.Printf("unknown sentiment: %s\n", p.Decision.Sentiment)
+	return posts, nil
+}
+
+func (s *Sentiment) setPausedHosts(hosts []string) {
+	s.pausedLk.Lock()
+	defer s.pausedLk.Unlock()
+	s.pausedHosts = hosts
+}
+
+// analyzeWithFallback tries each host in turn until one of them analyzes the posts
+func (s *Sentiment) analyzeWithFallback(ctx context.Context, lang string, hosts []string, posts []*search.Post) error {
+	errs := []error{}
+
+	for i, host := range hosts {
+		route := "primary"
+		if host == s.Routes[FallbackRoute] {
+			route = "fallback"
+		}
+
+		start := time.Now()
+		err := s.analyze(ctx, host, lang, posts)
+		sentimentRequestDurationHistogram.WithLabelValues(lang, route).Observe(time.Since(start).Seconds())
+		if err == nil {
+			sentimentRequestsCounter.WithLabelValues(lang, route, "ok").Inc()
+			return nil
+		}
+
+		status := "error"
+		if errors.Is(err, sidecar.ErrUnavailable) {
+			status = "unavailable"
+		}
+		sentimentRequestsCounter.WithLabelValues(lang, route, status).Inc()
+		errs = append(errs, fmt.Errorf("%s: %w", host, err))
+		if i < len(hosts)-1 {
+			log.Printf("sentiment service %s failed for %q posts, trying fallback: %+v\n", host, lang, err)
 		}
-		var confidence float64
-		confidence = p.Decision.Confidence
-		englishPosts[i].SentimentConfidence = &confidence
 	}
 
-	// Merge the english posts back into the original posts slice
-	for i, post := range posts {
-		for _, englishPost := range englishPosts {
-			if post.ID == englishPost.ID {
-				posts[i] = englishPost
-			}
+	return errors.Join(errs...)
+}
+
+// analyze sends the posts to a sentiment service in batches and sets the sentiment on the posts it returns
+func (s *Sentiment) analyze(ctx context.Context, host string, lang string, posts []*search.Post) error {
+	client, ok := s.clients[host]
+	if !ok {
+		return fmt.Errorf("no sentiment client for %s", host)
+	}
+
+	postsByID := make(map[string]*search.Post, len(posts))
+	for _, post := range posts {
+		postsByID[post.ID] = post
+	}
+
+	for _, batch := range sidecar.Batches(posts, client.Options.MaxBatchSize) {
+		var respBody sentimentResponse
+		err := client.PostJSON(ctx, "/analyze_sentiment", sentimentRequest{Posts: batch}, &respBody)
+		if err != nil {
+			return err
 		}
+
+		s.applyDecisions(lang, postsByID, respBody.Posts)
 	}
 
-	return posts, nil
+	return nil
+}
+
+// applyDecisions sets the sentiment decided by a sentiment service on the posts
+func (s *Sentiment) applyDecisions(lang string, postsByID map[string]*search.Post, decisions []sentimentPost) {
+	for _, p := range decisions {
+		post, ok := postsByID[p.ID]
+		if !ok {
+			continue
+		}
+
+		var sentiment string
+		switch p.Decision.Sentiment {
+		case POSITIVE:
+			sentiment = search.PositiveSentiment
+		case NEGATIVE:
+			sentiment = search.NegativeSentiment
+		case NEUTRAL:
+			sentiment = search.NeutralSentiment
+		default:
+			log.Printf("unknown sentiment: %s\n", p.Decision.Sentiment)
+			sentimentPostsCounter.WithLabelValues(lang, "unlabeled").Inc()
+			continue
+		}
+
+		confidence := p.Decision.Confidence
+		post.Sentiment = &sentiment
+		post.SentimentConfidence = &confidence
+		sentimentPostsCounter.WithLabelValues(lang, "labeled").Inc()
+	}
 }
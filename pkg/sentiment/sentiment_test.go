@@ -0,0 +1,82 @@
+package sentiment
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ericvolp12/bsky-experiments/pkg/search"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRoutes(t *testing.T) {
+	routes, err := ParseRoutes("en=http://sentiment-en:8088/, pt-BR=http://sentiment-pt:8088,*=http://sentiment-xlm:8088")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"en": "http://sentiment-en:8088",
+		"pt": "http://sentiment-pt:8088",
+		"*":  "http://sentiment-xlm:8088",
+	}, routes)
+
+	for _, raw := range []string{"", "en", "en=", "=http://a", "en=http://a,EN=http://b"} {
+		_, err := ParseRoutes(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestHosts(t *testing.T) {
+	s := &Sentiment{Routes: map[string]string{
+		"en": "http://en",
+		"ja": "http://xlm",
+		"*":  "http://xlm",
+	}}
+
+	assert.Equal(t, []string{"http://en", "http://xlm"}, s.hosts("en"))
+	assert.Equal(t, []string{"http://xlm"}, s.hosts("ja"))
+	assert.Equal(t, []string{"http://xlm"}, s.hosts(unknownLang))
+
+	s = &Sentiment{Routes: map[string]string{"en": "http://en"}}
+	assert.Equal(t, []string{"http://en"}, s.hosts("en"))
+	assert.Empty(t, s.hosts("pt"))
+}
+
+func TestGetPostsSentimentPartialFailure(t *testing.T) {
+	english := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := sentimentRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		resp := sentimentResponse{}
+		for _, post := range req.Posts {
+			resp.Posts = append(resp.Posts, sentimentPost{ID: post.ID, Decision: sentimentDecision{Sentiment: POSITIVE, Confidence: 0.9}})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer english.Close()
+
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer fallback.Close()
+
+	s := NewSentiment(map[string]string{"en": english.URL, FallbackRoute: fallback.URL})
+
+	posts := []*search.Post{
+		{ID: "a", Text: "I had a wonderful time at the beach with my family this weekend"},
+		{ID: "b", Text: "🙂"},
+	}
+
+	// The English posts get their sentiment and the posts the fallback failed on are skipped
+	results, err := s.GetPostsSentiment(context.Background(), posts)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.NotNil(t, posts[0].Sentiment)
+	assert.Nil(t, posts[1].Sentiment)
+
+	// When every routed language fails the batch fails
+	_, err = s.GetPostsSentiment(context.Background(), posts[1:])
+	assert.Error(t, err)
+}
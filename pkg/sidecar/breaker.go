@@ -0,0 +1,164 @@
+package sidecar
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BreakerState is the state of a circuit breaker
+type BreakerState int
+
+const (
+	// BreakerClosed lets every request through
+	BreakerClosed BreakerState = iota
+	// BreakerHalfOpen lets a single trial request through after the cooldown
+	BreakerHalfOpen
+	// BreakerOpen fails requests fast until the cooldown has passed
+	BreakerOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerHalfOpen:
+		return "half-open"
+	case BreakerOpen:
+		return "open"
+	}
+	return "unknown"
+}
+
+// Breaker is a circuit breaker that opens after a run of consecutive failures
+// Once the cooldown passes a single trial request is let through, if it succeeds the breaker closes again
+type Breaker struct {
+	FailureThreshold int
+	Cooldown         time.Duration
+
+	lk       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	trialing bool
+	onChange func(BreakerState)
+
+	now func() time.Time
+}
+
+func NewBreaker(failureThreshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		FailureThreshold: failureThreshold,
+		Cooldown:         cooldown,
+		now:              time.Now,
+	}
+}
+
+// State returns the current state of the breaker
+func (b *Breaker) State() BreakerState {
+	b.lk.Lock()
+	defer b.lk.Unlock()
+	b.refresh()
+	return b.state
+}
+
+// Available returns true if a request would be let through right now
+func (b *Breaker) Available() bool {
+	b.lk.Lock()
+	defer b.lk.Unlock()
+	b.refresh()
+	return b.state == BreakerClosed || (b.state == BreakerHalfOpen && !b.trialing)
+}
+
+// Allow reports whether a request may be made, callers must report the outcome with Success or Failure
+func (b *Breaker) Allow() bool {
+	b.lk.Lock()
+	defer b.lk.Unlock()
+	b.refresh()
+
+	switch b.state {
+	case BreakerClosed:
+		return true
+	case BreakerHalfOpen:
+		if b.trialing {
+			return false
+		}
+		b.trialing = true
+		return true
+	}
+	return false
+}
+
+// Success records a successful request and closes the breaker
+func (b *Breaker) Success() {
+	b.lk.Lock()
+	defer b.lk.Unlock()
+	b.failures = 0
+	b.trialing = false
+	b.setState(BreakerClosed)
+}
+
+// Failure records a failed request, opening the breaker if the threshold is reached or the trial request failed
+func (b *Breaker) Failure() {
+	b.lk.Lock()
+	defer b.lk.Unlock()
+	b.refresh()
+
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.FailureThreshold {
+		b.trialing = false
+		b.openedAt = b.now()
+		b.setState(BreakerOpen)
+	}
+}
+
+// Release gives up a request let through by Allow without recording an outcome, i.e. when the caller was cancelled
+func (b *Breaker) Release() {
+	b.lk.Lock()
+	defer b.lk.Unlock()
+	b.trialing = false
+}
+
+// Wait blocks until the breaker would let a request through or the context is cancelled
+func (b *Breaker) Wait(ctx context.Context) error {
+	for {
+		b.lk.Lock()
+		b.refresh()
+		wait := time.Duration(0)
+		switch {
+		case b.state == BreakerOpen:
+			wait = b.Cooldown - b.now().Sub(b.openedAt)
+		case b.state == BreakerHalfOpen && b.trialing:
+			wait = time.Second
+		}
+		b.lk.Unlock()
+
+		if wait <= 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// refresh moves an open breaker to half-open once the cooldown has passed, the lock must be held
+func (b *Breaker) refresh() {
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.Cooldown {
+		b.setState(BreakerHalfOpen)
+	}
+}
+
+// setState changes the state of the breaker, the lock must be held
+func (b *Breaker) setState(state BreakerState) {
+	if b.state == state {
+		return
+	}
+	b.state = state
+	if b.onChange != nil {
+		b.onChange(state)
+	}
+}
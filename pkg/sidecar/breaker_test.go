@@ -0,0 +1,60 @@
+package sidecar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Unix(1690000000, 0)
+	b := NewBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	assert.True(t, b.Allow())
+	b.Failure()
+	assert.Equal(t, BreakerClosed, b.State())
+
+	// A success resets the run of failures
+	b.Success()
+	b.Failure()
+	assert.Equal(t, BreakerClosed, b.State())
+
+	b.Failure()
+	assert.Equal(t, BreakerOpen, b.State())
+	assert.False(t, b.Allow())
+	assert.False(t, b.Available())
+
+	// After the cooldown only one trial request is let through
+	now = now.Add(time.Minute)
+	assert.Equal(t, BreakerHalfOpen, b.State())
+	assert.True(t, b.Allow())
+	assert.False(t, b.Allow())
+
+	// A failed trial opens the breaker again
+	b.Failure()
+	assert.Equal(t, BreakerOpen, b.State())
+
+	now = now.Add(time.Minute)
+	assert.True(t, b.Allow())
+	b.Success()
+	assert.Equal(t, BreakerClosed, b.State())
+	assert.True(t, b.Available())
+}
+
+func TestBreakerRelease(t *testing.T) {
+	now := time.Unix(1690000000, 0)
+	b := NewBreaker(1, time.Minute)
+	b.now = func() time.Time { return now }
+
+	b.Failure()
+	now = now.Add(time.Minute)
+	assert.True(t, b.Allow())
+	assert.False(t, b.Available())
+
+	// A cancelled trial lets the next request try again
+	b.Release()
+	assert.True(t, b.Available())
+	assert.Equal(t, BreakerHalfOpen, b.State())
+}
@@ -0,0 +1,252 @@
+package sidecar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ErrUnavailable is returned without making a request while a service's circuit breaker is open
+var ErrUnavailable = errors.New("sidecar service unavailable")
+
+// StatusError is returned when a service responds with a status other than 200 OK
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d", e.StatusCode)
+}
+
+// Options configures a Client, zero values are replaced with defaults
+type Options struct {
+	// Timeout bounds each attempt at a request
+	Timeout time.Duration
+	// MaxBatchSize is the most items callers should send in one request, see Batches
+	MaxBatchSize int
+	// MaxAttempts is how many times a request is tried before giving up
+	MaxAttempts int
+	// BaseBackoff is the delay before the first retry, it doubles with every attempt up to MaxBackoff
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// FailureThreshold is how many failed attempts in a row open the circuit breaker
+	FailureThreshold int
+	// Cooldown is how long the circuit breaker stays open before letting a trial request through
+	Cooldown time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.Timeout <= 0 {
+		o.Timeout = 30 * time.Second
+	}
+	if o.MaxBatchSize <= 0 {
+		o.MaxBatchSize = 100
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = 3
+	}
+	if o.BaseBackoff <= 0 {
+		o.BaseBackoff = 500 * time.Millisecond
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = 10 * time.Second
+	}
+	if o.FailureThreshold <= 0 {
+		o.FailureThreshold = 5
+	}
+	if o.Cooldown <= 0 {
+		o.Cooldown = 30 * time.Second
+	}
+	return o
+}
+
+// Client makes JSON requests to an ML sidecar service
+// Requests time out, failed requests are retried with backoff, and a circuit breaker
+// fails requests fast while the service is down so callers can pause instead of piling on
+type Client struct {
+	Service    string
+	Host       string
+	Options    Options
+	HTTPClient *http.Client
+	Breaker    *Breaker
+}
+
+// NewClient creates a client for the service at host, service names the service in metrics
+func NewClient(service string, host string, options Options) *Client {
+	options = options.withDefaults()
+
+	breaker := NewBreaker(options.FailureThreshold, options.Cooldown)
+	breaker.onChange = func(state BreakerState) {
+		sidecarBreakerStateGauge.WithLabelValues(service).Set(float64(state))
+	}
+	sidecarBreakerStateGauge.WithLabelValues(service).Set(float64(BreakerClosed))
+
+	return &Client{
+		Service:    service,
+		Host:       strings.TrimSuffix(host, "/"),
+		Options:    options,
+		HTTPClient: &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)},
+		Breaker:    breaker,
+	}
+}
+
+// Available returns true if the service's circuit breaker would let a request through
+func (c *Client) Available() bool {
+	return c.Breaker.Available()
+}
+
+// WaitUntilAvailable blocks until the service's circuit breaker lets requests through again
+func (c *Client) WaitUntilAvailable(ctx context.Context) error {
+	return c.Breaker.Wait(ctx)
+}
+
+// PostJSON posts the request body to the path as JSON and decodes the JSON response into resp
+// Timeouts, connection errors, 429s, and 5xxs are retried with backoff, other errors are returned right away
+func (c *Client) PostJSON(ctx context.Context, path string, body interface{}, resp interface{}) error {
+	tracer := otel.Tracer("sidecar")
+	ctx, span := tracer.Start(ctx, "Client:PostJSON")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("sidecar.service", c.Service),
+		attribute.String("sidecar.path", path),
+	)
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s request body: %w", c.Service, err)
+	}
+
+	for attempt := 1; ; attempt++ {
+		if !c.Breaker.Allow() {
+			sidecarRequestsCounter.WithLabelValues(c.Service, "unavailable").Inc()
+			span.SetAttributes(attribute.Bool("sidecar.unavailable", true))
+			if err != nil {
+				return fmt.Errorf("%s: %w (last error: %s)", c.Service, ErrUnavailable, err.Error())
+			}
+			return fmt.Errorf("%s: %w", c.Service, ErrUnavailable)
+		}
+
+		err = c.post(ctx, path, payload, resp)
+		switch {
+		case err == nil:
+			c.Breaker.Success()
+			sidecarUpGauge.WithLabelValues(c.Service).Set(1)
+			sidecarRequestsCounter.WithLabelValues(c.Service, "ok").Inc()
+			span.SetAttributes(attribute.Int("sidecar.attempts", attempt))
+			return nil
+		case ctx.Err() != nil:
+			// The caller gave up, that says nothing about the health of the service
+			c.Breaker.Release()
+			return ctx.Err()
+		case !retryable(err):
+			// The service is up but rejected the request, retrying won't help
+			c.Breaker.Success()
+			sidecarUpGauge.WithLabelValues(c.Service).Set(1)
+			sidecarRequestsCounter.WithLabelValues(c.Service, "rejected").Inc()
+			span.RecordError(err)
+			return fmt.Errorf("%s request rejected: %w", c.Service, err)
+		}
+
+		c.Breaker.Failure()
+		sidecarUpGauge.WithLabelValues(c.Service).Set(0)
+		sidecarRequestsCounter.WithLabelValues(c.Service, "error").Inc()
+		span.RecordError(err)
+
+		if attempt >= c.Options.MaxAttempts {
+			return fmt.Errorf("%s request failed after %d attempts: %w", c.Service, attempt, err)
+		}
+
+		sidecarRetriesCounter.WithLabelValues(c.Service).Inc()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.backoff(attempt)):
+		}
+	}
+}
+
+// post makes a single attempt at a request
+func (c *Client) post(ctx context.Context, path string, payload []byte, resp interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, c.Options.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	defer func() {
+		sidecarRequestDurationHistogram.WithLabelValues(c.Service).Observe(time.Since(start).Seconds())
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.Host+path, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		// Drain the body so the connection can be reused
+		_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+		return &StatusError{StatusCode: res.StatusCode}
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(resp); err != nil {
+		return fmt.Errorf("failed to decode response body: %w", err)
+	}
+
+	return nil
+}
+
+// backoff doubles from the base backoff with every attempt, capped at the max backoff
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.Options.BaseBackoff << (attempt - 1)
+	if delay > c.Options.MaxBackoff || delay <= 0 {
+		return c.Options.MaxBackoff
+	}
+	return delay
+}
+
+// retryable returns true for errors that might go away if the request is made again
+func retryable(err error) bool {
+	statusErr := &StatusError{}
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+	return true
+}
+
+// Batches splits items into consecutive batches of at most size items
+func Batches[T any](items []T, size int) [][]T {
+	if len(items) == 0 {
+		return nil
+	}
+	if size <= 0 {
+		size = len(items)
+	}
+
+	batches := make([][]T, 0, (len(items)+size-1)/size)
+	for start := 0; start < len(items); start += size {
+		end := start + size
+		if end > len(items) {
+			end = len(items)
+		}
+		batches = append(batches, items[start:end])
+	}
+
+	return batches
+}
@@ -0,0 +1,91 @@
+package sidecar
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testClient(t *testing.T, handler http.HandlerFunc) (*Client, *int32) {
+	calls := int32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient("test", server.URL, Options{
+		MaxAttempts:      3,
+		BaseBackoff:      time.Millisecond,
+		FailureThreshold: 5,
+		Cooldown:         time.Hour,
+	})
+
+	return client, &calls
+}
+
+func TestPostJSONRetries(t *testing.T) {
+	failures := int32(2)
+	client, calls := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&failures, -1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	})
+
+	resp := struct {
+		OK bool `json:"ok"`
+	}{}
+	err := client.PostJSON(context.Background(), "/analyze", []string{"a"}, &resp)
+	assert.NoError(t, err)
+	assert.True(t, resp.OK)
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+	assert.Equal(t, BreakerClosed, client.Breaker.State())
+}
+
+func TestPostJSONRejected(t *testing.T) {
+	client, calls := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	err := client.PostJSON(context.Background(), "/analyze", []string{"a"}, &struct{}{})
+	statusErr := &StatusError{}
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusBadRequest, statusErr.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestPostJSONOpensBreaker(t *testing.T) {
+	client, calls := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	// Five failed attempts open the breaker partway through the second request
+	err := client.PostJSON(context.Background(), "/analyze", []string{"a"}, &struct{}{})
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrUnavailable))
+
+	err = client.PostJSON(context.Background(), "/analyze", []string{"a"}, &struct{}{})
+	assert.True(t, errors.Is(err, ErrUnavailable))
+	assert.Equal(t, int32(5), atomic.LoadInt32(calls))
+	assert.False(t, client.Available())
+
+	// Requests fail fast while the breaker is open
+	err = client.PostJSON(context.Background(), "/analyze", []string{"a"}, &struct{}{})
+	assert.True(t, errors.Is(err, ErrUnavailable))
+	assert.Equal(t, int32(5), atomic.LoadInt32(calls))
+}
+
+func TestBatches(t *testing.T) {
+	assert.Nil(t, Batches([]int{}, 2))
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, Batches([]int{1, 2, 3, 4, 5}, 2))
+	assert.Equal(t, [][]int{{1, 2, 3}}, Batches([]int{1, 2, 3}, 10))
+	assert.Equal(t, [][]int{{1, 2, 3}}, Batches([]int{1, 2, 3}, 0))
+}
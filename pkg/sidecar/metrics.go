@@ -0,0 +1,32 @@
+package sidecar
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var sidecarRequestsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "sidecar_requests_total",
+	Help: "The total number of requests to sidecar services by result",
+}, []string{"service", "result"})
+
+var sidecarRetriesCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "sidecar_retries_total",
+	Help: "The total number of retried requests to sidecar services",
+}, []string{"service"})
+
+var sidecarRequestDurationHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "sidecar_request_duration_seconds",
+	Help:    "The time it takes a sidecar service to respond to a request",
+	Buckets: prometheus.ExponentialBuckets(0.01, 2, 15),
+}, []string{"service"})
+
+var sidecarBreakerStateGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "sidecar_breaker_state",
+	Help: "The circuit breaker state of a sidecar service, 0 is closed, 1 is half-open, and 2 is open",
+}, []string{"service"})
+
+var sidecarUpGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "sidecar_up",
+	Help: "Whether the last request to a sidecar service succeeded",
+}, []string{"service"})
@@ -0,0 +1,228 @@
+// Package topics labels posts with topics using keyword, hashtag, and regex rules.
+// Rules are loaded from a JSON config so topics can be added without a deploy of new code.
+package topics
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// LabelPrefix is prepended to a rule's name to make the post label it applies, i.e. "topic:space"
+const LabelPrefix = "topic:"
+
+var (
+	ruleNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	hashtagRegex  = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_&/])#([\p{L}\p{N}_]+)`)
+	digitsRegex   = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// Rule matches posts about a topic
+// A post matches if it contains any of the keywords as whole words, uses any of the hashtags,
+// or matches any of the regex patterns. Keywords and hashtags are case-insensitive, patterns are used as written
+type Rule struct {
+	Name     string   `json:"name"`
+	Keywords []string `json:"keywords"`
+	Hashtags []string `json:"hashtags"`
+	Patterns []string `json:"patterns"`
+
+	keywords *regexp.Regexp
+	hashtags map[string]struct{}
+	patterns []*regexp.Regexp
+}
+
+// Rules is a validated set of topic rules
+type Rules struct {
+	Rules []*Rule
+}
+
+// Label returns the post label for a topic
+func Label(name string) string {
+	return LabelPrefix + name
+}
+
+// NewRules validates and compiles the given rules
+func NewRules(rules []*Rule) (*Rules, error) {
+	names := map[string]struct{}{}
+
+	for _, rule := range rules {
+		if !ruleNameRegex.MatchString(rule.Name) {
+			return nil, fmt.Errorf("topic rule name %q must be lowercase letters, numbers, dashes, and underscores", rule.Name)
+		}
+		if _, ok := names[rule.Name]; ok {
+			return nil, fmt.Errorf("topic rule %s is defined more than once", rule.Name)
+		}
+		names[rule.Name] = struct{}{}
+
+		if len(rule.Keywords) == 0 && len(rule.Hashtags) == 0 && len(rule.Patterns) == 0 {
+			return nil, fmt.Errorf("topic rule %s has no keywords, hashtags, or patterns", rule.Name)
+		}
+
+		if err := rule.compile(); err != nil {
+			return nil, fmt.Errorf("topic rule %s is invalid: %w", rule.Name, err)
+		}
+	}
+
+	return &Rules{Rules: rules}, nil
+}
+
+// LoadRules reads a JSON list of topic rules, i.e.
+// [{"name": "space", "keywords": ["nasa", "rocket launch"], "hashtags": ["space"], "patterns": ["(?i)\\bjwst\\b"]}]
+func LoadRules(r io.Reader) (*Rules, error) {
+	rules := []*Rule{}
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&rules); err != nil {
+		return nil, fmt.Errorf("failed to decode topic rules: %w", err)
+	}
+
+	return NewRules(rules)
+}
+
+// LoadRulesFromFile reads a JSON list of topic rules from the given path
+func LoadRulesFromFile(path string) (*Rules, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open topic rules file: %w", err)
+	}
+	defer file.Close()
+
+	return LoadRules(file)
+}
+
+// Hashtags returns the distinct lowercased hashtags of a post without their leading "#"
+// Tag facets don't have to cover a "#tag" in the text, so the tags from the post's facets are merged with the ones in its text
+func Hashtags(text string, facetTags []string) []string {
+	tags := []string{}
+	seen := map[string]struct{}{}
+
+	add := func(tag string) {
+		if _, ok := seen[tag]; ok {
+			return
+		}
+		seen[tag] = struct{}{}
+		tags = append(tags, tag)
+	}
+
+	for _, match := range hashtagRegex.FindAllStringSubmatch(text, -1) {
+		tag := strings.ToLower(match[1])
+		if digitsRegex.MatchString(tag) {
+			continue
+		}
+		add(tag)
+	}
+
+	for _, facetTag := range facetTags {
+		if tag := normalizeHashtag(facetTag); tag != "" {
+			add(tag)
+		}
+	}
+
+	return tags
+}
+
+// Match returns the names of the topics a post's text or tag facets match, in rule order
+func (r *Rules) Match(text string, facetTags []string) []string {
+	hashtags := Hashtags(text, facetTags)
+
+	topics := []string{}
+	for _, rule := range r.Rules {
+		if rule.Matches(text, hashtags) {
+			topics = append(topics, rule.Name)
+		}
+	}
+
+	return topics
+}
+
+// Matches reports whether a post's text or hashtags match the rule
+func (rule *Rule) Matches(text string, hashtags []string) bool {
+	for _, tag := range hashtags {
+		if _, ok := rule.hashtags[tag]; ok {
+			return true
+		}
+	}
+
+	if rule.keywords != nil && rule.keywords.MatchString(text) {
+		return true
+	}
+
+	for _, pattern := range rule.patterns {
+		if pattern.MatchString(text) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Fingerprint identifies what a rule matches, it changes when the rule is edited but not when its lists are reordered
+func (rule *Rule) Fingerprint() string {
+	normalize := func(values []string) []string {
+		normalized := make([]string, len(values))
+		copy(normalized, values)
+		sort.Strings(normalized)
+		return normalized
+	}
+
+	// Marshalling a struct of strings can't fail
+	b, _ := json.Marshal([]interface{}{
+		rule.Name,
+		normalize(rule.Keywords),
+		normalize(rule.Hashtags),
+		normalize(rule.Patterns),
+	})
+
+	h := fnv.New64a()
+	h.Write(b)
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+func (rule *Rule) compile() error {
+	if len(rule.Keywords) > 0 {
+		alternatives := make([]string, len(rule.Keywords))
+		for i, keyword := range rule.Keywords {
+			words := strings.Fields(keyword)
+			if len(words) == 0 {
+				return fmt.Errorf("keyword %d is empty", i)
+			}
+			for j, word := range words {
+				words[j] = regexp.QuoteMeta(word)
+			}
+			alternatives[i] = strings.Join(words, `\s+`)
+		}
+
+		// Keywords only match whole words so "art" doesn't match "start"
+		rule.keywords = regexp.MustCompile(`(?i)(?:^|[^\p{L}\p{N}_])(?:` + strings.Join(alternatives, "|") + `)(?:$|[^\p{L}\p{N}_])`)
+	}
+
+	rule.hashtags = map[string]struct{}{}
+	for i, hashtag := range rule.Hashtags {
+		tag := normalizeHashtag(hashtag)
+		if tag == "" {
+			return fmt.Errorf("hashtag %d is empty", i)
+		}
+		rule.hashtags[tag] = struct{}{}
+	}
+
+	rule.patterns = make([]*regexp.Regexp, len(rule.Patterns))
+	for i, pattern := range rule.Patterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("pattern %q doesn't compile: %w", pattern, err)
+		}
+		rule.patterns[i] = compiled
+	}
+
+	return nil
+}
+
+// normalizeHashtag lowercases a hashtag and trims its leading "#"
+func normalizeHashtag(hashtag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(hashtag), "#"))
+}
@@ -0,0 +1,109 @@
+package topics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadRules(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{
+			name:   "Valid rules",
+			config: `[{"name": "space", "keywords": ["nasa"], "hashtags": ["#Space"]}, {"name": "ai", "patterns": ["(?i)\\bllms?\\b"]}]`,
+		},
+		{
+			name:    "Bad name",
+			config:  `[{"name": "Outer Space", "keywords": ["nasa"]}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Duplicate name",
+			config:  `[{"name": "space", "keywords": ["nasa"]}, {"name": "space", "hashtags": ["space"]}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Nothing to match",
+			config:  `[{"name": "space"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Empty keyword",
+			config:  `[{"name": "space", "keywords": [" "]}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Bad pattern",
+			config:  `[{"name": "space", "patterns": ["(nasa"]}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Unknown field",
+			config:  `[{"name": "space", "keyword": ["nasa"]}]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := LoadRules(strings.NewReader(tc.config))
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHashtags(t *testing.T) {
+	assert.Equal(t, []string{"space", "jwst"}, Hashtags("#Space pics from #JWST! #space #1 https://example.com/#anchor a#b", nil))
+	assert.Equal(t, []string{"café"}, Hashtags("(#Café)", nil))
+	assert.Empty(t, Hashtags("no tags here", nil))
+	assert.Equal(t, []string{"space", "jwst", "astronomy"}, Hashtags("#space pics", []string{"JWST", "#Space", "astronomy", " "}))
+}
+
+func TestMatch(t *testing.T) {
+	rules, err := LoadRules(strings.NewReader(`[
+		{"name": "space", "keywords": ["nasa", "rocket launch"], "hashtags": ["space"]},
+		{"name": "art", "keywords": ["art"], "hashtags": ["#ArtistsOnBsky"]},
+		{"name": "ai", "patterns": ["(?i)\\bllms?\\b"]}
+	]`))
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		text string
+		tags []string
+		want []string
+	}{
+		{text: "Watching the NASA stream", want: []string{"space"}},
+		{text: "another rocket\nlaunch today", want: []string{"space"}},
+		{text: "just started a new job", want: []string{}},
+		{text: "new art! #artistsonbsky", want: []string{"art"}},
+		{text: "#Space art made with LLMs", want: []string{"space", "art", "ai"}},
+		{text: "spacecraft and nasal spray", want: []string{}},
+		{text: "look at this", tags: []string{"Space"}, want: []string{"space"}},
+		{text: "new piece", tags: []string{"#artistsonbsky"}, want: []string{"art"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.text, func(t *testing.T) {
+			assert.Equal(t, tc.want, rules.Match(tc.text, tc.tags))
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a := &Rule{Name: "space", Keywords: []string{"nasa", "esa"}, Hashtags: []string{"space"}}
+	b := &Rule{Name: "space", Keywords: []string{"esa", "nasa"}, Hashtags: []string{"space"}}
+	c := &Rule{Name: "space", Keywords: []string{"nasa", "esa", "jaxa"}, Hashtags: []string{"space"}}
+
+	assert.Equal(t, a.Fingerprint(), b.Fingerprint())
+	assert.NotEqual(t, a.Fingerprint(), c.Fingerprint())
+	assert.Equal(t, "topic:space", Label(a.Name))
+}
@@ -19,14 +19,16 @@ def preprocess(text):
     return " ".join(new_text)
 
 
-MODEL = f"cardiffnlp/twitter-roberta-base-sentiment-latest"
+# Any model with negative/neutral/positive labels works, i.e. a multilingual model
+# like cardiffnlp/twitter-xlm-roberta-base-sentiment for the fallback route
+MODEL = os.getenv("SENTIMENT_MODEL", "cardiffnlp/twitter-roberta-base-sentiment-latest")
 
 tokenizer = AutoTokenizer.from_pretrained(MODEL)
 config = AutoConfig.from_pretrained(MODEL)
 device = "cuda:0" if torch.cuda.is_available() else "cpu"
 
 model = AutoModelForSequenceClassification.from_pretrained(MODEL).to(device)
-model.save_pretrained(os.getcwd() + "/cardiffnlp/twitter-roberta-base-sentiment-latest")
+model.save_pretrained(os.path.join(os.getcwd(), MODEL))
 
 
 def get_sentiment(post_text):